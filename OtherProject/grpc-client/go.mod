@@ -4,4 +4,15 @@ go 1.25.5
 
 require github.com/jlambert68/MQDockerContainer2/mq-gateway v0.0.0-20260108143124-a4b9ddc475e0
 
+require (
+	github.com/grpc-ecosystem/grpc-gateway/v2 v2.27.7 // indirect
+	golang.org/x/net v0.47.0 // indirect
+	golang.org/x/sys v0.38.0 // indirect
+	golang.org/x/text v0.33.0 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20260128011058-8636f8732409 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260128011058-8636f8732409 // indirect
+	google.golang.org/grpc v1.78.0 // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
+)
+
 replace github.com/jlambert68/MQDockerContainer2/mq-gateway => ../../mq-gateway
@@ -0,0 +1,138 @@
+package grpcsrv
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/api/proto/mq_grpc_api"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/audit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+// archiveChunkSize is the largest ArchiveChunk sent by ExportQueue.
+const archiveChunkSize = 64 * 1024
+
+// chunkSender turns archive writes into ArchiveChunk messages.
+type chunkSender struct {
+	stream grpc.ServerStreamingServer[mq_grpc_api.ArchiveChunk]
+}
+
+func (c chunkSender) Write(p []byte) (int, error) {
+	if err := c.stream.Send(&mq_grpc_api.ArchiveChunk{Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ExportQueue streams a queue archive. Unlike the unary calls, failures are
+// returned as gRPC status errors, since the stream may already carry data.
+func (s *Server) ExportQueue(req *mq_grpc_api.ExportQueueRequest, stream grpc.ServerStreamingServer[mq_grpc_api.ArchiveChunk]) error {
+	ctx := stream.Context()
+	if req.GetQueue() == "" {
+		return status.Error(codes.InvalidArgument, "queue required")
+	}
+	format, err := mqcore.ParseArchiveFormat(req.GetFormat())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.admit(ctx, auth.OpAdmin, req.GetQueue()); err != nil {
+		return err
+	}
+
+	buf := bufio.NewWriterSize(chunkSender{stream}, archiveChunkSize)
+	aw, err := mqcore.NewArchiveWriter(format, buf)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	n, err := s.GW.ExportQueue(req.GetQueue(), aw, mqcore.ExportOptions{
+		Destructive: req.GetDestructive(),
+		MaxMessages: int(req.GetMaxMessages()),
+		MaxBytes:    int(req.GetMaxMsgBytes()),
+	})
+	if err == nil {
+		err = aw.Close()
+	}
+	if err == nil {
+		err = buf.Flush()
+	}
+	s.record(ctx, audit.Record{Operation: audit.OpExport, Queue: req.GetQueue(), Count: n}, err)
+	if err != nil {
+		slog.Error("[gRPC] ExportQueue error",
+			"error", err,
+			"queue", req.GetQueue(),
+			"exported", n,
+			"id", "05d8559f-3a08-47e9-b0d1-092abaa0f100")
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return nil
+}
+
+// chunkReader reads the archive bytes carried by ImportQueueRequest messages.
+type chunkReader struct {
+	stream grpc.ClientStreamingServer[mq_grpc_api.ImportQueueRequest, mq_grpc_api.ImportQueueResponse]
+	buf    []byte
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	for len(c.buf) == 0 {
+		req, err := c.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		c.buf = req.GetData()
+	}
+	n := copy(p, c.buf)
+	c.buf = c.buf[n:]
+	return n, nil
+}
+
+// ImportQueue re-puts the messages of an archive streamed by the client. The
+// first message names the queue and options and may already carry data.
+func (s *Server) ImportQueue(stream grpc.ClientStreamingServer[mq_grpc_api.ImportQueueRequest, mq_grpc_api.ImportQueueResponse]) error {
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return status.Error(codes.InvalidArgument, "empty import stream")
+	}
+	if err != nil {
+		return err
+	}
+	if first.GetQueue() == "" {
+		return status.Error(codes.InvalidArgument, "queue required")
+	}
+	format, err := mqcore.ParseArchiveFormat(first.GetFormat())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.admit(ctx, auth.OpAdmin, first.GetQueue()); err != nil {
+		return err
+	}
+
+	ar, err := mqcore.NewArchiveReader(format, &chunkReader{stream: stream, buf: first.GetData()})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	n, err := s.GW.ImportQueue(first.GetQueue(), ar, mqcore.ImportOptions{
+		SetAllContext: first.GetSetAllContext(),
+		NewMsgID:      first.GetNewMsgId(),
+	})
+	s.record(ctx, audit.Record{Operation: audit.OpImport, Queue: first.GetQueue(), Count: n}, err)
+	if err != nil {
+		slog.Error("[gRPC] ImportQueue error",
+			"error", err,
+			"queue", first.GetQueue(),
+			"imported", n,
+			"id", "c0997017-de51-445c-a27e-100a5106a9f5")
+		return stream.SendAndClose(&mq_grpc_api.ImportQueueResponse{Status: "error", Imported: int32(n), Error: err.Error()})
+	}
+	return stream.SendAndClose(&mq_grpc_api.ImportQueueResponse{Status: "ok", Imported: int32(n)})
+}
@@ -0,0 +1,126 @@
+package grpcsrv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/api/proto/mq_grpc_api"
+)
+
+// Gateway returns an HTTP handler that serves the google.api.http bindings
+// in mq.proto by calling s in-process. JSON uses the proto field names, so
+// the REST and gRPC APIs share one schema. Authentication is left to the
+// HTTP middleware wrapped around the handler.
+func (s *Server) Gateway() (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+		}),
+		runtime.WithIncomingHeaderMatcher(incomingHeader),
+		runtime.WithMetadata(remoteAddr),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeader),
+		runtime.WithForwardResponseOption(errorStatus),
+		runtime.WithRoutingErrorHandler(routingError),
+	)
+	if err := mq_grpc_api.RegisterMqGrpcServicesHandlerServer(context.Background(), mux, s); err != nil {
+		return nil, err
+	}
+	return pathQueue(mux), nil
+}
+
+// pathQueue rejects a body whose queue differs from the queue in the path.
+// The gateway fills the request from the body first and then the path, so
+// the mismatch would otherwise be dropped silently.
+func pathQueue(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.EscapedPath(), "/v2/queues/")
+		if !ok || r.Body == nil || r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		segment, _, _ := strings.Cut(rest, "/")
+		queue, err := url.PathUnescape(segment)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "reading body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(raw))
+		var body struct {
+			Queue string `json:"queue"`
+		}
+		if json.Unmarshal(raw, &body) == nil && body.Queue != "" && !strings.EqualFold(body.Queue, queue) {
+			http.Error(w, "queue in body does not match the path", http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// remoteAddrKey carries the HTTP client's address to the in-process call,
+// which has no gRPC peer.
+const remoteAddrKey = "x-mq-gateway-remote-addr"
+
+// remoteAddr passes the connection's RemoteAddr, in the same host:port form
+// v1 audits, rather than X-Forwarded-For, which starts with whatever the
+// caller sent.
+func remoteAddr(ctx context.Context, r *http.Request) metadata.MD {
+	return metadata.Pairs(remoteAddrKey, r.RemoteAddr)
+}
+
+// incomingHeader forwards Idempotency-Key as metadata for Put, in addition
+// to the headers the gateway forwards by default. Callers cannot set the
+// remote address key through a Grpc-Metadata- header.
+func incomingHeader(key string) (string, bool) {
+	if strings.EqualFold(key, "Idempotency-Key") {
+		return "idempotency-key", true
+	}
+	if strings.EqualFold(key, runtime.MetadataHeaderPrefix+remoteAddrKey) {
+		return "", false
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeader passes Retry-After through unprefixed so REST clients see
+// the same back-off hint as v1; other gRPC headers keep the default prefix.
+func outgoingHeader(key string) (string, bool) {
+	if strings.EqualFold(key, "retry-after") {
+		return "Retry-After", true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// errorStatus maps responses with status "error" to 502, matching how v1
+// reports MQ failures. Invalid requests never get this far: the RPCs reject
+// them with InvalidArgument, which the gateway answers with 400.
+func errorStatus(ctx context.Context, w http.ResponseWriter, m proto.Message) error {
+	f := m.ProtoReflect().Descriptor().Fields().ByName("status")
+	if f != nil && m.ProtoReflect().Get(f).String() == "error" {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	return nil
+}
+
+// routingError answers a known path with the wrong method with 405 instead
+// of the gateway's default 501.
+func routingError(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, code int) {
+	if code == http.StatusMethodNotAllowed {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	runtime.DefaultRoutingErrorHandler(ctx, mux, m, w, r, code)
+}
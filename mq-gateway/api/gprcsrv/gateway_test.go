@@ -0,0 +1,93 @@
+package grpcsrv
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/api/proto/mq_grpc_api"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+)
+
+func TestRemoteAddrMetadata(t *testing.T) {
+	r := httptest.NewRequest("GET", "/v2/queues/Q", nil)
+	r.RemoteAddr = "10.0.0.7:51234"
+	r.Header.Set("X-Forwarded-For", "1.2.3.4")
+	if got := remoteAddr(context.Background(), r).Get(remoteAddrKey); len(got) != 1 || got[0] != "10.0.0.7:51234" {
+		t.Fatalf("remote address metadata = %v", got)
+	}
+
+	// Clients cannot supply the key themselves.
+	if _, ok := incomingHeader(runtime.MetadataHeaderPrefix + "X-Mq-Gateway-Remote-Addr"); ok {
+		t.Fatal("remote address header forwarded from the client")
+	}
+	if key, ok := incomingHeader("Idempotency-Key"); !ok || key != "idempotency-key" {
+		t.Fatalf("Idempotency-Key forwarded as %q, %v", key, ok)
+	}
+}
+
+func TestInvalidArgument(t *testing.T) {
+	// Requests rejected before MQ is reached, so a zero Server is enough.
+	s := &Server{}
+	ctx := context.Background()
+	for name, call := range map[string]func() error{
+		"put without queue": func() error { _, err := s.Put(ctx, &mq_grpc_api.PutRequest{}); return err },
+		"put with bad group_id": func() error {
+			_, err := s.Put(ctx, &mq_grpc_api.PutRequest{Queue: "Q", GroupId: "zz"})
+			return err
+		},
+		"put with long idempotency key": func() error {
+			_, err := s.Put(ctx, &mq_grpc_api.PutRequest{Queue: "Q", IdempotencyKey: strings.Repeat("k", 1000)})
+			return err
+		},
+		"get with bad correl_id": func() error {
+			_, err := s.Get(ctx, &mq_grpc_api.GetRequest{Queue: "Q", CorrelId: "zz"})
+			return err
+		},
+		"group without messages": func() error {
+			_, err := s.PutGroup(ctx, &mq_grpc_api.PutGroupRequest{Queue: "Q"})
+			return err
+		},
+		"batch without max_messages": func() error {
+			_, err := s.GetBatch(ctx, &mq_grpc_api.GetBatchRequest{Queue: "Q"})
+			return err
+		},
+		"browse without cursor": func() error { _, err := s.BrowseNext(ctx, &mq_grpc_api.BrowseNextRequest{}); return err },
+		"schedule without due time": func() error {
+			_, err := s.ScheduleMessage(ctx, &mq_grpc_api.ScheduleRequest{Queue: "Q"})
+			return err
+		},
+		"cancel with bad schedule_id": func() error {
+			_, err := s.CancelScheduled(ctx, &mq_grpc_api.CancelScheduledRequest{Queue: "Q", ScheduleId: "zz"})
+			return err
+		},
+	} {
+		if code := status.Code(call()); code != codes.InvalidArgument {
+			t.Errorf("%s: %v, want InvalidArgument", name, code)
+		}
+	}
+}
+
+func TestDLQResolveFailure(t *testing.T) {
+	// A DEADQ that cannot be looked up fails the call before authorization;
+	// the zero gateway behind s would fault if BrowseDLQ or ReplayDLQ ran.
+	s := &Server{
+		Authz:      &auth.Policy{},
+		resolveDLQ: func(string) (string, error) { return "", errors.New("MQINQ(DEADQ): MQRC_CONNECTION_BROKEN") },
+	}
+	ctx := context.Background()
+	if _, err := s.BrowseDLQ(ctx, &mq_grpc_api.DLQBrowseRequest{}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("BrowseDLQ: %v, want Unavailable", err)
+	}
+	for _, dry := range []bool{false, true} {
+		if _, err := s.ReplayDLQ(ctx, &mq_grpc_api.DLQReplayRequest{DryRun: dry}); status.Code(err) != codes.Unavailable {
+			t.Fatalf("ReplayDLQ(dry_run=%v): %v, want Unavailable", dry, err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package grpcsrv
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// LoggingUnaryInterceptor logs every unary RPC with its status code and
+// duration. Chain it before authentication so rejected calls are logged too.
+func LoggingUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// LoggingStreamInterceptor logs every streaming RPC when it ends.
+func LoggingStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(ss.Context(), info.FullMethod, start, err)
+		return err
+	}
+}
+
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	attrs := []any{
+		"method", method,
+		"code", code.String(),
+		"duration_ms", time.Since(start).Milliseconds(),
+	}
+	if pr, ok := peer.FromContext(ctx); ok {
+		attrs = append(attrs, "remote_addr", pr.Addr.String())
+	}
+	if code == codes.OK {
+		slog.Info("[gRPC] call", append(attrs, "id", "b97418c5-3400-4f6c-8a96-d34c97e6af02")...)
+		return
+	}
+	slog.Warn("[gRPC] call failed", append(attrs, "error", err, "id", "1a2968f2-d900-4c75-9b59-70f4af8a1e70")...)
+}
+
+// RecoveryUnaryInterceptor turns a panicking handler into codes.Internal
+// instead of crashing the gateway. Chain it first so it covers the others.
+func RecoveryUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStreamInterceptor is RecoveryUnaryInterceptor for streaming RPCs.
+func RecoveryStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func recovered(method string, r any) error {
+	slog.Error("[gRPC] handler panicked",
+		"method", method,
+		"panic", r,
+		"stack", string(debug.Stack()),
+		"id", "1d3f3ff5-1df0-463d-9fd1-ecccda01528b")
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,173 @@
+package grpcsrv
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// defaultMaxMsgSize leaves room for MQ's 100 MiB maximum message length plus
+// the surrounding fields; gRPC's own 4 MiB default rejects large messages.
+const defaultMaxMsgSize = 101 << 20
+
+// Options configures the gRPC listener. A zero value keeps gRPC's default,
+// which for the connection idle and age limits means no limit.
+type Options struct {
+	// MaxRecvMsgSize and MaxSendMsgSize bound a single message in bytes; 0
+	// keeps gRPC's default of 4 MiB received and unlimited sent.
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+	// MaxConcurrentStreams limits in-flight RPCs per connection; 0 keeps gRPC's default.
+	MaxConcurrentStreams uint32
+	// KeepaliveTime is how long a connection may be idle before the server
+	// pings it; KeepaliveTimeout is how long it then waits for the ack.
+	KeepaliveTime    time.Duration
+	KeepaliveTimeout time.Duration
+	// KeepaliveMinTime is the shortest ping interval a client may use; clients
+	// that ping more often are disconnected.
+	KeepaliveMinTime time.Duration
+	// PermitWithoutStream lets clients ping while no RPC is active, as idle
+	// long-poll clients do.
+	PermitWithoutStream bool
+	// MaxConnectionIdle closes connections without RPCs after this long.
+	MaxConnectionIdle time.Duration
+	// MaxConnectionAge closes connections after this long, giving in-flight
+	// RPCs MaxConnectionAgeGrace to finish; keep the grace above the longest
+	// wait_ms clients use.
+	MaxConnectionAge      time.Duration
+	MaxConnectionAgeGrace time.Duration
+	// Reflection registers the server reflection service for tools such as grpcurl.
+	Reflection bool
+}
+
+func getint(key string, def int) (int, error) {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s: want a non-negative integer, got %q", key, v)
+	}
+	return n, nil
+}
+
+func getduration(key string, def time.Duration) (time.Duration, error) {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s: want a non-negative duration such as 30s, got %q", key, v)
+	}
+	return d, nil
+}
+
+func getbool(key string, def bool) (bool, error) {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return def, nil
+	}
+	switch strings.ToLower(v) {
+	case "1", "true", "yes", "y", "on":
+		return true, nil
+	case "0", "false", "no", "n", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("%s: want true or false, got %q", key, v)
+}
+
+// OptionsFromEnv reads the listener options from environment variables:
+//
+//	GRPC_MAX_RECV_MSG_BYTES               largest message the server accepts (default 101 MiB, 0 for gRPC's 4 MiB)
+//	GRPC_MAX_SEND_MSG_BYTES               largest message the server sends (default 101 MiB, 0 for unlimited)
+//	GRPC_MAX_CONCURRENT_STREAMS           in-flight RPCs per connection (default unlimited)
+//	GRPC_KEEPALIVE_TIME                   ping idle connections after this long (default 1m)
+//	GRPC_KEEPALIVE_TIMEOUT                close a connection whose ping is not acked in time (default 20s)
+//	GRPC_KEEPALIVE_MIN_TIME               shortest ping interval allowed from clients (default 30s)
+//	GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM  allow client pings without active RPCs (default true)
+//	GRPC_MAX_CONNECTION_IDLE              close connections idle this long (default never)
+//	GRPC_MAX_CONNECTION_AGE               close connections older than this (default never)
+//	GRPC_MAX_CONNECTION_AGE_GRACE         time in-flight RPCs get after that (default unlimited)
+//	GRPC_REFLECTION                       register server reflection (default true)
+//
+// The keepalive defaults keep connections open through NATs and load
+// balancers that drop flows after a few idle minutes.
+func OptionsFromEnv() (Options, error) {
+	var o Options
+	var err error
+	if o.MaxRecvMsgSize, err = getint("GRPC_MAX_RECV_MSG_BYTES", defaultMaxMsgSize); err != nil {
+		return o, err
+	}
+	if o.MaxSendMsgSize, err = getint("GRPC_MAX_SEND_MSG_BYTES", defaultMaxMsgSize); err != nil {
+		return o, err
+	}
+	streams, err := getint("GRPC_MAX_CONCURRENT_STREAMS", 0)
+	if err != nil {
+		return o, err
+	}
+	if streams > math.MaxUint32 {
+		return o, fmt.Errorf("GRPC_MAX_CONCURRENT_STREAMS: %d is too large", streams)
+	}
+	o.MaxConcurrentStreams = uint32(streams)
+
+	durations := []struct {
+		key string
+		def time.Duration
+		dst *time.Duration
+	}{
+		{"GRPC_KEEPALIVE_TIME", time.Minute, &o.KeepaliveTime},
+		{"GRPC_KEEPALIVE_TIMEOUT", 20 * time.Second, &o.KeepaliveTimeout},
+		{"GRPC_KEEPALIVE_MIN_TIME", 30 * time.Second, &o.KeepaliveMinTime},
+		{"GRPC_MAX_CONNECTION_IDLE", 0, &o.MaxConnectionIdle},
+		{"GRPC_MAX_CONNECTION_AGE", 0, &o.MaxConnectionAge},
+		{"GRPC_MAX_CONNECTION_AGE_GRACE", 0, &o.MaxConnectionAgeGrace},
+	}
+	for _, d := range durations {
+		if *d.dst, err = getduration(d.key, d.def); err != nil {
+			return o, err
+		}
+	}
+	if o.PermitWithoutStream, err = getbool("GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM", true); err != nil {
+		return o, err
+	}
+	if o.Reflection, err = getbool("GRPC_REFLECTION", true); err != nil {
+		return o, err
+	}
+	return o, nil
+}
+
+// ServerOptions turns o into grpc.NewServer options. Credentials and
+// interceptors are added by the caller.
+func (o Options) ServerOptions() []grpc.ServerOption {
+	opts := []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle:     o.MaxConnectionIdle,
+			MaxConnectionAge:      o.MaxConnectionAge,
+			MaxConnectionAgeGrace: o.MaxConnectionAgeGrace,
+			Time:                  o.KeepaliveTime,
+			Timeout:               o.KeepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             o.KeepaliveMinTime,
+			PermitWithoutStream: o.PermitWithoutStream,
+		}),
+	}
+	if o.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(o.MaxRecvMsgSize))
+	}
+	if o.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(o.MaxSendMsgSize))
+	}
+	if o.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(o.MaxConcurrentStreams))
+	}
+	return opts
+}
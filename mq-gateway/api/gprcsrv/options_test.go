@@ -0,0 +1,90 @@
+package grpcsrv
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOptionsFromEnvDefaults(t *testing.T) {
+	o, err := OptionsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.MaxRecvMsgSize != defaultMaxMsgSize || o.MaxSendMsgSize != defaultMaxMsgSize {
+		t.Errorf("message sizes = %d/%d, want %d", o.MaxRecvMsgSize, o.MaxSendMsgSize, defaultMaxMsgSize)
+	}
+	if o.KeepaliveTime != time.Minute || !o.PermitWithoutStream || !o.Reflection {
+		t.Errorf("defaults = %+v", o)
+	}
+	if o.MaxConnectionAge != 0 || o.MaxConcurrentStreams != 0 {
+		t.Errorf("connection limits set by default: %+v", o)
+	}
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	t.Setenv("GRPC_MAX_RECV_MSG_BYTES", "1048576")
+	t.Setenv("GRPC_MAX_CONCURRENT_STREAMS", "64")
+	t.Setenv("GRPC_MAX_CONNECTION_AGE", "30m")
+	t.Setenv("GRPC_MAX_CONNECTION_AGE_GRACE", "2m")
+	t.Setenv("GRPC_REFLECTION", "off")
+	o, err := OptionsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.MaxRecvMsgSize != 1<<20 || o.MaxConcurrentStreams != 64 ||
+		o.MaxConnectionAge != 30*time.Minute || o.MaxConnectionAgeGrace != 2*time.Minute || o.Reflection {
+		t.Errorf("options = %+v", o)
+	}
+	if got := len(o.ServerOptions()); got != 5 {
+		t.Errorf("ServerOptions returned %d options, want 5", got)
+	}
+}
+
+func TestZeroOptionsKeepGRPCDefaults(t *testing.T) {
+	// Only the keepalive settings are passed; a 0-byte message limit would
+	// reject every request.
+	if got := len((Options{}).ServerOptions()); got != 2 {
+		t.Errorf("ServerOptions of a zero Options returned %d options, want 2", got)
+	}
+	t.Setenv("GRPC_MAX_RECV_MSG_BYTES", "0")
+	t.Setenv("GRPC_MAX_SEND_MSG_BYTES", "0")
+	o, err := OptionsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(o.ServerOptions()); got != 2 {
+		t.Errorf("ServerOptions with 0-byte limits returned %d options, want 2", got)
+	}
+}
+
+func TestOptionsFromEnvRejectsBadValues(t *testing.T) {
+	for key, value := range map[string]string{
+		"GRPC_MAX_SEND_MSG_BYTES":              "-1",
+		"GRPC_MAX_CONCURRENT_STREAMS":          "many",
+		"GRPC_KEEPALIVE_TIME":                  "60",
+		"GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM": "maybe",
+	} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, value)
+			if _, err := OptionsFromEnv(); err == nil || !strings.Contains(err.Error(), key) {
+				t.Errorf("%s=%s: err = %v, want an error naming the variable", key, value, err)
+			}
+		})
+	}
+}
+
+func TestRecoveryUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/mqpb.MqGrpcServices/Put"}
+	_, err := RecoveryUnaryInterceptor()(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		panic("boom")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("err = %v, want codes.Internal", err)
+	}
+}
@@ -0,0 +1,151 @@
+package grpcsrv
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/api/proto/mq_grpc_api"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/audit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+// scheduleStatus maps the scheduler's sentinel errors to gRPC codes; other
+// errors are reported in the response body like every other MQ error.
+func scheduleStatus(err error) error {
+	switch {
+	case errors.Is(err, mqcore.ErrSchedulingDisabled):
+		return status.Error(codes.Unimplemented, err.Error())
+	case errors.Is(err, mqcore.ErrScheduleNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return nil
+}
+
+func (s *Server) ScheduleMessage(ctx context.Context, req *mq_grpc_api.ScheduleRequest) (*mq_grpc_api.ScheduleResponse, error) {
+	// Validate request early to keep MQ errors clean.
+	if req.GetQueue() == "" {
+		return nil, status.Error(codes.InvalidArgument, "queue required")
+	}
+	dueAt, err := mqcore.DueTime(req.GetDeliverAt(), int64(req.GetDelayMs()), time.Now())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Scheduling is a deferred put to the target queue.
+	if err := s.admit(ctx, auth.OpPut, req.GetQueue()); err != nil {
+		return nil, err
+	}
+
+	id, err := s.GW.Schedule(req.GetQueue(), req.GetMessage(), dueAt, mqcore.PutOptions{
+		ReplyToQueue: req.GetReplyToQueue(),
+	})
+	s.record(ctx, audit.Record{
+		Operation: audit.OpSchedule,
+		Queue:     req.GetQueue(),
+		MsgID:     mqcore.FormatID(id),
+	}.WithPayload(req.GetMessage()), err)
+	if serr := scheduleStatus(err); serr != nil {
+		return nil, serr
+	}
+	if serr := invalidPayload(err); serr != nil {
+		return nil, serr
+	}
+	if err != nil {
+		slog.Error("[gRPC] ScheduleMessage error",
+			"error", err,
+			"id", "29db0c0d-2b18-4804-aa8e-935a8c613dc5")
+		return &mq_grpc_api.ScheduleResponse{
+			Status: "error",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	return &mq_grpc_api.ScheduleResponse{
+		Status:     "ok",
+		ScheduleId: mqcore.FormatID(id),
+		DeliverAt:  dueAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func (s *Server) ListScheduled(ctx context.Context, req *mq_grpc_api.ListScheduledRequest) (*mq_grpc_api.ListScheduledResponse, error) {
+	// ListScheduled browses the pending messages for one target queue.
+	if req.GetQueue() == "" {
+		return nil, status.Error(codes.InvalidArgument, "queue required")
+	}
+
+	if err := s.admit(ctx, auth.OpBrowse, req.GetQueue()); err != nil {
+		return nil, err
+	}
+
+	sms, err := s.GW.ListScheduled(req.GetQueue(), int(req.GetMaxMessages()), int(req.GetMaxMsgBytes()))
+	for _, sm := range sms {
+		s.record(ctx, audit.Record{Operation: audit.OpScheduleList, Queue: sm.Queue, MsgID: mqcore.FormatID(sm.ID)}.WithPayload(sm.Payload), nil)
+	}
+	if err != nil && len(sms) == 0 {
+		s.record(ctx, audit.Record{Operation: audit.OpScheduleList, Queue: req.GetQueue()}, err)
+	}
+	if serr := scheduleStatus(err); serr != nil {
+		return nil, serr
+	}
+	resp := &mq_grpc_api.ListScheduledResponse{Status: "ok"}
+	for _, sm := range sms {
+		resp.Messages = append(resp.Messages, &mq_grpc_api.ScheduledMessage{
+			ScheduleId:   mqcore.FormatID(sm.ID),
+			Queue:        sm.Queue,
+			DeliverAt:    sm.DueAt.UTC().Format(time.RFC3339),
+			ReplyToQueue: sm.ReplyToQueue,
+			Message:      sm.Payload,
+		})
+	}
+	if err != nil {
+		slog.Error("[gRPC] ListScheduled error",
+			"error", err,
+			"id", "e6e56a52-4b42-47f1-943f-4353a5cefedf")
+		resp.Status = "error"
+		resp.Error = err.Error()
+	}
+	return resp, nil
+}
+
+func (s *Server) CancelScheduled(ctx context.Context, req *mq_grpc_api.CancelScheduledRequest) (*mq_grpc_api.CancelScheduledResponse, error) {
+	// CancelScheduled removes a pending message before it is delivered.
+	if req.GetQueue() == "" || req.GetScheduleId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "queue and schedule_id required")
+	}
+	id, err := mqcore.ParseID(req.GetScheduleId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "schedule_id: "+err.Error())
+	}
+
+	// Cancelling withdraws a put that has not happened yet.
+	if err := s.admit(ctx, auth.OpPut, req.GetQueue()); err != nil {
+		return nil, err
+	}
+
+	sm, err := s.GW.CancelScheduled(req.GetQueue(), id)
+	rec := audit.Record{Operation: audit.OpScheduleCancel, Queue: req.GetQueue(), MsgID: req.GetScheduleId()}
+	if sm != nil {
+		rec = rec.WithPayload(sm.Payload)
+	}
+	s.record(ctx, rec, err)
+	if serr := scheduleStatus(err); serr != nil {
+		return nil, serr
+	}
+	if err != nil {
+		slog.Error("[gRPC] CancelScheduled error",
+			"error", err,
+			"id", "9c3bd043-b2d9-47a6-b49f-46d24226223c")
+		return &mq_grpc_api.CancelScheduledResponse{
+			Status: "error",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	return &mq_grpc_api.CancelScheduledResponse{Status: "ok"}, nil
+}
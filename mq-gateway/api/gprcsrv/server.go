@@ -2,27 +2,186 @@ package grpcsrv
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"github.com/jlambert68/MQDockerContainer2/mq-gateway/api/proto/mq_grpc_api"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/audit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/ratelimit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/schema"
 	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
 )
 
 type Server struct {
 	mq_grpc_api.UnimplementedMqGrpcServicesServer
 	GW mqcore.Gateway
+	// Authz restricts which principals may use which queues; nil allows all.
+	Authz *auth.Policy
+	// Audit receives one record per message operation; nil disables auditing.
+	Audit *audit.Logger
+	// Limits throttles callers per principal, queue and operation; nil disables limits.
+	Limits *ratelimit.Limiter
+	// resolveDLQ replaces GW.ResolveDLQ in tests.
+	resolveDLQ func(queue string) (string, error)
+}
+
+// admit checks authorization and rate limits for op on queue, mapping
+// rejections to codes.PermissionDenied and codes.ResourceExhausted.
+func (s *Server) admit(ctx context.Context, op auth.Operation, queue string) error {
+	if err := s.Authz.Authorize(ctx, op, queue); err != nil {
+		s.record(ctx, audit.Record{Operation: string(op), Queue: queue, Outcome: audit.OutcomeDenied, Error: err.Error()}, nil)
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err := s.Limits.Allow(ctx, op, queue); err != nil {
+		s.record(ctx, audit.Record{Operation: string(op), Queue: queue, Outcome: audit.OutcomeThrottled, Error: err.Error()}, nil)
+		return resourceExhausted(ctx, err)
+	}
+	return nil
+}
+
+// longPoll reserves a slot for a call that may wait waitMs. On success the
+// caller must invoke release once the wait is over.
+func (s *Server) longPoll(ctx context.Context, waitMs int32) (release func(), err error) {
+	release, lerr := s.Limits.AcquireLongPoll(ctx, int(waitMs))
+	if lerr != nil {
+		return release, resourceExhausted(ctx, lerr)
+	}
+	return release, nil
+}
+
+// resourceExhausted carries the retry hint both as a RetryInfo detail and as
+// a "retry-after" header for clients that do not decode details.
+func resourceExhausted(ctx context.Context, err *ratelimit.Error) error {
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(err.RetryAfterSeconds())))
+	st := status.New(codes.ResourceExhausted, err.Error())
+	if detailed, derr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(err.RetryAfter)}); derr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// invalidPayload maps a payload rejected by its queue's schema to
+// InvalidArgument with one BadRequest field violation per schema violation,
+// and returns nil for other errors.
+func invalidPayload(err error) error {
+	var verr *schema.ValidationError
+	if !errors.As(err, &verr) {
+		return nil
+	}
+	st := status.New(codes.InvalidArgument, err.Error())
+	br := &errdetails.BadRequest{}
+	for _, v := range verr.Violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: v.Path, Description: v.Message})
+	}
+	if detailed, derr := st.WithDetails(br); derr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// record writes an audit record for the call in ctx; a non-nil err marks it
+// failed with its MQ reason code.
+func (s *Server) record(ctx context.Context, rec audit.Record, err error) {
+	if s.Audit == nil {
+		return
+	}
+	rec.Transport = "grpc"
+	if _, ok := runtime.HTTPPathPattern(ctx); ok {
+		// Called in-process by the /v2 REST gateway, which has no peer.
+		rec.Transport = "rest"
+	}
+	if pr, ok := peer.FromContext(ctx); ok {
+		rec.ClientAddr = pr.Addr.String()
+	} else if addr := metadata.ValueFromIncomingContext(ctx, remoteAddrKey); len(addr) > 0 {
+		// Set by the /v2 gateway's annotator, which is joined last.
+		rec.ClientAddr = addr[len(addr)-1]
+	}
+	if err != nil {
+		rec.Outcome = audit.OutcomeError
+		rec.Error = err.Error()
+		rec.ReasonCode = mqcore.ReasonCode(err)
+		if errors.As(err, new(*schema.ValidationError)) {
+			rec.Outcome = audit.OutcomeInvalid
+		}
+	} else if rec.Outcome == "" {
+		rec.Outcome = audit.OutcomeOK
+	}
+	s.Audit.Log(ctx, rec)
+}
+
+// messageRecord describes a received or browsed message.
+func messageRecord(op, queue string, m *mqcore.Message) audit.Record {
+	return audit.Record{
+		Operation: op,
+		Queue:     queue,
+		MsgID:     mqcore.FormatID(m.MsgID),
+		CorrelID:  mqcore.FormatID(m.CorrelID),
+		GroupID:   mqcore.FormatID(m.GroupID),
+	}.WithPayload(m.Payload)
 }
 
 func (s *Server) Put(ctx context.Context, req *mq_grpc_api.PutRequest) (*mq_grpc_api.PutResponse, error) {
 	// Validate request early to keep MQ errors clean.
 	if req.GetQueue() == "" {
-		return &mq_grpc_api.PutResponse{
-			Status: "error",
-			Error:  "queue required",
-		}, nil
+		return nil, status.Error(codes.InvalidArgument, "queue required")
 	}
 
-	err := s.GW.Put(req.GetQueue(), req.GetMessage())
+	groupID, err := mqcore.ParseID(req.GetGroupId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "group_id: "+err.Error())
+	}
+
+	key, err := idempotencyKey(ctx, req.GetIdempotencyKey())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.admit(ctx, auth.OpPut, req.GetQueue()); err != nil {
+		return nil, err
+	}
+
+	// Idempotency keys are per principal; without authentication all
+	// callers share one scope.
+	principal := ""
+	if p, ok := auth.FromContext(ctx); ok {
+		principal = p.Name
+	}
+	msgID, replayed, err := s.GW.PutIdempotent(principal, req.GetQueue(), key, req.GetMessage(), mqcore.PutOptions{
+		GroupID:      groupID,
+		MsgSeqNumber: req.GetMsgSeqNumber(),
+		Offset:       req.GetOffset(),
+		MsgFlags:     req.GetMsgFlags(),
+		ReplyToQueue: req.GetReplyToQueue(),
+	})
+	rec := audit.Record{
+		Operation: audit.OpPut,
+		Queue:     req.GetQueue(),
+		MsgID:     mqcore.FormatID(msgID),
+		GroupID:   mqcore.FormatID(groupID),
+	}.WithPayload(req.GetMessage())
+	if replayed {
+		rec.Outcome = audit.OutcomeReplayed
+	}
+	s.record(ctx, rec, err)
+	if errors.Is(err, mqcore.ErrIdempotencyConflict) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	if serr := invalidPayload(err); serr != nil {
+		return nil, serr
+	}
 	if err != nil {
 		slog.Error("[gRPC] Put error",
 			"error", err,
@@ -33,19 +192,59 @@ func (s *Server) Put(ctx context.Context, req *mq_grpc_api.PutRequest) (*mq_grpc
 		}, nil
 	}
 
-	return &mq_grpc_api.PutResponse{Status: "ok"}, nil
+	return &mq_grpc_api.PutResponse{Status: "ok", MsgId: mqcore.FormatID(msgID), Replayed: replayed}, nil
+}
+
+// idempotencyKey returns the put's idempotency key from the request field or
+// the "idempotency-key" metadata, which the /v2 gateway fills from the
+// Idempotency-Key header.
+func idempotencyKey(ctx context.Context, field string) (string, error) {
+	key := field
+	if v := metadata.ValueFromIncomingContext(ctx, "idempotency-key"); len(v) > 0 {
+		if key != "" && key != v[0] {
+			return "", errors.New("idempotency_key and idempotency-key metadata differ")
+		}
+		key = v[0]
+	}
+	if len(key) > mqcore.MaxIdempotencyKeyLength {
+		return "", fmt.Errorf("idempotency_key longer than %d bytes", mqcore.MaxIdempotencyKeyLength)
+	}
+	return key, nil
 }
 
 func (s *Server) Get(ctx context.Context, req *mq_grpc_api.GetRequest) (*mq_grpc_api.GetResponse, error) {
 	// Validate request early to keep MQ errors clean.
 	if req.GetQueue() == "" {
-		return &mq_grpc_api.GetResponse{
-			Status: "error",
-			Error:  "queue required",
-		}, nil
+		return nil, status.Error(codes.InvalidArgument, "queue required")
 	}
 
-	msg, empty, err := s.GW.Get(req.GetQueue(), int(req.GetWaitMs()), int(req.GetMaxMsgBytes()))
+	correlID, err := mqcore.ParseID(req.GetCorrelId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "correl_id: "+err.Error())
+	}
+
+	if err := s.admit(ctx, auth.OpGet, req.GetQueue()); err != nil {
+		return nil, err
+	}
+
+	release, err := s.longPoll(ctx, req.GetWaitMs())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	msg, empty, err := s.GW.GetMessage(req.GetQueue(), mqcore.GetOptions{
+		WaitMs:      int(req.GetWaitMs()),
+		MaxBytes:    int(req.GetMaxMsgBytes()),
+		CompleteMsg: req.GetCompleteMsg(),
+		CorrelID:    correlID,
+	})
+	// Empty gets remove nothing and are not audited.
+	if msg != nil {
+		s.record(ctx, messageRecord(audit.OpGet, req.GetQueue(), msg), nil)
+	} else if err != nil {
+		s.record(ctx, audit.Record{Operation: audit.OpGet, Queue: req.GetQueue()}, err)
+	}
 	if err != nil {
 		slog.Error("[gRPC] Get error",
 			"error", err,
@@ -55,24 +254,226 @@ func (s *Server) Get(ctx context.Context, req *mq_grpc_api.GetRequest) (*mq_grpc
 			Error:  err.Error(),
 		}, nil
 	}
+	if empty {
+		return &mq_grpc_api.GetResponse{Status: "ok", Empty: true}, nil
+	}
 
 	return &mq_grpc_api.GetResponse{
+		Status:       "ok",
+		Message:      msg.Payload,
+		MsgId:        mqcore.FormatID(msg.MsgID),
+		CorrelId:     mqcore.FormatID(msg.CorrelID),
+		GroupId:      mqcore.FormatID(msg.GroupID),
+		MsgSeqNumber: msg.MsgSeqNumber,
+		Offset:       msg.Offset,
+		MsgFlags:     msg.MsgFlags,
+	}, nil
+}
+
+func (s *Server) PutGroup(ctx context.Context, req *mq_grpc_api.PutGroupRequest) (*mq_grpc_api.PutGroupResponse, error) {
+	// PutGroup sends the messages as one ordered MQ group.
+	if req.GetQueue() == "" {
+		return nil, status.Error(codes.InvalidArgument, "queue required")
+	}
+	if len(req.GetMessages()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "messages required")
+	}
+
+	if err := s.admit(ctx, auth.OpPut, req.GetQueue()); err != nil {
+		return nil, err
+	}
+
+	groupID, err := s.GW.PutGroup(req.GetQueue(), req.GetMessages())
+	for _, m := range req.GetMessages() {
+		s.record(ctx, audit.Record{Operation: audit.OpPut, Queue: req.GetQueue(), GroupID: mqcore.FormatID(groupID)}.WithPayload(m), err)
+	}
+	if serr := invalidPayload(err); serr != nil {
+		return nil, serr
+	}
+	if err != nil {
+		slog.Error("[gRPC] PutGroup error",
+			"error", err,
+			"id", "939656aa-8cdf-439b-89ea-e19210136a22")
+		return &mq_grpc_api.PutGroupResponse{
+			Status: "error",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	return &mq_grpc_api.PutGroupResponse{
 		Status:  "ok",
-		Message: msg,
-		Empty:   empty,
+		GroupId: mqcore.FormatID(groupID),
 	}, nil
 }
 
-func (s *Server) BrowseFirst(ctx context.Context, req *mq_grpc_api.BrowseFirstRequest) (*mq_grpc_api.BrowseResponse, error) {
-	// BrowseFirst opens a server-side browse cursor.
+func (s *Server) GetGroup(ctx context.Context, req *mq_grpc_api.GetGroupRequest) (*mq_grpc_api.GetGroupResponse, error) {
+	// GetGroup receives one complete logical group.
 	if req.GetQueue() == "" {
-		return &mq_grpc_api.BrowseResponse{
+		return nil, status.Error(codes.InvalidArgument, "queue required")
+	}
+
+	if err := s.admit(ctx, auth.OpGet, req.GetQueue()); err != nil {
+		return nil, err
+	}
+
+	release, err := s.longPoll(ctx, req.GetWaitMs())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	msgs, empty, err := s.GW.GetGroup(req.GetQueue(), mqcore.GetOptions{
+		WaitMs:   int(req.GetWaitMs()),
+		MaxBytes: int(req.GetMaxMsgBytes()),
+	})
+	for i := range msgs {
+		s.record(ctx, messageRecord(audit.OpGet, req.GetQueue(), &msgs[i]), nil)
+	}
+	if err != nil && len(msgs) == 0 {
+		s.record(ctx, audit.Record{Operation: audit.OpGet, Queue: req.GetQueue()}, err)
+	}
+	if err != nil {
+		slog.Error("[gRPC] GetGroup error",
+			"error", err,
+			"id", "224cf3ad-c467-4fbc-ae97-be7127af591d")
+		return &mq_grpc_api.GetGroupResponse{
 			Status: "error",
-			Error:  "queue required",
+			Error:  err.Error(),
 		}, nil
 	}
 
+	resp := &mq_grpc_api.GetGroupResponse{Status: "ok", Empty: empty}
+	for _, m := range msgs {
+		resp.GroupId = mqcore.FormatID(m.GroupID)
+		resp.Messages = append(resp.Messages, &mq_grpc_api.GroupMessage{
+			Message:      m.Payload,
+			MsgId:        mqcore.FormatID(m.MsgID),
+			MsgSeqNumber: m.MsgSeqNumber,
+			Offset:       m.Offset,
+			MsgFlags:     m.MsgFlags,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Server) PutBatch(ctx context.Context, req *mq_grpc_api.PutBatchRequest) (*mq_grpc_api.PutBatchResponse, error) {
+	// PutBatch opens the queue once and puts every message.
+	if req.GetQueue() == "" {
+		return nil, status.Error(codes.InvalidArgument, "queue required")
+	}
+	if len(req.GetMessages()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "messages required")
+	}
+
+	if err := s.admit(ctx, auth.OpPut, req.GetQueue()); err != nil {
+		return nil, err
+	}
+
+	results, err := s.GW.PutBatch(req.GetQueue(), req.GetMessages(), req.GetSyncpoint())
+	for i, res := range results {
+		s.record(ctx, audit.Record{Operation: audit.OpPut, Queue: req.GetQueue(), MsgID: mqcore.FormatID(res.MsgID)}.WithPayload(req.GetMessages()[i]), res.Err)
+	}
+	if err != nil && len(results) == 0 {
+		s.record(ctx, audit.Record{Operation: audit.OpPut, Queue: req.GetQueue()}, err)
+	}
+	// Without syncpoint a rejected payload fails only its own entry.
+	if serr := invalidPayload(err); serr != nil {
+		return nil, serr
+	}
+	resp := &mq_grpc_api.PutBatchResponse{Status: "ok"}
+	for _, res := range results {
+		item := &mq_grpc_api.PutBatchResult{Status: "ok", MsgId: mqcore.FormatID(res.MsgID)}
+		if res.Err != nil {
+			item = &mq_grpc_api.PutBatchResult{Status: "error", Error: res.Err.Error()}
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	if err != nil {
+		slog.Error("[gRPC] PutBatch error",
+			"error", err,
+			"id", "d74e24f6-f7cf-460b-9186-c4be59885092")
+		resp.Status = "error"
+		resp.Error = err.Error()
+	}
+	return resp, nil
+}
+
+func (s *Server) GetBatch(ctx context.Context, req *mq_grpc_api.GetBatchRequest) (*mq_grpc_api.GetBatchResponse, error) {
+	// GetBatch opens the queue once and receives up to max_messages.
+	if req.GetQueue() == "" {
+		return nil, status.Error(codes.InvalidArgument, "queue required")
+	}
+	if req.GetMaxMessages() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "max_messages required")
+	}
+
+	if err := s.admit(ctx, auth.OpGet, req.GetQueue()); err != nil {
+		return nil, err
+	}
+
+	release, err := s.longPoll(ctx, req.GetWaitMs())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	results, err := s.GW.GetBatch(req.GetQueue(), int(req.GetMaxMessages()), mqcore.GetOptions{
+		WaitMs:   int(req.GetWaitMs()),
+		MaxBytes: int(req.GetMaxMsgBytes()),
+	}, req.GetSyncpoint())
+	for _, res := range results {
+		if res.Message != nil {
+			s.record(ctx, messageRecord(audit.OpGet, req.GetQueue(), res.Message), res.Err)
+		} else {
+			s.record(ctx, audit.Record{Operation: audit.OpGet, Queue: req.GetQueue()}, res.Err)
+		}
+	}
+	if err != nil && len(results) == 0 {
+		s.record(ctx, audit.Record{Operation: audit.OpGet, Queue: req.GetQueue()}, err)
+	}
+	resp := &mq_grpc_api.GetBatchResponse{Status: "ok", Empty: err == nil && len(results) == 0}
+	for _, res := range results {
+		item := &mq_grpc_api.GetBatchResult{Status: "error"}
+		if res.Err != nil {
+			item.Error = res.Err.Error()
+		} else {
+			item.Status = "ok"
+			item.Message = res.Message.Payload
+			item.MsgId = mqcore.FormatID(res.Message.MsgID)
+			item.CorrelId = mqcore.FormatID(res.Message.CorrelID)
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	if err != nil {
+		slog.Error("[gRPC] GetBatch error",
+			"error", err,
+			"id", "1e6a4273-8338-4f0f-ae70-bff7c7ee97ec")
+		resp.Status = "error"
+		resp.Error = err.Error()
+	}
+	return resp, nil
+}
+
+func (s *Server) BrowseFirst(ctx context.Context, req *mq_grpc_api.BrowseFirstRequest) (*mq_grpc_api.BrowseResponse, error) {
+	// BrowseFirst opens a server-side browse cursor.
+	if req.GetQueue() == "" {
+		return nil, status.Error(codes.InvalidArgument, "queue required")
+	}
+
+	if err := s.admit(ctx, auth.OpBrowse, req.GetQueue()); err != nil {
+		return nil, err
+	}
+
+	release, err := s.longPoll(ctx, req.GetWaitMs())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	msg, empty, browseID, err := s.GW.BrowseFirst(req.GetQueue(), int(req.GetWaitMs()), int(req.GetMaxMsgBytes()))
+	if err != nil || !empty {
+		s.record(ctx, audit.Record{Operation: audit.OpBrowse, Queue: req.GetQueue()}.WithPayload(msg), err)
+	}
 	if err != nil {
 		slog.Error("[gRPC] BrowseFirst error",
 			"error", err,
@@ -94,13 +495,28 @@ func (s *Server) BrowseFirst(ctx context.Context, req *mq_grpc_api.BrowseFirstRe
 func (s *Server) BrowseNext(ctx context.Context, req *mq_grpc_api.BrowseNextRequest) (*mq_grpc_api.BrowseResponse, error) {
 	// BrowseNext continues an existing browse cursor.
 	if req.GetBrowseId() == "" {
-		return &mq_grpc_api.BrowseResponse{
-			Status: "error",
-			Error:  "browse_id required",
-		}, nil
+		return nil, status.Error(codes.InvalidArgument, "browse_id required")
+	}
+
+	// The cursor keeps its queue, so re-check browse rights on every call.
+	queue, err := s.GW.BrowseQueue(req.GetBrowseId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err := s.admit(ctx, auth.OpBrowse, queue); err != nil {
+		return nil, err
 	}
 
+	release, err := s.longPoll(ctx, req.GetWaitMs())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	msg, empty, err := s.GW.BrowseNext(req.GetBrowseId(), int(req.GetWaitMs()), int(req.GetMaxMsgBytes()))
+	if err != nil || !empty {
+		s.record(ctx, audit.Record{Operation: audit.OpBrowse, Queue: queue}.WithPayload(msg), err)
+	}
 	if err != nil {
 		slog.Error("[gRPC] BrowseNext error",
 			"error", err,
@@ -119,16 +535,45 @@ func (s *Server) BrowseNext(ctx context.Context, req *mq_grpc_api.BrowseNextRequ
 	}, nil
 }
 
+func (s *Server) CloseBrowse(ctx context.Context, req *mq_grpc_api.CloseBrowseRequest) (*mq_grpc_api.CloseBrowseResponse, error) {
+	// CloseBrowse releases a browse cursor before it idles out.
+	if req.GetBrowseId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "browse_id required")
+	}
+
+	queue, err := s.GW.BrowseQueue(req.GetBrowseId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err := s.admit(ctx, auth.OpBrowse, queue); err != nil {
+		return nil, err
+	}
+
+	if err := s.GW.EndBrowse(req.GetBrowseId()); err != nil {
+		slog.Error("[gRPC] CloseBrowse error",
+			"error", err,
+			"id", "c3b5e0d6-1f2a-4b8e-9c7d-5a6e2f41b093")
+		return &mq_grpc_api.CloseBrowseResponse{
+			Status: "error",
+			Error:  err.Error(),
+		}, nil
+	}
+
+	return &mq_grpc_api.CloseBrowseResponse{Status: "ok"}, nil
+}
+
 func (s *Server) InquireQueue(ctx context.Context, req *mq_grpc_api.InquireQueueRequest) (*mq_grpc_api.InquireQueueResponse, error) {
 	// InquireQueue returns a subset of queue attributes.
 	if req.GetQueue() == "" {
-		return &mq_grpc_api.InquireQueueResponse{
-			Status: "error",
-			Error:  "queue required",
-		}, nil
+		return nil, status.Error(codes.InvalidArgument, "queue required")
+	}
+
+	if err := s.admit(ctx, auth.OpInquire, req.GetQueue()); err != nil {
+		return nil, err
 	}
 
 	info, err := s.GW.InquireQueue(req.GetQueue())
+	s.record(ctx, audit.Record{Operation: audit.OpInquire, Queue: req.GetQueue()}, err)
 	if err != nil {
 		slog.Error("[gRPC] InquireQueue error",
 			"error", err,
@@ -154,3 +599,144 @@ func (s *Server) InquireQueue(ctx context.Context, req *mq_grpc_api.InquireQueue
 		OpenOutputCount: info.OpenOutputCount,
 	}, nil
 }
+
+// deadLetterQueue resolves the dead-letter queue a DLQ call works on. When
+// the queue manager cannot name its DEADQ the call fails with
+// codes.Unavailable before it is authorized, so nothing reaches the queue.
+func (s *Server) deadLetterQueue(queue string) (string, error) {
+	resolve := s.GW.ResolveDLQ
+	if s.resolveDLQ != nil {
+		resolve = s.resolveDLQ
+	}
+	dlq, err := resolve(queue)
+	if err != nil {
+		slog.Error("[gRPC] cannot resolve dead-letter queue",
+			"queue", queue,
+			"error", err,
+			"id", "7d2f4b9e-31a6-4c58-9e0b-5a8c1f6d3e27")
+		return "", status.Error(codes.Unavailable, err.Error())
+	}
+	return dlq, nil
+}
+
+func (s *Server) BrowseDLQ(ctx context.Context, req *mq_grpc_api.DLQBrowseRequest) (*mq_grpc_api.DLQBrowseResponse, error) {
+	// BrowseDLQ lists dead letters with their MQDLH decoded.
+	filter := mqcore.DLQFilter{Reasons: req.GetReasons(), DestQueue: req.GetDestQueue()}
+	dlq, err := s.deadLetterQueue(req.GetQueue())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.admit(ctx, auth.OpBrowse, dlq); err != nil {
+		return nil, err
+	}
+
+	dls, err := s.GW.BrowseDLQ(dlq, filter, int(req.GetMaxMessages()), int(req.GetMaxMsgBytes()))
+	for _, dl := range dls {
+		s.record(ctx, audit.Record{Operation: audit.OpDLQBrowse, Queue: dlq, MsgID: mqcore.FormatID(dl.MsgID)}.WithPayload(dl.Payload), nil)
+	}
+	if err != nil && len(dls) == 0 {
+		s.record(ctx, audit.Record{Operation: audit.OpDLQBrowse, Queue: dlq}, err)
+	}
+	resp := &mq_grpc_api.DLQBrowseResponse{Status: "ok"}
+	for _, dl := range dls {
+		putTime := ""
+		if !dl.PutTime.IsZero() {
+			putTime = dl.PutTime.UTC().Format(time.RFC3339)
+		}
+		resp.Messages = append(resp.Messages, &mq_grpc_api.DeadLetterMessage{
+			MsgId:       mqcore.FormatID(dl.MsgID),
+			Reason:      dl.Reason,
+			ReasonText:  dl.ReasonText,
+			DestQueue:   dl.DestQName,
+			DestQmgr:    dl.DestQMgrName,
+			Format:      dl.Format,
+			PutApplName: dl.PutApplName,
+			PutTime:     putTime,
+			Message:     dl.Payload,
+		})
+	}
+	if err != nil {
+		slog.Error("[gRPC] BrowseDLQ error",
+			"error", err,
+			"id", "ec5c8c19-346b-4c7a-bf9c-3539afa9b28b")
+		resp.Status = "error"
+		resp.Error = err.Error()
+	}
+	return resp, nil
+}
+
+func (s *Server) ReplayDLQ(ctx context.Context, req *mq_grpc_api.DLQReplayRequest) (*mq_grpc_api.DLQReplayResponse, error) {
+	// ReplayDLQ strips the DLH and re-puts matching dead letters.
+	// Replay removes from the DLQ; a dry run only browses it.
+	dlqOp := auth.OpGet
+	if req.GetDryRun() {
+		dlqOp = auth.OpBrowse
+	}
+	dlq, err := s.deadLetterQueue(req.GetQueue())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.admit(ctx, dlqOp, dlq); err != nil {
+		return nil, err
+	}
+
+	results, err := s.GW.ReplayDLQ(mqcore.ReplayOptions{
+		DLQ:         dlq,
+		Filter:      mqcore.DLQFilter{Reasons: req.GetReasons(), DestQueue: req.GetDestQueue()},
+		Destination: req.GetDestination(),
+		MaxMessages: int(req.GetMaxMessages()),
+		MaxBytes:    int(req.GetMaxMsgBytes()),
+		DryRun:      req.GetDryRun(),
+		Authorize: func(target, targetQMgr string) error {
+			// Another queue manager's queues are outside the policy's
+			// namespace, so routing there also needs admin.
+			if targetQMgr != "" {
+				if err := s.Authz.Authorize(ctx, auth.OpAdmin, target); err != nil {
+					return err
+				}
+			}
+			return s.Authz.Authorize(ctx, auth.OpPut, target)
+		},
+	})
+	// Previews move nothing, so only real replays are audited.
+	for _, res := range results {
+		if req.GetDryRun() {
+			break
+		}
+		s.record(ctx, audit.Record{
+			Operation: audit.OpDLQReplay,
+			Queue:     dlq,
+			Target:    res.Target,
+			MsgID:     mqcore.FormatID(res.MsgID),
+		}.WithPayload(res.Payload), res.Err)
+	}
+	if err != nil && len(results) == 0 {
+		s.record(ctx, audit.Record{Operation: audit.OpDLQReplay, Queue: dlq}, err)
+	}
+	resp := &mq_grpc_api.DLQReplayResponse{Status: "ok", DryRun: req.GetDryRun()}
+	for _, res := range results {
+		item := &mq_grpc_api.DLQReplayResult{
+			Status:     "preview",
+			MsgId:      mqcore.FormatID(res.MsgID),
+			Reason:     res.Reason,
+			ReasonText: res.ReasonText,
+			DestQueue:  res.DestQName,
+			Target:     res.Target,
+		}
+		if res.Err != nil {
+			item.Status = "error"
+			item.Error = res.Err.Error()
+		} else if res.Replayed {
+			item.Status = "replayed"
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	if err != nil {
+		slog.Error("[gRPC] ReplayDLQ error",
+			"error", err,
+			"id", "4530c758-f4f5-4fb7-9153-46094fc30fc8")
+		resp.Status = "error"
+		resp.Error = err.Error()
+	}
+	return resp, nil
+}
@@ -0,0 +1,93 @@
+package grpcsrv
+
+import (
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/api/proto/mq_grpc_api"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/audit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+// TransferMessages moves or copies messages between queues. Progress is sent
+// after each committed batch; like the unary calls, MQ errors are reported in
+// the final message and invalid requests are rejected with InvalidArgument.
+func (s *Server) TransferMessages(req *mq_grpc_api.TransferRequest, stream grpc.ServerStreamingServer[mq_grpc_api.TransferProgress]) error {
+	ctx := stream.Context()
+	if req.GetSource() == "" || req.GetDestination() == "" {
+		return status.Error(codes.InvalidArgument, "source and destination required")
+	}
+	if strings.EqualFold(req.GetSource(), req.GetDestination()) {
+		return status.Error(codes.InvalidArgument, "source and destination must differ")
+	}
+	msgID, err := mqcore.ParseID(req.GetMsgId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "msg_id: "+err.Error())
+	}
+	correlID, err := mqcore.ParseID(req.GetCorrelId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "correl_id: "+err.Error())
+	}
+
+	// A move consumes the source; a copy only browses it.
+	srcOp, auditOp := auth.OpGet, audit.OpMove
+	if req.GetCopy() {
+		srcOp, auditOp = auth.OpBrowse, audit.OpCopy
+	}
+	if err := s.admit(ctx, srcOp, req.GetSource()); err != nil {
+		return err
+	}
+	if err := s.admit(ctx, auth.OpPut, req.GetDestination()); err != nil {
+		return err
+	}
+
+	var sendErr error
+	res, err := s.GW.TransferMessages(mqcore.TransferOptions{
+		Source:      req.GetSource(),
+		Destination: req.GetDestination(),
+		Copy:        req.GetCopy(),
+		MsgID:       msgID,
+		CorrelID:    correlID,
+		Selector:    req.GetSelector(),
+		BatchSize:   int(req.GetBatchSize()),
+		MaxMessages: int(req.GetMaxMessages()),
+		MaxBytes:    int(req.GetMaxMsgBytes()),
+		Progress: func(p mqcore.TransferResult) {
+			// A client that went away does not stop the transfer midway;
+			// committed batches stay committed either way.
+			if sendErr == nil {
+				sendErr = stream.Send(&mq_grpc_api.TransferProgress{
+					Status:           "progress",
+					Transferred:      int32(p.Transferred),
+					ContextPreserved: p.ContextPreserved,
+				})
+			}
+		},
+	})
+	s.record(ctx, audit.Record{Operation: auditOp, Queue: req.GetSource(), Target: req.GetDestination(), Count: res.Transferred}, err)
+
+	resp := &mq_grpc_api.TransferProgress{
+		Status:           "ok",
+		Transferred:      int32(res.Transferred),
+		ContextPreserved: res.ContextPreserved,
+	}
+	if err != nil {
+		slog.Error("[gRPC] TransferMessages error",
+			"error", err,
+			"source", req.GetSource(),
+			"destination", req.GetDestination(),
+			"transferred", res.Transferred,
+			"id", "0fbae29a-5af9-4821-b999-7db642139da4")
+		resp.Status = "error"
+		resp.Error = err.Error()
+	}
+	if sendErr != nil {
+		return sendErr
+	}
+	return stream.Send(resp)
+}
@@ -7,6 +7,7 @@
 package mq_grpc_api
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -22,11 +23,22 @@ const (
 )
 
 type PutRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Queue         string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Queue   string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Optional MQMD grouping/segmentation fields (group_id is hex).
+	GroupId      string `protobuf:"bytes,3,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	MsgSeqNumber int32  `protobuf:"varint,4,opt,name=msg_seq_number,json=msgSeqNumber,proto3" json:"msg_seq_number,omitempty"`
+	Offset       int32  `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	MsgFlags     int32  `protobuf:"varint,6,opt,name=msg_flags,json=msgFlags,proto3" json:"msg_flags,omitempty"`
+	// Optional reply queue; marks the message as a request.
+	ReplyToQueue string `protobuf:"bytes,7,opt,name=reply_to_queue,json=replyToQueue,proto3" json:"reply_to_queue,omitempty"`
+	// Optional deduplication key: a retried put by the same caller with the
+	// same key returns the first put's result instead of putting again. The
+	// MsgId is derived from it. May also be sent as "idempotency-key" metadata.
+	IdempotencyKey string `protobuf:"bytes,8,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *PutRequest) Reset() {
@@ -73,10 +85,55 @@ func (x *PutRequest) GetMessage() string {
 	return ""
 }
 
+func (x *PutRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *PutRequest) GetMsgSeqNumber() int32 {
+	if x != nil {
+		return x.MsgSeqNumber
+	}
+	return 0
+}
+
+func (x *PutRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *PutRequest) GetMsgFlags() int32 {
+	if x != nil {
+		return x.MsgFlags
+	}
+	return 0
+}
+
+func (x *PutRequest) GetReplyToQueue() string {
+	if x != nil {
+		return x.ReplyToQueue
+	}
+	return ""
+}
+
+func (x *PutRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 type PutResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Status string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error  string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	MsgId  string                 `protobuf:"bytes,3,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"`
+	// True when the result is that of an earlier put with the same idempotency_key.
+	Replayed      bool `protobuf:"varint,4,opt,name=replayed,proto3" json:"replayed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -125,11 +182,29 @@ func (x *PutResponse) GetError() string {
 	return ""
 }
 
+func (x *PutResponse) GetMsgId() string {
+	if x != nil {
+		return x.MsgId
+	}
+	return ""
+}
+
+func (x *PutResponse) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
 type GetRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Queue         string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
-	WaitMs        int32                  `protobuf:"varint,2,opt,name=wait_ms,json=waitMs,proto3" json:"wait_ms,omitempty"`
-	MaxMsgBytes   int32                  `protobuf:"varint,3,opt,name=max_msg_bytes,json=maxMsgBytes,proto3" json:"max_msg_bytes,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Queue       string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	WaitMs      int32                  `protobuf:"varint,2,opt,name=wait_ms,json=waitMs,proto3" json:"wait_ms,omitempty"`
+	MaxMsgBytes int32                  `protobuf:"varint,3,opt,name=max_msg_bytes,json=maxMsgBytes,proto3" json:"max_msg_bytes,omitempty"`
+	// Reassemble segmented messages into one logical message.
+	CompleteMsg bool `protobuf:"varint,4,opt,name=complete_msg,json=completeMsg,proto3" json:"complete_msg,omitempty"`
+	// Only get the message with this CorrelId (hex), e.g. a reply.
+	CorrelId      string `protobuf:"bytes,5,opt,name=correl_id,json=correlId,proto3" json:"correl_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -185,12 +260,32 @@ func (x *GetRequest) GetMaxMsgBytes() int32 {
 	return 0
 }
 
+func (x *GetRequest) GetCompleteMsg() bool {
+	if x != nil {
+		return x.CompleteMsg
+	}
+	return false
+}
+
+func (x *GetRequest) GetCorrelId() string {
+	if x != nil {
+		return x.CorrelId
+	}
+	return ""
+}
+
 type GetResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	Empty         bool                   `protobuf:"varint,3,opt,name=empty,proto3" json:"empty,omitempty"`
 	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	MsgId         string                 `protobuf:"bytes,5,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"`
+	CorrelId      string                 `protobuf:"bytes,6,opt,name=correl_id,json=correlId,proto3" json:"correl_id,omitempty"`
+	GroupId       string                 `protobuf:"bytes,7,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	MsgSeqNumber  int32                  `protobuf:"varint,8,opt,name=msg_seq_number,json=msgSeqNumber,proto3" json:"msg_seq_number,omitempty"`
+	Offset        int32                  `protobuf:"varint,9,opt,name=offset,proto3" json:"offset,omitempty"`
+	MsgFlags      int32                  `protobuf:"varint,10,opt,name=msg_flags,json=msgFlags,proto3" json:"msg_flags,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -253,6 +348,48 @@ func (x *GetResponse) GetError() string {
 	return ""
 }
 
+func (x *GetResponse) GetMsgId() string {
+	if x != nil {
+		return x.MsgId
+	}
+	return ""
+}
+
+func (x *GetResponse) GetCorrelId() string {
+	if x != nil {
+		return x.CorrelId
+	}
+	return ""
+}
+
+func (x *GetResponse) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *GetResponse) GetMsgSeqNumber() int32 {
+	if x != nil {
+		return x.MsgSeqNumber
+	}
+	return 0
+}
+
+func (x *GetResponse) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetResponse) GetMsgFlags() int32 {
+	if x != nil {
+		return x.MsgFlags
+	}
+	return 0
+}
+
 type BrowseFirstRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Queue         string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
@@ -449,6 +586,102 @@ func (x *BrowseResponse) GetError() string {
 	return ""
 }
 
+type CloseBrowseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BrowseId      string                 `protobuf:"bytes,1,opt,name=browse_id,json=browseId,proto3" json:"browse_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseBrowseRequest) Reset() {
+	*x = CloseBrowseRequest{}
+	mi := &file_mq_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseBrowseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseBrowseRequest) ProtoMessage() {}
+
+func (x *CloseBrowseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseBrowseRequest.ProtoReflect.Descriptor instead.
+func (*CloseBrowseRequest) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CloseBrowseRequest) GetBrowseId() string {
+	if x != nil {
+		return x.BrowseId
+	}
+	return ""
+}
+
+type CloseBrowseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseBrowseResponse) Reset() {
+	*x = CloseBrowseResponse{}
+	mi := &file_mq_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseBrowseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseBrowseResponse) ProtoMessage() {}
+
+func (x *CloseBrowseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseBrowseResponse.ProtoReflect.Descriptor instead.
+func (*CloseBrowseResponse) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CloseBrowseResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CloseBrowseResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 type InquireQueueRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Queue         string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
@@ -458,7 +691,7 @@ type InquireQueueRequest struct {
 
 func (x *InquireQueueRequest) Reset() {
 	*x = InquireQueueRequest{}
-	mi := &file_mq_proto_msgTypes[7]
+	mi := &file_mq_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -470,7 +703,7 @@ func (x *InquireQueueRequest) String() string {
 func (*InquireQueueRequest) ProtoMessage() {}
 
 func (x *InquireQueueRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mq_proto_msgTypes[7]
+	mi := &file_mq_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -483,7 +716,7 @@ func (x *InquireQueueRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InquireQueueRequest.ProtoReflect.Descriptor instead.
 func (*InquireQueueRequest) Descriptor() ([]byte, []int) {
-	return file_mq_proto_rawDescGZIP(), []int{7}
+	return file_mq_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *InquireQueueRequest) GetQueue() string {
@@ -514,7 +747,7 @@ type InquireQueueResponse struct {
 
 func (x *InquireQueueResponse) Reset() {
 	*x = InquireQueueResponse{}
-	mi := &file_mq_proto_msgTypes[8]
+	mi := &file_mq_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -526,7 +759,7 @@ func (x *InquireQueueResponse) String() string {
 func (*InquireQueueResponse) ProtoMessage() {}
 
 func (x *InquireQueueResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mq_proto_msgTypes[8]
+	mi := &file_mq_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -539,7 +772,7 @@ func (x *InquireQueueResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InquireQueueResponse.ProtoReflect.Descriptor instead.
 func (*InquireQueueResponse) Descriptor() ([]byte, []int) {
-	return file_mq_proto_rawDescGZIP(), []int{8}
+	return file_mq_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *InquireQueueResponse) GetStatus() string {
@@ -633,71 +866,2400 @@ func (x *InquireQueueResponse) GetError() string {
 	return ""
 }
 
-var File_mq_proto protoreflect.FileDescriptor
+type PutGroupRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Queue string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	// Messages are put in order; the last one is flagged last-in-group.
+	Messages      []string `protobuf:"bytes,2,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_mq_proto_rawDesc = "" +
-	"\n" +
-	"\bmq.proto\x12\x04mqpb\"<\n" +
-	"\n" +
-	"PutRequest\x12\x14\n" +
-	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\";\n" +
-	"\vPutResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"_\n" +
-	"\n" +
-	"GetRequest\x12\x14\n" +
-	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x17\n" +
-	"\await_ms\x18\x02 \x01(\x05R\x06waitMs\x12\"\n" +
-	"\rmax_msg_bytes\x18\x03 \x01(\x05R\vmaxMsgBytes\"k\n" +
-	"\vGetResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
-	"\x05empty\x18\x03 \x01(\bR\x05empty\x12\x14\n" +
-	"\x05error\x18\x04 \x01(\tR\x05error\"g\n" +
-	"\x12BrowseFirstRequest\x12\x14\n" +
-	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x17\n" +
-	"\await_ms\x18\x02 \x01(\x05R\x06waitMs\x12\"\n" +
-	"\rmax_msg_bytes\x18\x03 \x01(\x05R\vmaxMsgBytes\"m\n" +
-	"\x11BrowseNextRequest\x12\x1b\n" +
-	"\tbrowse_id\x18\x01 \x01(\tR\bbrowseId\x12\x17\n" +
-	"\await_ms\x18\x02 \x01(\x05R\x06waitMs\x12\"\n" +
-	"\rmax_msg_bytes\x18\x03 \x01(\x05R\vmaxMsgBytes\"\x8b\x01\n" +
-	"\x0eBrowseResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
-	"\x05empty\x18\x03 \x01(\bR\x05empty\x12\x1b\n" +
-	"\tbrowse_id\x18\x04 \x01(\tR\bbrowseId\x12\x14\n" +
-	"\x05error\x18\x05 \x01(\tR\x05error\"+\n" +
-	"\x13InquireQueueRequest\x12\x14\n" +
-	"\x05queue\x18\x01 \x01(\tR\x05queue\"\xc2\x03\n" +
-	"\x14InquireQueueResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
-	"\x05queue\x18\x02 \x01(\tR\x05queue\x12\x1d\n" +
-	"\n" +
-	"queue_desc\x18\x03 \x01(\tR\tqueueDesc\x12\x1d\n" +
-	"\n" +
-	"queue_type\x18\x04 \x01(\x05R\tqueueType\x12\x1f\n" +
-	"\vqueue_usage\x18\x05 \x01(\x05R\n" +
-	"queueUsage\x12'\n" +
-	"\x0fdef_persistence\x18\x06 \x01(\x05R\x0edefPersistence\x12\x1f\n" +
-	"\vinhibit_get\x18\a \x01(\x05R\n" +
-	"inhibitGet\x12\x1f\n" +
-	"\vinhibit_put\x18\b \x01(\x05R\n" +
-	"inhibitPut\x12&\n" +
-	"\x0fcurrent_q_depth\x18\t \x01(\x05R\rcurrentQDepth\x12\x1e\n" +
-	"\vmax_q_depth\x18\n" +
-	" \x01(\x05R\tmaxQDepth\x12(\n" +
-	"\x10open_input_count\x18\v \x01(\x05R\x0eopenInputCount\x12*\n" +
-	"\x11open_output_count\x18\f \x01(\x05R\x0fopenOutputCount\x12\x14\n" +
-	"\x05error\x18\r \x01(\tR\x05error2\xb5\x02\n" +
-	"\x0eMqGrpcServices\x12,\n" +
-	"\x03Put\x12\x10.mqpb.PutRequest\x1a\x11.mqpb.PutResponse\"\x00\x12,\n" +
-	"\x03Get\x12\x10.mqpb.GetRequest\x1a\x11.mqpb.GetResponse\"\x00\x12?\n" +
-	"\vBrowseFirst\x12\x18.mqpb.BrowseFirstRequest\x1a\x14.mqpb.BrowseResponse\"\x00\x12=\n" +
+func (x *PutGroupRequest) Reset() {
+	*x = PutGroupRequest{}
+	mi := &file_mq_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutGroupRequest) ProtoMessage() {}
+
+func (x *PutGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutGroupRequest.ProtoReflect.Descriptor instead.
+func (*PutGroupRequest) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PutGroupRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *PutGroupRequest) GetMessages() []string {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type PutGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	GroupId       string                 `protobuf:"bytes,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutGroupResponse) Reset() {
+	*x = PutGroupResponse{}
+	mi := &file_mq_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutGroupResponse) ProtoMessage() {}
+
+func (x *PutGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutGroupResponse.ProtoReflect.Descriptor instead.
+func (*PutGroupResponse) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PutGroupResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PutGroupResponse) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *PutGroupResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Queue         string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	WaitMs        int32                  `protobuf:"varint,2,opt,name=wait_ms,json=waitMs,proto3" json:"wait_ms,omitempty"`
+	MaxMsgBytes   int32                  `protobuf:"varint,3,opt,name=max_msg_bytes,json=maxMsgBytes,proto3" json:"max_msg_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGroupRequest) Reset() {
+	*x = GetGroupRequest{}
+	mi := &file_mq_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGroupRequest) ProtoMessage() {}
+
+func (x *GetGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGroupRequest.ProtoReflect.Descriptor instead.
+func (*GetGroupRequest) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetGroupRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *GetGroupRequest) GetWaitMs() int32 {
+	if x != nil {
+		return x.WaitMs
+	}
+	return 0
+}
+
+func (x *GetGroupRequest) GetMaxMsgBytes() int32 {
+	if x != nil {
+		return x.MaxMsgBytes
+	}
+	return 0
+}
+
+type GroupMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	MsgId         string                 `protobuf:"bytes,2,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"`
+	MsgSeqNumber  int32                  `protobuf:"varint,3,opt,name=msg_seq_number,json=msgSeqNumber,proto3" json:"msg_seq_number,omitempty"`
+	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	MsgFlags      int32                  `protobuf:"varint,5,opt,name=msg_flags,json=msgFlags,proto3" json:"msg_flags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupMessage) Reset() {
+	*x = GroupMessage{}
+	mi := &file_mq_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupMessage) ProtoMessage() {}
+
+func (x *GroupMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupMessage.ProtoReflect.Descriptor instead.
+func (*GroupMessage) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GroupMessage) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GroupMessage) GetMsgId() string {
+	if x != nil {
+		return x.MsgId
+	}
+	return ""
+}
+
+func (x *GroupMessage) GetMsgSeqNumber() int32 {
+	if x != nil {
+		return x.MsgSeqNumber
+	}
+	return 0
+}
+
+func (x *GroupMessage) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GroupMessage) GetMsgFlags() int32 {
+	if x != nil {
+		return x.MsgFlags
+	}
+	return 0
+}
+
+type GetGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	GroupId       string                 `protobuf:"bytes,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Messages      []*GroupMessage        `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`
+	Empty         bool                   `protobuf:"varint,4,opt,name=empty,proto3" json:"empty,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGroupResponse) Reset() {
+	*x = GetGroupResponse{}
+	mi := &file_mq_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGroupResponse) ProtoMessage() {}
+
+func (x *GetGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGroupResponse.ProtoReflect.Descriptor instead.
+func (*GetGroupResponse) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetGroupResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetGroupResponse) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *GetGroupResponse) GetMessages() []*GroupMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *GetGroupResponse) GetEmpty() bool {
+	if x != nil {
+		return x.Empty
+	}
+	return false
+}
+
+func (x *GetGroupResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type PutBatchRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Queue    string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	Messages []string               `protobuf:"bytes,2,rep,name=messages,proto3" json:"messages,omitempty"`
+	// Put all messages in one unit of work (all-or-nothing).
+	Syncpoint     bool `protobuf:"varint,3,opt,name=syncpoint,proto3" json:"syncpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutBatchRequest) Reset() {
+	*x = PutBatchRequest{}
+	mi := &file_mq_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutBatchRequest) ProtoMessage() {}
+
+func (x *PutBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutBatchRequest.ProtoReflect.Descriptor instead.
+func (*PutBatchRequest) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PutBatchRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *PutBatchRequest) GetMessages() []string {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *PutBatchRequest) GetSyncpoint() bool {
+	if x != nil {
+		return x.Syncpoint
+	}
+	return false
+}
+
+type PutBatchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	MsgId         string                 `protobuf:"bytes,2,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutBatchResult) Reset() {
+	*x = PutBatchResult{}
+	mi := &file_mq_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutBatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutBatchResult) ProtoMessage() {}
+
+func (x *PutBatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutBatchResult.ProtoReflect.Descriptor instead.
+func (*PutBatchResult) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PutBatchResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PutBatchResult) GetMsgId() string {
+	if x != nil {
+		return x.MsgId
+	}
+	return ""
+}
+
+func (x *PutBatchResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type PutBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Results       []*PutBatchResult      `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutBatchResponse) Reset() {
+	*x = PutBatchResponse{}
+	mi := &file_mq_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutBatchResponse) ProtoMessage() {}
+
+func (x *PutBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutBatchResponse.ProtoReflect.Descriptor instead.
+func (*PutBatchResponse) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PutBatchResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PutBatchResponse) GetResults() []*PutBatchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *PutBatchResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetBatchRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Queue       string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	MaxMessages int32                  `protobuf:"varint,2,opt,name=max_messages,json=maxMessages,proto3" json:"max_messages,omitempty"`
+	WaitMs      int32                  `protobuf:"varint,3,opt,name=wait_ms,json=waitMs,proto3" json:"wait_ms,omitempty"`
+	MaxMsgBytes int32                  `protobuf:"varint,4,opt,name=max_msg_bytes,json=maxMsgBytes,proto3" json:"max_msg_bytes,omitempty"`
+	// Get all messages in one unit of work (all-or-nothing).
+	Syncpoint     bool `protobuf:"varint,5,opt,name=syncpoint,proto3" json:"syncpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBatchRequest) Reset() {
+	*x = GetBatchRequest{}
+	mi := &file_mq_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBatchRequest) ProtoMessage() {}
+
+func (x *GetBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBatchRequest.ProtoReflect.Descriptor instead.
+func (*GetBatchRequest) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetBatchRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *GetBatchRequest) GetMaxMessages() int32 {
+	if x != nil {
+		return x.MaxMessages
+	}
+	return 0
+}
+
+func (x *GetBatchRequest) GetWaitMs() int32 {
+	if x != nil {
+		return x.WaitMs
+	}
+	return 0
+}
+
+func (x *GetBatchRequest) GetMaxMsgBytes() int32 {
+	if x != nil {
+		return x.MaxMsgBytes
+	}
+	return 0
+}
+
+func (x *GetBatchRequest) GetSyncpoint() bool {
+	if x != nil {
+		return x.Syncpoint
+	}
+	return false
+}
+
+type GetBatchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	MsgId         string                 `protobuf:"bytes,3,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"`
+	CorrelId      string                 `protobuf:"bytes,4,opt,name=correl_id,json=correlId,proto3" json:"correl_id,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBatchResult) Reset() {
+	*x = GetBatchResult{}
+	mi := &file_mq_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBatchResult) ProtoMessage() {}
+
+func (x *GetBatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBatchResult.ProtoReflect.Descriptor instead.
+func (*GetBatchResult) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetBatchResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetBatchResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetBatchResult) GetMsgId() string {
+	if x != nil {
+		return x.MsgId
+	}
+	return ""
+}
+
+func (x *GetBatchResult) GetCorrelId() string {
+	if x != nil {
+		return x.CorrelId
+	}
+	return ""
+}
+
+func (x *GetBatchResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Results       []*GetBatchResult      `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	Empty         bool                   `protobuf:"varint,3,opt,name=empty,proto3" json:"empty,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBatchResponse) Reset() {
+	*x = GetBatchResponse{}
+	mi := &file_mq_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBatchResponse) ProtoMessage() {}
+
+func (x *GetBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBatchResponse.ProtoReflect.Descriptor instead.
+func (*GetBatchResponse) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetBatchResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetBatchResponse) GetResults() []*GetBatchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *GetBatchResponse) GetEmpty() bool {
+	if x != nil {
+		return x.Empty
+	}
+	return false
+}
+
+func (x *GetBatchResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type DLQBrowseRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Dead-letter queue; empty uses the queue manager's DEADQ.
+	Queue         string  `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	Reasons       []int32 `protobuf:"varint,2,rep,packed,name=reasons,proto3" json:"reasons,omitempty"`
+	DestQueue     string  `protobuf:"bytes,3,opt,name=dest_queue,json=destQueue,proto3" json:"dest_queue,omitempty"`
+	MaxMessages   int32   `protobuf:"varint,4,opt,name=max_messages,json=maxMessages,proto3" json:"max_messages,omitempty"`
+	MaxMsgBytes   int32   `protobuf:"varint,5,opt,name=max_msg_bytes,json=maxMsgBytes,proto3" json:"max_msg_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DLQBrowseRequest) Reset() {
+	*x = DLQBrowseRequest{}
+	mi := &file_mq_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DLQBrowseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DLQBrowseRequest) ProtoMessage() {}
+
+func (x *DLQBrowseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DLQBrowseRequest.ProtoReflect.Descriptor instead.
+func (*DLQBrowseRequest) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DLQBrowseRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *DLQBrowseRequest) GetReasons() []int32 {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *DLQBrowseRequest) GetDestQueue() string {
+	if x != nil {
+		return x.DestQueue
+	}
+	return ""
+}
+
+func (x *DLQBrowseRequest) GetMaxMessages() int32 {
+	if x != nil {
+		return x.MaxMessages
+	}
+	return 0
+}
+
+func (x *DLQBrowseRequest) GetMaxMsgBytes() int32 {
+	if x != nil {
+		return x.MaxMsgBytes
+	}
+	return 0
+}
+
+type DeadLetterMessage struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	MsgId       string                 `protobuf:"bytes,1,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"`
+	Reason      int32                  `protobuf:"varint,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	ReasonText  string                 `protobuf:"bytes,3,opt,name=reason_text,json=reasonText,proto3" json:"reason_text,omitempty"`
+	DestQueue   string                 `protobuf:"bytes,4,opt,name=dest_queue,json=destQueue,proto3" json:"dest_queue,omitempty"`
+	DestQmgr    string                 `protobuf:"bytes,5,opt,name=dest_qmgr,json=destQmgr,proto3" json:"dest_qmgr,omitempty"`
+	Format      string                 `protobuf:"bytes,6,opt,name=format,proto3" json:"format,omitempty"`
+	PutApplName string                 `protobuf:"bytes,7,opt,name=put_appl_name,json=putApplName,proto3" json:"put_appl_name,omitempty"`
+	// RFC 3339, empty when the DLH carries no timestamp.
+	PutTime       string `protobuf:"bytes,8,opt,name=put_time,json=putTime,proto3" json:"put_time,omitempty"`
+	Message       string `protobuf:"bytes,9,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadLetterMessage) Reset() {
+	*x = DeadLetterMessage{}
+	mi := &file_mq_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadLetterMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetterMessage) ProtoMessage() {}
+
+func (x *DeadLetterMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetterMessage.ProtoReflect.Descriptor instead.
+func (*DeadLetterMessage) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeadLetterMessage) GetMsgId() string {
+	if x != nil {
+		return x.MsgId
+	}
+	return ""
+}
+
+func (x *DeadLetterMessage) GetReason() int32 {
+	if x != nil {
+		return x.Reason
+	}
+	return 0
+}
+
+func (x *DeadLetterMessage) GetReasonText() string {
+	if x != nil {
+		return x.ReasonText
+	}
+	return ""
+}
+
+func (x *DeadLetterMessage) GetDestQueue() string {
+	if x != nil {
+		return x.DestQueue
+	}
+	return ""
+}
+
+func (x *DeadLetterMessage) GetDestQmgr() string {
+	if x != nil {
+		return x.DestQmgr
+	}
+	return ""
+}
+
+func (x *DeadLetterMessage) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *DeadLetterMessage) GetPutApplName() string {
+	if x != nil {
+		return x.PutApplName
+	}
+	return ""
+}
+
+func (x *DeadLetterMessage) GetPutTime() string {
+	if x != nil {
+		return x.PutTime
+	}
+	return ""
+}
+
+func (x *DeadLetterMessage) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type DLQBrowseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Messages      []*DeadLetterMessage   `protobuf:"bytes,2,rep,name=messages,proto3" json:"messages,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DLQBrowseResponse) Reset() {
+	*x = DLQBrowseResponse{}
+	mi := &file_mq_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DLQBrowseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DLQBrowseResponse) ProtoMessage() {}
+
+func (x *DLQBrowseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DLQBrowseResponse.ProtoReflect.Descriptor instead.
+func (*DLQBrowseResponse) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DLQBrowseResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DLQBrowseResponse) GetMessages() []*DeadLetterMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *DLQBrowseResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type DLQReplayRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Dead-letter queue; empty uses the queue manager's DEADQ.
+	Queue     string  `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	Reasons   []int32 `protobuf:"varint,2,rep,packed,name=reasons,proto3" json:"reasons,omitempty"`
+	DestQueue string  `protobuf:"bytes,3,opt,name=dest_queue,json=destQueue,proto3" json:"dest_queue,omitempty"`
+	// Overrides the original destination from the DLH.
+	Destination   string `protobuf:"bytes,4,opt,name=destination,proto3" json:"destination,omitempty"`
+	MaxMessages   int32  `protobuf:"varint,5,opt,name=max_messages,json=maxMessages,proto3" json:"max_messages,omitempty"`
+	MaxMsgBytes   int32  `protobuf:"varint,6,opt,name=max_msg_bytes,json=maxMsgBytes,proto3" json:"max_msg_bytes,omitempty"`
+	DryRun        bool   `protobuf:"varint,7,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DLQReplayRequest) Reset() {
+	*x = DLQReplayRequest{}
+	mi := &file_mq_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DLQReplayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DLQReplayRequest) ProtoMessage() {}
+
+func (x *DLQReplayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DLQReplayRequest.ProtoReflect.Descriptor instead.
+func (*DLQReplayRequest) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DLQReplayRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *DLQReplayRequest) GetReasons() []int32 {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *DLQReplayRequest) GetDestQueue() string {
+	if x != nil {
+		return x.DestQueue
+	}
+	return ""
+}
+
+func (x *DLQReplayRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *DLQReplayRequest) GetMaxMessages() int32 {
+	if x != nil {
+		return x.MaxMessages
+	}
+	return 0
+}
+
+func (x *DLQReplayRequest) GetMaxMsgBytes() int32 {
+	if x != nil {
+		return x.MaxMsgBytes
+	}
+	return 0
+}
+
+func (x *DLQReplayRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type DLQReplayResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "replayed", "preview" or "error".
+	Status        string `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	MsgId         string `protobuf:"bytes,2,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"`
+	Reason        int32  `protobuf:"varint,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	ReasonText    string `protobuf:"bytes,4,opt,name=reason_text,json=reasonText,proto3" json:"reason_text,omitempty"`
+	DestQueue     string `protobuf:"bytes,5,opt,name=dest_queue,json=destQueue,proto3" json:"dest_queue,omitempty"`
+	Target        string `protobuf:"bytes,6,opt,name=target,proto3" json:"target,omitempty"`
+	Error         string `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DLQReplayResult) Reset() {
+	*x = DLQReplayResult{}
+	mi := &file_mq_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DLQReplayResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DLQReplayResult) ProtoMessage() {}
+
+func (x *DLQReplayResult) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DLQReplayResult.ProtoReflect.Descriptor instead.
+func (*DLQReplayResult) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DLQReplayResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DLQReplayResult) GetMsgId() string {
+	if x != nil {
+		return x.MsgId
+	}
+	return ""
+}
+
+func (x *DLQReplayResult) GetReason() int32 {
+	if x != nil {
+		return x.Reason
+	}
+	return 0
+}
+
+func (x *DLQReplayResult) GetReasonText() string {
+	if x != nil {
+		return x.ReasonText
+	}
+	return ""
+}
+
+func (x *DLQReplayResult) GetDestQueue() string {
+	if x != nil {
+		return x.DestQueue
+	}
+	return ""
+}
+
+func (x *DLQReplayResult) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *DLQReplayResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type DLQReplayResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	Results       []*DLQReplayResult     `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DLQReplayResponse) Reset() {
+	*x = DLQReplayResponse{}
+	mi := &file_mq_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DLQReplayResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DLQReplayResponse) ProtoMessage() {}
+
+func (x *DLQReplayResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DLQReplayResponse.ProtoReflect.Descriptor instead.
+func (*DLQReplayResponse) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DLQReplayResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DLQReplayResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *DLQReplayResponse) GetResults() []*DLQReplayResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *DLQReplayResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ScheduleRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Queue   string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Exactly one of deliver_at (RFC 3339) and delay_ms sets the due time;
+	// delays beyond int32 (about 24 days) use deliver_at.
+	DeliverAt string `protobuf:"bytes,3,opt,name=deliver_at,json=deliverAt,proto3" json:"deliver_at,omitempty"`
+	DelayMs   int32  `protobuf:"varint,4,opt,name=delay_ms,json=delayMs,proto3" json:"delay_ms,omitempty"`
+	// Optional reply queue; marks the message as a request.
+	ReplyToQueue  string `protobuf:"bytes,5,opt,name=reply_to_queue,json=replyToQueue,proto3" json:"reply_to_queue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleRequest) Reset() {
+	*x = ScheduleRequest{}
+	mi := &file_mq_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleRequest) ProtoMessage() {}
+
+func (x *ScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleRequest.ProtoReflect.Descriptor instead.
+func (*ScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ScheduleRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *ScheduleRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ScheduleRequest) GetDeliverAt() string {
+	if x != nil {
+		return x.DeliverAt
+	}
+	return ""
+}
+
+func (x *ScheduleRequest) GetDelayMs() int32 {
+	if x != nil {
+		return x.DelayMs
+	}
+	return 0
+}
+
+func (x *ScheduleRequest) GetReplyToQueue() string {
+	if x != nil {
+		return x.ReplyToQueue
+	}
+	return ""
+}
+
+type ScheduleResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Status string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error  string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// The MsgId (hex) of the message, which it keeps when delivered.
+	ScheduleId string `protobuf:"bytes,3,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	// RFC 3339.
+	DeliverAt     string `protobuf:"bytes,4,opt,name=deliver_at,json=deliverAt,proto3" json:"deliver_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleResponse) Reset() {
+	*x = ScheduleResponse{}
+	mi := &file_mq_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleResponse) ProtoMessage() {}
+
+func (x *ScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleResponse.ProtoReflect.Descriptor instead.
+func (*ScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ScheduleResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ScheduleResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ScheduleResponse) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+func (x *ScheduleResponse) GetDeliverAt() string {
+	if x != nil {
+		return x.DeliverAt
+	}
+	return ""
+}
+
+type ListScheduledRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Queue         string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	MaxMessages   int32                  `protobuf:"varint,2,opt,name=max_messages,json=maxMessages,proto3" json:"max_messages,omitempty"`
+	MaxMsgBytes   int32                  `protobuf:"varint,3,opt,name=max_msg_bytes,json=maxMsgBytes,proto3" json:"max_msg_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScheduledRequest) Reset() {
+	*x = ListScheduledRequest{}
+	mi := &file_mq_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduledRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledRequest) ProtoMessage() {}
+
+func (x *ListScheduledRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledRequest.ProtoReflect.Descriptor instead.
+func (*ListScheduledRequest) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListScheduledRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *ListScheduledRequest) GetMaxMessages() int32 {
+	if x != nil {
+		return x.MaxMessages
+	}
+	return 0
+}
+
+func (x *ListScheduledRequest) GetMaxMsgBytes() int32 {
+	if x != nil {
+		return x.MaxMsgBytes
+	}
+	return 0
+}
+
+type ScheduledMessage struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId string                 `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	Queue      string                 `protobuf:"bytes,2,opt,name=queue,proto3" json:"queue,omitempty"`
+	// RFC 3339.
+	DeliverAt     string `protobuf:"bytes,3,opt,name=deliver_at,json=deliverAt,proto3" json:"deliver_at,omitempty"`
+	ReplyToQueue  string `protobuf:"bytes,4,opt,name=reply_to_queue,json=replyToQueue,proto3" json:"reply_to_queue,omitempty"`
+	Message       string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledMessage) Reset() {
+	*x = ScheduledMessage{}
+	mi := &file_mq_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledMessage) ProtoMessage() {}
+
+func (x *ScheduledMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledMessage.ProtoReflect.Descriptor instead.
+func (*ScheduledMessage) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ScheduledMessage) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetDeliverAt() string {
+	if x != nil {
+		return x.DeliverAt
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetReplyToQueue() string {
+	if x != nil {
+		return x.ReplyToQueue
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ListScheduledResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Messages      []*ScheduledMessage    `protobuf:"bytes,2,rep,name=messages,proto3" json:"messages,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScheduledResponse) Reset() {
+	*x = ListScheduledResponse{}
+	mi := &file_mq_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduledResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledResponse) ProtoMessage() {}
+
+func (x *ListScheduledResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledResponse.ProtoReflect.Descriptor instead.
+func (*ListScheduledResponse) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListScheduledResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListScheduledResponse) GetMessages() []*ScheduledMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *ListScheduledResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type CancelScheduledRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Queue         string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	ScheduleId    string                 `protobuf:"bytes,2,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScheduledRequest) Reset() {
+	*x = CancelScheduledRequest{}
+	mi := &file_mq_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduledRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduledRequest) ProtoMessage() {}
+
+func (x *CancelScheduledRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScheduledRequest.ProtoReflect.Descriptor instead.
+func (*CancelScheduledRequest) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CancelScheduledRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *CancelScheduledRequest) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+type CancelScheduledResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScheduledResponse) Reset() {
+	*x = CancelScheduledResponse{}
+	mi := &file_mq_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduledResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduledResponse) ProtoMessage() {}
+
+func (x *CancelScheduledResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScheduledResponse.ProtoReflect.Descriptor instead.
+func (*CancelScheduledResponse) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *CancelScheduledResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CancelScheduledResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ExportQueueRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Queue string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	// "jsonl" (default) or "tar".
+	Format string `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	// Remove exported messages instead of browsing them.
+	Destructive bool `protobuf:"varint,3,opt,name=destructive,proto3" json:"destructive,omitempty"`
+	// 0 exports every message.
+	MaxMessages   int32 `protobuf:"varint,4,opt,name=max_messages,json=maxMessages,proto3" json:"max_messages,omitempty"`
+	MaxMsgBytes   int32 `protobuf:"varint,5,opt,name=max_msg_bytes,json=maxMsgBytes,proto3" json:"max_msg_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportQueueRequest) Reset() {
+	*x = ExportQueueRequest{}
+	mi := &file_mq_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportQueueRequest) ProtoMessage() {}
+
+func (x *ExportQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportQueueRequest.ProtoReflect.Descriptor instead.
+func (*ExportQueueRequest) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ExportQueueRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *ExportQueueRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExportQueueRequest) GetDestructive() bool {
+	if x != nil {
+		return x.Destructive
+	}
+	return false
+}
+
+func (x *ExportQueueRequest) GetMaxMessages() int32 {
+	if x != nil {
+		return x.MaxMessages
+	}
+	return 0
+}
+
+func (x *ExportQueueRequest) GetMaxMsgBytes() int32 {
+	if x != nil {
+		return x.MaxMsgBytes
+	}
+	return 0
+}
+
+type ArchiveChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveChunk) Reset() {
+	*x = ArchiveChunk{}
+	mi := &file_mq_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveChunk) ProtoMessage() {}
+
+func (x *ArchiveChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveChunk.ProtoReflect.Descriptor instead.
+func (*ArchiveChunk) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ArchiveChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ImportQueueRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// queue, format and the options are read from the first message only.
+	Queue  string `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	Format string `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	// Restore identity and origin context (MQPMO_SET_ALL_CONTEXT).
+	SetAllContext bool `protobuf:"varint,3,opt,name=set_all_context,json=setAllContext,proto3" json:"set_all_context,omitempty"`
+	// Give every message a new MsgId.
+	NewMsgId bool `protobuf:"varint,4,opt,name=new_msg_id,json=newMsgId,proto3" json:"new_msg_id,omitempty"`
+	// Archive bytes; the archive may be split across any number of messages.
+	Data          []byte `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportQueueRequest) Reset() {
+	*x = ImportQueueRequest{}
+	mi := &file_mq_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportQueueRequest) ProtoMessage() {}
+
+func (x *ImportQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportQueueRequest.ProtoReflect.Descriptor instead.
+func (*ImportQueueRequest) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ImportQueueRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *ImportQueueRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ImportQueueRequest) GetSetAllContext() bool {
+	if x != nil {
+		return x.SetAllContext
+	}
+	return false
+}
+
+func (x *ImportQueueRequest) GetNewMsgId() bool {
+	if x != nil {
+		return x.NewMsgId
+	}
+	return false
+}
+
+func (x *ImportQueueRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ImportQueueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Imported      int32                  `protobuf:"varint,2,opt,name=imported,proto3" json:"imported,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportQueueResponse) Reset() {
+	*x = ImportQueueResponse{}
+	mi := &file_mq_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportQueueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportQueueResponse) ProtoMessage() {}
+
+func (x *ImportQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportQueueResponse.ProtoReflect.Descriptor instead.
+func (*ImportQueueResponse) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ImportQueueResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ImportQueueResponse) GetImported() int32 {
+	if x != nil {
+		return x.Imported
+	}
+	return 0
+}
+
+func (x *ImportQueueResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type TransferRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Source      string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Destination string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	// Browse and copy instead of moving.
+	Copy bool `protobuf:"varint,3,opt,name=copy,proto3" json:"copy,omitempty"`
+	// Optional filters (ids are hex, selector is SQL92 on message properties).
+	MsgId    string `protobuf:"bytes,4,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"`
+	CorrelId string `protobuf:"bytes,5,opt,name=correl_id,json=correlId,proto3" json:"correl_id,omitempty"`
+	Selector string `protobuf:"bytes,6,opt,name=selector,proto3" json:"selector,omitempty"`
+	// Messages per unit of work; 0 uses the default of 100.
+	BatchSize int32 `protobuf:"varint,7,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	// 0 transfers every matching message.
+	MaxMessages   int32 `protobuf:"varint,8,opt,name=max_messages,json=maxMessages,proto3" json:"max_messages,omitempty"`
+	MaxMsgBytes   int32 `protobuf:"varint,9,opt,name=max_msg_bytes,json=maxMsgBytes,proto3" json:"max_msg_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferRequest) Reset() {
+	*x = TransferRequest{}
+	mi := &file_mq_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferRequest) ProtoMessage() {}
+
+func (x *TransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferRequest.ProtoReflect.Descriptor instead.
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *TransferRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetCopy() bool {
+	if x != nil {
+		return x.Copy
+	}
+	return false
+}
+
+func (x *TransferRequest) GetMsgId() string {
+	if x != nil {
+		return x.MsgId
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetCorrelId() string {
+	if x != nil {
+		return x.CorrelId
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *TransferRequest) GetMaxMessages() int32 {
+	if x != nil {
+		return x.MaxMessages
+	}
+	return 0
+}
+
+func (x *TransferRequest) GetMaxMsgBytes() int32 {
+	if x != nil {
+		return x.MaxMsgBytes
+	}
+	return 0
+}
+
+type TransferProgress struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "progress", "ok" or "error".
+	Status      string `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Transferred int32  `protobuf:"varint,2,opt,name=transferred,proto3" json:"transferred,omitempty"`
+	// False when the destination messages got the gateway's default context.
+	ContextPreserved bool   `protobuf:"varint,3,opt,name=context_preserved,json=contextPreserved,proto3" json:"context_preserved,omitempty"`
+	Error            string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TransferProgress) Reset() {
+	*x = TransferProgress{}
+	mi := &file_mq_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferProgress) ProtoMessage() {}
+
+func (x *TransferProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_mq_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferProgress.ProtoReflect.Descriptor instead.
+func (*TransferProgress) Descriptor() ([]byte, []int) {
+	return file_mq_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *TransferProgress) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TransferProgress) GetTransferred() int32 {
+	if x != nil {
+		return x.Transferred
+	}
+	return 0
+}
+
+func (x *TransferProgress) GetContextPreserved() bool {
+	if x != nil {
+		return x.ContextPreserved
+	}
+	return false
+}
+
+func (x *TransferProgress) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_mq_proto protoreflect.FileDescriptor
+
+const file_mq_proto_rawDesc = "" +
+	"\n" +
+	"\bmq.proto\x12\x04mqpb\x1a\x1cgoogle/api/annotations.proto\"\x81\x02\n" +
+	"\n" +
+	"PutRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x19\n" +
+	"\bgroup_id\x18\x03 \x01(\tR\agroupId\x12$\n" +
+	"\x0emsg_seq_number\x18\x04 \x01(\x05R\fmsgSeqNumber\x12\x16\n" +
+	"\x06offset\x18\x05 \x01(\x05R\x06offset\x12\x1b\n" +
+	"\tmsg_flags\x18\x06 \x01(\x05R\bmsgFlags\x12$\n" +
+	"\x0ereply_to_queue\x18\a \x01(\tR\freplyToQueue\x12'\n" +
+	"\x0fidempotency_key\x18\b \x01(\tR\x0eidempotencyKey\"n\n" +
+	"\vPutResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x15\n" +
+	"\x06msg_id\x18\x03 \x01(\tR\x05msgId\x12\x1a\n" +
+	"\breplayed\x18\x04 \x01(\bR\breplayed\"\x9f\x01\n" +
+	"\n" +
+	"GetRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x17\n" +
+	"\await_ms\x18\x02 \x01(\x05R\x06waitMs\x12\"\n" +
+	"\rmax_msg_bytes\x18\x03 \x01(\x05R\vmaxMsgBytes\x12!\n" +
+	"\fcomplete_msg\x18\x04 \x01(\bR\vcompleteMsg\x12\x1b\n" +
+	"\tcorrel_id\x18\x05 \x01(\tR\bcorrelId\"\x95\x02\n" +
+	"\vGetResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05empty\x18\x03 \x01(\bR\x05empty\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x15\n" +
+	"\x06msg_id\x18\x05 \x01(\tR\x05msgId\x12\x1b\n" +
+	"\tcorrel_id\x18\x06 \x01(\tR\bcorrelId\x12\x19\n" +
+	"\bgroup_id\x18\a \x01(\tR\agroupId\x12$\n" +
+	"\x0emsg_seq_number\x18\b \x01(\x05R\fmsgSeqNumber\x12\x16\n" +
+	"\x06offset\x18\t \x01(\x05R\x06offset\x12\x1b\n" +
+	"\tmsg_flags\x18\n" +
+	" \x01(\x05R\bmsgFlags\"g\n" +
+	"\x12BrowseFirstRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x17\n" +
+	"\await_ms\x18\x02 \x01(\x05R\x06waitMs\x12\"\n" +
+	"\rmax_msg_bytes\x18\x03 \x01(\x05R\vmaxMsgBytes\"m\n" +
+	"\x11BrowseNextRequest\x12\x1b\n" +
+	"\tbrowse_id\x18\x01 \x01(\tR\bbrowseId\x12\x17\n" +
+	"\await_ms\x18\x02 \x01(\x05R\x06waitMs\x12\"\n" +
+	"\rmax_msg_bytes\x18\x03 \x01(\x05R\vmaxMsgBytes\"\x8b\x01\n" +
+	"\x0eBrowseResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05empty\x18\x03 \x01(\bR\x05empty\x12\x1b\n" +
+	"\tbrowse_id\x18\x04 \x01(\tR\bbrowseId\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"1\n" +
+	"\x12CloseBrowseRequest\x12\x1b\n" +
+	"\tbrowse_id\x18\x01 \x01(\tR\bbrowseId\"C\n" +
+	"\x13CloseBrowseResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"+\n" +
+	"\x13InquireQueueRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\"\xc2\x03\n" +
+	"\x14InquireQueueResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
+	"\x05queue\x18\x02 \x01(\tR\x05queue\x12\x1d\n" +
+	"\n" +
+	"queue_desc\x18\x03 \x01(\tR\tqueueDesc\x12\x1d\n" +
+	"\n" +
+	"queue_type\x18\x04 \x01(\x05R\tqueueType\x12\x1f\n" +
+	"\vqueue_usage\x18\x05 \x01(\x05R\n" +
+	"queueUsage\x12'\n" +
+	"\x0fdef_persistence\x18\x06 \x01(\x05R\x0edefPersistence\x12\x1f\n" +
+	"\vinhibit_get\x18\a \x01(\x05R\n" +
+	"inhibitGet\x12\x1f\n" +
+	"\vinhibit_put\x18\b \x01(\x05R\n" +
+	"inhibitPut\x12&\n" +
+	"\x0fcurrent_q_depth\x18\t \x01(\x05R\rcurrentQDepth\x12\x1e\n" +
+	"\vmax_q_depth\x18\n" +
+	" \x01(\x05R\tmaxQDepth\x12(\n" +
+	"\x10open_input_count\x18\v \x01(\x05R\x0eopenInputCount\x12*\n" +
+	"\x11open_output_count\x18\f \x01(\x05R\x0fopenOutputCount\x12\x14\n" +
+	"\x05error\x18\r \x01(\tR\x05error\"C\n" +
+	"\x0fPutGroupRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x1a\n" +
+	"\bmessages\x18\x02 \x03(\tR\bmessages\"[\n" +
+	"\x10PutGroupResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x19\n" +
+	"\bgroup_id\x18\x02 \x01(\tR\agroupId\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"d\n" +
+	"\x0fGetGroupRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x17\n" +
+	"\await_ms\x18\x02 \x01(\x05R\x06waitMs\x12\"\n" +
+	"\rmax_msg_bytes\x18\x03 \x01(\x05R\vmaxMsgBytes\"\x9a\x01\n" +
+	"\fGroupMessage\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x15\n" +
+	"\x06msg_id\x18\x02 \x01(\tR\x05msgId\x12$\n" +
+	"\x0emsg_seq_number\x18\x03 \x01(\x05R\fmsgSeqNumber\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\x12\x1b\n" +
+	"\tmsg_flags\x18\x05 \x01(\x05R\bmsgFlags\"\xa1\x01\n" +
+	"\x10GetGroupResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x19\n" +
+	"\bgroup_id\x18\x02 \x01(\tR\agroupId\x12.\n" +
+	"\bmessages\x18\x03 \x03(\v2\x12.mqpb.GroupMessageR\bmessages\x12\x14\n" +
+	"\x05empty\x18\x04 \x01(\bR\x05empty\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"a\n" +
+	"\x0fPutBatchRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x1a\n" +
+	"\bmessages\x18\x02 \x03(\tR\bmessages\x12\x1c\n" +
+	"\tsyncpoint\x18\x03 \x01(\bR\tsyncpoint\"U\n" +
+	"\x0ePutBatchResult\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x15\n" +
+	"\x06msg_id\x18\x02 \x01(\tR\x05msgId\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"p\n" +
+	"\x10PutBatchResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12.\n" +
+	"\aresults\x18\x02 \x03(\v2\x14.mqpb.PutBatchResultR\aresults\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\xa5\x01\n" +
+	"\x0fGetBatchRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12!\n" +
+	"\fmax_messages\x18\x02 \x01(\x05R\vmaxMessages\x12\x17\n" +
+	"\await_ms\x18\x03 \x01(\x05R\x06waitMs\x12\"\n" +
+	"\rmax_msg_bytes\x18\x04 \x01(\x05R\vmaxMsgBytes\x12\x1c\n" +
+	"\tsyncpoint\x18\x05 \x01(\bR\tsyncpoint\"\x8c\x01\n" +
+	"\x0eGetBatchResult\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x15\n" +
+	"\x06msg_id\x18\x03 \x01(\tR\x05msgId\x12\x1b\n" +
+	"\tcorrel_id\x18\x04 \x01(\tR\bcorrelId\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"\x86\x01\n" +
+	"\x10GetBatchResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12.\n" +
+	"\aresults\x18\x02 \x03(\v2\x14.mqpb.GetBatchResultR\aresults\x12\x14\n" +
+	"\x05empty\x18\x03 \x01(\bR\x05empty\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\xa8\x01\n" +
+	"\x10DLQBrowseRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x18\n" +
+	"\areasons\x18\x02 \x03(\x05R\areasons\x12\x1d\n" +
+	"\n" +
+	"dest_queue\x18\x03 \x01(\tR\tdestQueue\x12!\n" +
+	"\fmax_messages\x18\x04 \x01(\x05R\vmaxMessages\x12\"\n" +
+	"\rmax_msg_bytes\x18\x05 \x01(\x05R\vmaxMsgBytes\"\x90\x02\n" +
+	"\x11DeadLetterMessage\x12\x15\n" +
+	"\x06msg_id\x18\x01 \x01(\tR\x05msgId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\x05R\x06reason\x12\x1f\n" +
+	"\vreason_text\x18\x03 \x01(\tR\n" +
+	"reasonText\x12\x1d\n" +
+	"\n" +
+	"dest_queue\x18\x04 \x01(\tR\tdestQueue\x12\x1b\n" +
+	"\tdest_qmgr\x18\x05 \x01(\tR\bdestQmgr\x12\x16\n" +
+	"\x06format\x18\x06 \x01(\tR\x06format\x12\"\n" +
+	"\rput_appl_name\x18\a \x01(\tR\vputApplName\x12\x19\n" +
+	"\bput_time\x18\b \x01(\tR\aputTime\x12\x18\n" +
+	"\amessage\x18\t \x01(\tR\amessage\"v\n" +
+	"\x11DLQBrowseResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x123\n" +
+	"\bmessages\x18\x02 \x03(\v2\x17.mqpb.DeadLetterMessageR\bmessages\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\xe3\x01\n" +
+	"\x10DLQReplayRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x18\n" +
+	"\areasons\x18\x02 \x03(\x05R\areasons\x12\x1d\n" +
+	"\n" +
+	"dest_queue\x18\x03 \x01(\tR\tdestQueue\x12 \n" +
+	"\vdestination\x18\x04 \x01(\tR\vdestination\x12!\n" +
+	"\fmax_messages\x18\x05 \x01(\x05R\vmaxMessages\x12\"\n" +
+	"\rmax_msg_bytes\x18\x06 \x01(\x05R\vmaxMsgBytes\x12\x17\n" +
+	"\adry_run\x18\a \x01(\bR\x06dryRun\"\xc6\x01\n" +
+	"\x0fDLQReplayResult\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x15\n" +
+	"\x06msg_id\x18\x02 \x01(\tR\x05msgId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\x05R\x06reason\x12\x1f\n" +
+	"\vreason_text\x18\x04 \x01(\tR\n" +
+	"reasonText\x12\x1d\n" +
+	"\n" +
+	"dest_queue\x18\x05 \x01(\tR\tdestQueue\x12\x16\n" +
+	"\x06target\x18\x06 \x01(\tR\x06target\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\"\x8b\x01\n" +
+	"\x11DLQReplayResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\x12/\n" +
+	"\aresults\x18\x03 \x03(\v2\x15.mqpb.DLQReplayResultR\aresults\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\xa1\x01\n" +
+	"\x0fScheduleRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1d\n" +
+	"\n" +
+	"deliver_at\x18\x03 \x01(\tR\tdeliverAt\x12\x19\n" +
+	"\bdelay_ms\x18\x04 \x01(\x05R\adelayMs\x12$\n" +
+	"\x0ereply_to_queue\x18\x05 \x01(\tR\freplyToQueue\"\x80\x01\n" +
+	"\x10ScheduleResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x1f\n" +
+	"\vschedule_id\x18\x03 \x01(\tR\n" +
+	"scheduleId\x12\x1d\n" +
+	"\n" +
+	"deliver_at\x18\x04 \x01(\tR\tdeliverAt\"s\n" +
+	"\x14ListScheduledRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12!\n" +
+	"\fmax_messages\x18\x02 \x01(\x05R\vmaxMessages\x12\"\n" +
+	"\rmax_msg_bytes\x18\x03 \x01(\x05R\vmaxMsgBytes\"\xa8\x01\n" +
+	"\x10ScheduledMessage\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\tR\n" +
+	"scheduleId\x12\x14\n" +
+	"\x05queue\x18\x02 \x01(\tR\x05queue\x12\x1d\n" +
+	"\n" +
+	"deliver_at\x18\x03 \x01(\tR\tdeliverAt\x12$\n" +
+	"\x0ereply_to_queue\x18\x04 \x01(\tR\freplyToQueue\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\"y\n" +
+	"\x15ListScheduledResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x122\n" +
+	"\bmessages\x18\x02 \x03(\v2\x16.mqpb.ScheduledMessageR\bmessages\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"O\n" +
+	"\x16CancelScheduledRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x1f\n" +
+	"\vschedule_id\x18\x02 \x01(\tR\n" +
+	"scheduleId\"G\n" +
+	"\x17CancelScheduledResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\xab\x01\n" +
+	"\x12ExportQueueRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x12 \n" +
+	"\vdestructive\x18\x03 \x01(\bR\vdestructive\x12!\n" +
+	"\fmax_messages\x18\x04 \x01(\x05R\vmaxMessages\x12\"\n" +
+	"\rmax_msg_bytes\x18\x05 \x01(\x05R\vmaxMsgBytes\"\"\n" +
+	"\fArchiveChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"\x9c\x01\n" +
+	"\x12ImportQueueRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x12&\n" +
+	"\x0fset_all_context\x18\x03 \x01(\bR\rsetAllContext\x12\x1c\n" +
+	"\n" +
+	"new_msg_id\x18\x04 \x01(\bR\bnewMsgId\x12\x12\n" +
+	"\x04data\x18\x05 \x01(\fR\x04data\"_\n" +
+	"\x13ImportQueueResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1a\n" +
+	"\bimported\x18\x02 \x01(\x05R\bimported\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\x95\x02\n" +
+	"\x0fTransferRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\x12\x12\n" +
+	"\x04copy\x18\x03 \x01(\bR\x04copy\x12\x15\n" +
+	"\x06msg_id\x18\x04 \x01(\tR\x05msgId\x12\x1b\n" +
+	"\tcorrel_id\x18\x05 \x01(\tR\bcorrelId\x12\x1a\n" +
+	"\bselector\x18\x06 \x01(\tR\bselector\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\a \x01(\x05R\tbatchSize\x12!\n" +
+	"\fmax_messages\x18\b \x01(\x05R\vmaxMessages\x12\"\n" +
+	"\rmax_msg_bytes\x18\t \x01(\x05R\vmaxMsgBytes\"\x8f\x01\n" +
+	"\x10TransferProgress\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12 \n" +
+	"\vtransferred\x18\x02 \x01(\x05R\vtransferred\x12+\n" +
+	"\x11context_preserved\x18\x03 \x01(\bR\x10contextPreserved\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error2\xa2\r\n" +
+	"\x0eMqGrpcServices\x12R\n" +
+	"\x03Put\x12\x10.mqpb.PutRequest\x1a\x11.mqpb.PutResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v2/queues/{queue}/messages\x12T\n" +
+	"\x03Get\x12\x10.mqpb.GetRequest\x1a\x11.mqpb.GetResponse\"(\x82\xd3\xe4\x93\x02\"* /v2/queues/{queue}/messages/next\x12`\n" +
+	"\vBrowseFirst\x12\x18.mqpb.BrowseFirstRequest\x1a\x14.mqpb.BrowseResponse\"!\x82\xd3\xe4\x93\x02\x1b\"\x19/v2/queues/{queue}/browse\x12[\n" +
 	"\n" +
-	"BrowseNext\x12\x17.mqpb.BrowseNextRequest\x1a\x14.mqpb.BrowseResponse\"\x00\x12G\n" +
-	"\fInquireQueue\x12\x19.mqpb.InquireQueueRequest\x1a\x1a.mqpb.InquireQueueResponse\"\x00B\x0fZ\r./mq_grpc_apib\x06proto3"
+	"BrowseNext\x12\x17.mqpb.BrowseNextRequest\x1a\x14.mqpb.BrowseResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/v2/browse/{browse_id}\x12b\n" +
+	"\vCloseBrowse\x12\x18.mqpb.CloseBrowseRequest\x1a\x19.mqpb.CloseBrowseResponse\"\x1e\x82\xd3\xe4\x93\x02\x18*\x16/v2/browse/{browse_id}\x12a\n" +
+	"\fInquireQueue\x12\x19.mqpb.InquireQueueRequest\x1a\x1a.mqpb.InquireQueueResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v2/queues/{queue}\x12_\n" +
+	"\bPutGroup\x12\x15.mqpb.PutGroupRequest\x1a\x16.mqpb.PutGroupResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v2/queues/{queue}/groups\x12a\n" +
+	"\bGetGroup\x12\x15.mqpb.GetGroupRequest\x1a\x16.mqpb.GetGroupResponse\"&\x82\xd3\xe4\x93\x02 *\x1e/v2/queues/{queue}/groups/next\x12`\n" +
+	"\bPutBatch\x12\x15.mqpb.PutBatchRequest\x1a\x16.mqpb.PutBatchResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v2/queues/{queue}/batches\x12b\n" +
+	"\bGetBatch\x12\x15.mqpb.GetBatchRequest\x1a\x16.mqpb.GetBatchResponse\"'\x82\xd3\xe4\x93\x02!*\x1f/v2/queues/{queue}/batches/next\x12M\n" +
+	"\tBrowseDLQ\x12\x16.mqpb.DLQBrowseRequest\x1a\x17.mqpb.DLQBrowseResponse\"\x0f\x82\xd3\xe4\x93\x02\t\x12\a/v2/dlq\x12W\n" +
+	"\tReplayDLQ\x12\x16.mqpb.DLQReplayRequest\x1a\x17.mqpb.DLQReplayResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v2/dlq/replay\x12i\n" +
+	"\x0fScheduleMessage\x12\x15.mqpb.ScheduleRequest\x1a\x16.mqpb.ScheduleResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v2/queues/{queue}/scheduled\x12n\n" +
+	"\rListScheduled\x12\x1a.mqpb.ListScheduledRequest\x1a\x1b.mqpb.ListScheduledResponse\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/v2/queues/{queue}/scheduled\x12\x82\x01\n" +
+	"\x0fCancelScheduled\x12\x1c.mqpb.CancelScheduledRequest\x1a\x1d.mqpb.CancelScheduledResponse\"2\x82\xd3\xe4\x93\x02,**/v2/queues/{queue}/scheduled/{schedule_id}\x12?\n" +
+	"\vExportQueue\x12\x18.mqpb.ExportQueueRequest\x1a\x12.mqpb.ArchiveChunk\"\x000\x01\x12F\n" +
+	"\vImportQueue\x12\x18.mqpb.ImportQueueRequest\x1a\x19.mqpb.ImportQueueResponse\"\x00(\x01\x12E\n" +
+	"\x10TransferMessages\x12\x15.mqpb.TransferRequest\x1a\x16.mqpb.TransferProgress\"\x000\x01B\x0fZ\r./mq_grpc_apib\x06proto3"
 
 var (
 	file_mq_proto_rawDescOnce sync.Once
@@ -711,34 +3273,98 @@ func file_mq_proto_rawDescGZIP() []byte {
 	return file_mq_proto_rawDescData
 }
 
-var file_mq_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_mq_proto_msgTypes = make([]protoimpl.MessageInfo, 41)
 var file_mq_proto_goTypes = []any{
-	(*PutRequest)(nil),           // 0: mqpb.PutRequest
-	(*PutResponse)(nil),          // 1: mqpb.PutResponse
-	(*GetRequest)(nil),           // 2: mqpb.GetRequest
-	(*GetResponse)(nil),          // 3: mqpb.GetResponse
-	(*BrowseFirstRequest)(nil),   // 4: mqpb.BrowseFirstRequest
-	(*BrowseNextRequest)(nil),    // 5: mqpb.BrowseNextRequest
-	(*BrowseResponse)(nil),       // 6: mqpb.BrowseResponse
-	(*InquireQueueRequest)(nil),  // 7: mqpb.InquireQueueRequest
-	(*InquireQueueResponse)(nil), // 8: mqpb.InquireQueueResponse
+	(*PutRequest)(nil),              // 0: mqpb.PutRequest
+	(*PutResponse)(nil),             // 1: mqpb.PutResponse
+	(*GetRequest)(nil),              // 2: mqpb.GetRequest
+	(*GetResponse)(nil),             // 3: mqpb.GetResponse
+	(*BrowseFirstRequest)(nil),      // 4: mqpb.BrowseFirstRequest
+	(*BrowseNextRequest)(nil),       // 5: mqpb.BrowseNextRequest
+	(*BrowseResponse)(nil),          // 6: mqpb.BrowseResponse
+	(*CloseBrowseRequest)(nil),      // 7: mqpb.CloseBrowseRequest
+	(*CloseBrowseResponse)(nil),     // 8: mqpb.CloseBrowseResponse
+	(*InquireQueueRequest)(nil),     // 9: mqpb.InquireQueueRequest
+	(*InquireQueueResponse)(nil),    // 10: mqpb.InquireQueueResponse
+	(*PutGroupRequest)(nil),         // 11: mqpb.PutGroupRequest
+	(*PutGroupResponse)(nil),        // 12: mqpb.PutGroupResponse
+	(*GetGroupRequest)(nil),         // 13: mqpb.GetGroupRequest
+	(*GroupMessage)(nil),            // 14: mqpb.GroupMessage
+	(*GetGroupResponse)(nil),        // 15: mqpb.GetGroupResponse
+	(*PutBatchRequest)(nil),         // 16: mqpb.PutBatchRequest
+	(*PutBatchResult)(nil),          // 17: mqpb.PutBatchResult
+	(*PutBatchResponse)(nil),        // 18: mqpb.PutBatchResponse
+	(*GetBatchRequest)(nil),         // 19: mqpb.GetBatchRequest
+	(*GetBatchResult)(nil),          // 20: mqpb.GetBatchResult
+	(*GetBatchResponse)(nil),        // 21: mqpb.GetBatchResponse
+	(*DLQBrowseRequest)(nil),        // 22: mqpb.DLQBrowseRequest
+	(*DeadLetterMessage)(nil),       // 23: mqpb.DeadLetterMessage
+	(*DLQBrowseResponse)(nil),       // 24: mqpb.DLQBrowseResponse
+	(*DLQReplayRequest)(nil),        // 25: mqpb.DLQReplayRequest
+	(*DLQReplayResult)(nil),         // 26: mqpb.DLQReplayResult
+	(*DLQReplayResponse)(nil),       // 27: mqpb.DLQReplayResponse
+	(*ScheduleRequest)(nil),         // 28: mqpb.ScheduleRequest
+	(*ScheduleResponse)(nil),        // 29: mqpb.ScheduleResponse
+	(*ListScheduledRequest)(nil),    // 30: mqpb.ListScheduledRequest
+	(*ScheduledMessage)(nil),        // 31: mqpb.ScheduledMessage
+	(*ListScheduledResponse)(nil),   // 32: mqpb.ListScheduledResponse
+	(*CancelScheduledRequest)(nil),  // 33: mqpb.CancelScheduledRequest
+	(*CancelScheduledResponse)(nil), // 34: mqpb.CancelScheduledResponse
+	(*ExportQueueRequest)(nil),      // 35: mqpb.ExportQueueRequest
+	(*ArchiveChunk)(nil),            // 36: mqpb.ArchiveChunk
+	(*ImportQueueRequest)(nil),      // 37: mqpb.ImportQueueRequest
+	(*ImportQueueResponse)(nil),     // 38: mqpb.ImportQueueResponse
+	(*TransferRequest)(nil),         // 39: mqpb.TransferRequest
+	(*TransferProgress)(nil),        // 40: mqpb.TransferProgress
 }
 var file_mq_proto_depIdxs = []int32{
-	0, // 0: mqpb.MqGrpcServices.Put:input_type -> mqpb.PutRequest
-	2, // 1: mqpb.MqGrpcServices.Get:input_type -> mqpb.GetRequest
-	4, // 2: mqpb.MqGrpcServices.BrowseFirst:input_type -> mqpb.BrowseFirstRequest
-	5, // 3: mqpb.MqGrpcServices.BrowseNext:input_type -> mqpb.BrowseNextRequest
-	7, // 4: mqpb.MqGrpcServices.InquireQueue:input_type -> mqpb.InquireQueueRequest
-	1, // 5: mqpb.MqGrpcServices.Put:output_type -> mqpb.PutResponse
-	3, // 6: mqpb.MqGrpcServices.Get:output_type -> mqpb.GetResponse
-	6, // 7: mqpb.MqGrpcServices.BrowseFirst:output_type -> mqpb.BrowseResponse
-	6, // 8: mqpb.MqGrpcServices.BrowseNext:output_type -> mqpb.BrowseResponse
-	8, // 9: mqpb.MqGrpcServices.InquireQueue:output_type -> mqpb.InquireQueueResponse
-	5, // [5:10] is the sub-list for method output_type
-	0, // [0:5] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	14, // 0: mqpb.GetGroupResponse.messages:type_name -> mqpb.GroupMessage
+	17, // 1: mqpb.PutBatchResponse.results:type_name -> mqpb.PutBatchResult
+	20, // 2: mqpb.GetBatchResponse.results:type_name -> mqpb.GetBatchResult
+	23, // 3: mqpb.DLQBrowseResponse.messages:type_name -> mqpb.DeadLetterMessage
+	26, // 4: mqpb.DLQReplayResponse.results:type_name -> mqpb.DLQReplayResult
+	31, // 5: mqpb.ListScheduledResponse.messages:type_name -> mqpb.ScheduledMessage
+	0,  // 6: mqpb.MqGrpcServices.Put:input_type -> mqpb.PutRequest
+	2,  // 7: mqpb.MqGrpcServices.Get:input_type -> mqpb.GetRequest
+	4,  // 8: mqpb.MqGrpcServices.BrowseFirst:input_type -> mqpb.BrowseFirstRequest
+	5,  // 9: mqpb.MqGrpcServices.BrowseNext:input_type -> mqpb.BrowseNextRequest
+	7,  // 10: mqpb.MqGrpcServices.CloseBrowse:input_type -> mqpb.CloseBrowseRequest
+	9,  // 11: mqpb.MqGrpcServices.InquireQueue:input_type -> mqpb.InquireQueueRequest
+	11, // 12: mqpb.MqGrpcServices.PutGroup:input_type -> mqpb.PutGroupRequest
+	13, // 13: mqpb.MqGrpcServices.GetGroup:input_type -> mqpb.GetGroupRequest
+	16, // 14: mqpb.MqGrpcServices.PutBatch:input_type -> mqpb.PutBatchRequest
+	19, // 15: mqpb.MqGrpcServices.GetBatch:input_type -> mqpb.GetBatchRequest
+	22, // 16: mqpb.MqGrpcServices.BrowseDLQ:input_type -> mqpb.DLQBrowseRequest
+	25, // 17: mqpb.MqGrpcServices.ReplayDLQ:input_type -> mqpb.DLQReplayRequest
+	28, // 18: mqpb.MqGrpcServices.ScheduleMessage:input_type -> mqpb.ScheduleRequest
+	30, // 19: mqpb.MqGrpcServices.ListScheduled:input_type -> mqpb.ListScheduledRequest
+	33, // 20: mqpb.MqGrpcServices.CancelScheduled:input_type -> mqpb.CancelScheduledRequest
+	35, // 21: mqpb.MqGrpcServices.ExportQueue:input_type -> mqpb.ExportQueueRequest
+	37, // 22: mqpb.MqGrpcServices.ImportQueue:input_type -> mqpb.ImportQueueRequest
+	39, // 23: mqpb.MqGrpcServices.TransferMessages:input_type -> mqpb.TransferRequest
+	1,  // 24: mqpb.MqGrpcServices.Put:output_type -> mqpb.PutResponse
+	3,  // 25: mqpb.MqGrpcServices.Get:output_type -> mqpb.GetResponse
+	6,  // 26: mqpb.MqGrpcServices.BrowseFirst:output_type -> mqpb.BrowseResponse
+	6,  // 27: mqpb.MqGrpcServices.BrowseNext:output_type -> mqpb.BrowseResponse
+	8,  // 28: mqpb.MqGrpcServices.CloseBrowse:output_type -> mqpb.CloseBrowseResponse
+	10, // 29: mqpb.MqGrpcServices.InquireQueue:output_type -> mqpb.InquireQueueResponse
+	12, // 30: mqpb.MqGrpcServices.PutGroup:output_type -> mqpb.PutGroupResponse
+	15, // 31: mqpb.MqGrpcServices.GetGroup:output_type -> mqpb.GetGroupResponse
+	18, // 32: mqpb.MqGrpcServices.PutBatch:output_type -> mqpb.PutBatchResponse
+	21, // 33: mqpb.MqGrpcServices.GetBatch:output_type -> mqpb.GetBatchResponse
+	24, // 34: mqpb.MqGrpcServices.BrowseDLQ:output_type -> mqpb.DLQBrowseResponse
+	27, // 35: mqpb.MqGrpcServices.ReplayDLQ:output_type -> mqpb.DLQReplayResponse
+	29, // 36: mqpb.MqGrpcServices.ScheduleMessage:output_type -> mqpb.ScheduleResponse
+	32, // 37: mqpb.MqGrpcServices.ListScheduled:output_type -> mqpb.ListScheduledResponse
+	34, // 38: mqpb.MqGrpcServices.CancelScheduled:output_type -> mqpb.CancelScheduledResponse
+	36, // 39: mqpb.MqGrpcServices.ExportQueue:output_type -> mqpb.ArchiveChunk
+	38, // 40: mqpb.MqGrpcServices.ImportQueue:output_type -> mqpb.ImportQueueResponse
+	40, // 41: mqpb.MqGrpcServices.TransferMessages:output_type -> mqpb.TransferProgress
+	24, // [24:42] is the sub-list for method output_type
+	6,  // [6:24] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_mq_proto_init() }
@@ -752,7 +3378,7 @@ func file_mq_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mq_proto_rawDesc), len(file_mq_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   41,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,22 +19,60 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MqGrpcServices_Put_FullMethodName          = "/mqpb.MqGrpcServices/Put"
-	MqGrpcServices_Get_FullMethodName          = "/mqpb.MqGrpcServices/Get"
-	MqGrpcServices_BrowseFirst_FullMethodName  = "/mqpb.MqGrpcServices/BrowseFirst"
-	MqGrpcServices_BrowseNext_FullMethodName   = "/mqpb.MqGrpcServices/BrowseNext"
-	MqGrpcServices_InquireQueue_FullMethodName = "/mqpb.MqGrpcServices/InquireQueue"
+	MqGrpcServices_Put_FullMethodName              = "/mqpb.MqGrpcServices/Put"
+	MqGrpcServices_Get_FullMethodName              = "/mqpb.MqGrpcServices/Get"
+	MqGrpcServices_BrowseFirst_FullMethodName      = "/mqpb.MqGrpcServices/BrowseFirst"
+	MqGrpcServices_BrowseNext_FullMethodName       = "/mqpb.MqGrpcServices/BrowseNext"
+	MqGrpcServices_CloseBrowse_FullMethodName      = "/mqpb.MqGrpcServices/CloseBrowse"
+	MqGrpcServices_InquireQueue_FullMethodName     = "/mqpb.MqGrpcServices/InquireQueue"
+	MqGrpcServices_PutGroup_FullMethodName         = "/mqpb.MqGrpcServices/PutGroup"
+	MqGrpcServices_GetGroup_FullMethodName         = "/mqpb.MqGrpcServices/GetGroup"
+	MqGrpcServices_PutBatch_FullMethodName         = "/mqpb.MqGrpcServices/PutBatch"
+	MqGrpcServices_GetBatch_FullMethodName         = "/mqpb.MqGrpcServices/GetBatch"
+	MqGrpcServices_BrowseDLQ_FullMethodName        = "/mqpb.MqGrpcServices/BrowseDLQ"
+	MqGrpcServices_ReplayDLQ_FullMethodName        = "/mqpb.MqGrpcServices/ReplayDLQ"
+	MqGrpcServices_ScheduleMessage_FullMethodName  = "/mqpb.MqGrpcServices/ScheduleMessage"
+	MqGrpcServices_ListScheduled_FullMethodName    = "/mqpb.MqGrpcServices/ListScheduled"
+	MqGrpcServices_CancelScheduled_FullMethodName  = "/mqpb.MqGrpcServices/CancelScheduled"
+	MqGrpcServices_ExportQueue_FullMethodName      = "/mqpb.MqGrpcServices/ExportQueue"
+	MqGrpcServices_ImportQueue_FullMethodName      = "/mqpb.MqGrpcServices/ImportQueue"
+	MqGrpcServices_TransferMessages_FullMethodName = "/mqpb.MqGrpcServices/TransferMessages"
 )
 
 // MqGrpcServicesClient is the client API for MqGrpcServices service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Unary RPCs are also served as REST under /v2 by the in-process gateway;
+// the google.api.http options below define their routes. Fields not bound
+// to the path or body are read from the query string.
 type MqGrpcServicesClient interface {
 	Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error)
 	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
 	BrowseFirst(ctx context.Context, in *BrowseFirstRequest, opts ...grpc.CallOption) (*BrowseResponse, error)
 	BrowseNext(ctx context.Context, in *BrowseNextRequest, opts ...grpc.CallOption) (*BrowseResponse, error)
+	// Closes a browse cursor instead of waiting for it to expire.
+	CloseBrowse(ctx context.Context, in *CloseBrowseRequest, opts ...grpc.CallOption) (*CloseBrowseResponse, error)
 	InquireQueue(ctx context.Context, in *InquireQueueRequest, opts ...grpc.CallOption) (*InquireQueueResponse, error)
+	PutGroup(ctx context.Context, in *PutGroupRequest, opts ...grpc.CallOption) (*PutGroupResponse, error)
+	GetGroup(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (*GetGroupResponse, error)
+	PutBatch(ctx context.Context, in *PutBatchRequest, opts ...grpc.CallOption) (*PutBatchResponse, error)
+	GetBatch(ctx context.Context, in *GetBatchRequest, opts ...grpc.CallOption) (*GetBatchResponse, error)
+	BrowseDLQ(ctx context.Context, in *DLQBrowseRequest, opts ...grpc.CallOption) (*DLQBrowseResponse, error)
+	ReplayDLQ(ctx context.Context, in *DLQReplayRequest, opts ...grpc.CallOption) (*DLQReplayResponse, error)
+	// Stages a message for delivery to queue at a later time.
+	ScheduleMessage(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*ScheduleResponse, error)
+	// Lists the messages still waiting for delivery to queue.
+	ListScheduled(ctx context.Context, in *ListScheduledRequest, opts ...grpc.CallOption) (*ListScheduledResponse, error)
+	// Removes a message before it is delivered.
+	CancelScheduled(ctx context.Context, in *CancelScheduledRequest, opts ...grpc.CallOption) (*CancelScheduledResponse, error)
+	// Streams a queue archive (JSONL or tar) in chunks.
+	ExportQueue(ctx context.Context, in *ExportQueueRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArchiveChunk], error)
+	// Reads an archive streamed in chunks and re-puts its messages.
+	ImportQueue(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportQueueRequest, ImportQueueResponse], error)
+	// Moves or copies messages between queues, reporting progress after each
+	// committed batch. The last message has status "ok" or "error".
+	TransferMessages(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TransferProgress], error)
 }
 
 type mqGrpcServicesClient struct {
@@ -85,6 +123,16 @@ func (c *mqGrpcServicesClient) BrowseNext(ctx context.Context, in *BrowseNextReq
 	return out, nil
 }
 
+func (c *mqGrpcServicesClient) CloseBrowse(ctx context.Context, in *CloseBrowseRequest, opts ...grpc.CallOption) (*CloseBrowseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloseBrowseResponse)
+	err := c.cc.Invoke(ctx, MqGrpcServices_CloseBrowse_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mqGrpcServicesClient) InquireQueue(ctx context.Context, in *InquireQueueRequest, opts ...grpc.CallOption) (*InquireQueueResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(InquireQueueResponse)
@@ -95,15 +143,181 @@ func (c *mqGrpcServicesClient) InquireQueue(ctx context.Context, in *InquireQueu
 	return out, nil
 }
 
+func (c *mqGrpcServicesClient) PutGroup(ctx context.Context, in *PutGroupRequest, opts ...grpc.CallOption) (*PutGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PutGroupResponse)
+	err := c.cc.Invoke(ctx, MqGrpcServices_PutGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mqGrpcServicesClient) GetGroup(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (*GetGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetGroupResponse)
+	err := c.cc.Invoke(ctx, MqGrpcServices_GetGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mqGrpcServicesClient) PutBatch(ctx context.Context, in *PutBatchRequest, opts ...grpc.CallOption) (*PutBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PutBatchResponse)
+	err := c.cc.Invoke(ctx, MqGrpcServices_PutBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mqGrpcServicesClient) GetBatch(ctx context.Context, in *GetBatchRequest, opts ...grpc.CallOption) (*GetBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBatchResponse)
+	err := c.cc.Invoke(ctx, MqGrpcServices_GetBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mqGrpcServicesClient) BrowseDLQ(ctx context.Context, in *DLQBrowseRequest, opts ...grpc.CallOption) (*DLQBrowseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DLQBrowseResponse)
+	err := c.cc.Invoke(ctx, MqGrpcServices_BrowseDLQ_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mqGrpcServicesClient) ReplayDLQ(ctx context.Context, in *DLQReplayRequest, opts ...grpc.CallOption) (*DLQReplayResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DLQReplayResponse)
+	err := c.cc.Invoke(ctx, MqGrpcServices_ReplayDLQ_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mqGrpcServicesClient) ScheduleMessage(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*ScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScheduleResponse)
+	err := c.cc.Invoke(ctx, MqGrpcServices_ScheduleMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mqGrpcServicesClient) ListScheduled(ctx context.Context, in *ListScheduledRequest, opts ...grpc.CallOption) (*ListScheduledResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListScheduledResponse)
+	err := c.cc.Invoke(ctx, MqGrpcServices_ListScheduled_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mqGrpcServicesClient) CancelScheduled(ctx context.Context, in *CancelScheduledRequest, opts ...grpc.CallOption) (*CancelScheduledResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelScheduledResponse)
+	err := c.cc.Invoke(ctx, MqGrpcServices_CancelScheduled_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mqGrpcServicesClient) ExportQueue(ctx context.Context, in *ExportQueueRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArchiveChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MqGrpcServices_ServiceDesc.Streams[0], MqGrpcServices_ExportQueue_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportQueueRequest, ArchiveChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MqGrpcServices_ExportQueueClient = grpc.ServerStreamingClient[ArchiveChunk]
+
+func (c *mqGrpcServicesClient) ImportQueue(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportQueueRequest, ImportQueueResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MqGrpcServices_ServiceDesc.Streams[1], MqGrpcServices_ImportQueue_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportQueueRequest, ImportQueueResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MqGrpcServices_ImportQueueClient = grpc.ClientStreamingClient[ImportQueueRequest, ImportQueueResponse]
+
+func (c *mqGrpcServicesClient) TransferMessages(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TransferProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MqGrpcServices_ServiceDesc.Streams[2], MqGrpcServices_TransferMessages_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TransferRequest, TransferProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MqGrpcServices_TransferMessagesClient = grpc.ServerStreamingClient[TransferProgress]
+
 // MqGrpcServicesServer is the server API for MqGrpcServices service.
 // All implementations must embed UnimplementedMqGrpcServicesServer
 // for forward compatibility.
+//
+// Unary RPCs are also served as REST under /v2 by the in-process gateway;
+// the google.api.http options below define their routes. Fields not bound
+// to the path or body are read from the query string.
 type MqGrpcServicesServer interface {
 	Put(context.Context, *PutRequest) (*PutResponse, error)
 	Get(context.Context, *GetRequest) (*GetResponse, error)
 	BrowseFirst(context.Context, *BrowseFirstRequest) (*BrowseResponse, error)
 	BrowseNext(context.Context, *BrowseNextRequest) (*BrowseResponse, error)
+	// Closes a browse cursor instead of waiting for it to expire.
+	CloseBrowse(context.Context, *CloseBrowseRequest) (*CloseBrowseResponse, error)
 	InquireQueue(context.Context, *InquireQueueRequest) (*InquireQueueResponse, error)
+	PutGroup(context.Context, *PutGroupRequest) (*PutGroupResponse, error)
+	GetGroup(context.Context, *GetGroupRequest) (*GetGroupResponse, error)
+	PutBatch(context.Context, *PutBatchRequest) (*PutBatchResponse, error)
+	GetBatch(context.Context, *GetBatchRequest) (*GetBatchResponse, error)
+	BrowseDLQ(context.Context, *DLQBrowseRequest) (*DLQBrowseResponse, error)
+	ReplayDLQ(context.Context, *DLQReplayRequest) (*DLQReplayResponse, error)
+	// Stages a message for delivery to queue at a later time.
+	ScheduleMessage(context.Context, *ScheduleRequest) (*ScheduleResponse, error)
+	// Lists the messages still waiting for delivery to queue.
+	ListScheduled(context.Context, *ListScheduledRequest) (*ListScheduledResponse, error)
+	// Removes a message before it is delivered.
+	CancelScheduled(context.Context, *CancelScheduledRequest) (*CancelScheduledResponse, error)
+	// Streams a queue archive (JSONL or tar) in chunks.
+	ExportQueue(*ExportQueueRequest, grpc.ServerStreamingServer[ArchiveChunk]) error
+	// Reads an archive streamed in chunks and re-puts its messages.
+	ImportQueue(grpc.ClientStreamingServer[ImportQueueRequest, ImportQueueResponse]) error
+	// Moves or copies messages between queues, reporting progress after each
+	// committed batch. The last message has status "ok" or "error".
+	TransferMessages(*TransferRequest, grpc.ServerStreamingServer[TransferProgress]) error
 	mustEmbedUnimplementedMqGrpcServicesServer()
 }
 
@@ -126,9 +340,48 @@ func (UnimplementedMqGrpcServicesServer) BrowseFirst(context.Context, *BrowseFir
 func (UnimplementedMqGrpcServicesServer) BrowseNext(context.Context, *BrowseNextRequest) (*BrowseResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method BrowseNext not implemented")
 }
+func (UnimplementedMqGrpcServicesServer) CloseBrowse(context.Context, *CloseBrowseRequest) (*CloseBrowseResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CloseBrowse not implemented")
+}
 func (UnimplementedMqGrpcServicesServer) InquireQueue(context.Context, *InquireQueueRequest) (*InquireQueueResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method InquireQueue not implemented")
 }
+func (UnimplementedMqGrpcServicesServer) PutGroup(context.Context, *PutGroupRequest) (*PutGroupResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PutGroup not implemented")
+}
+func (UnimplementedMqGrpcServicesServer) GetGroup(context.Context, *GetGroupRequest) (*GetGroupResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetGroup not implemented")
+}
+func (UnimplementedMqGrpcServicesServer) PutBatch(context.Context, *PutBatchRequest) (*PutBatchResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PutBatch not implemented")
+}
+func (UnimplementedMqGrpcServicesServer) GetBatch(context.Context, *GetBatchRequest) (*GetBatchResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetBatch not implemented")
+}
+func (UnimplementedMqGrpcServicesServer) BrowseDLQ(context.Context, *DLQBrowseRequest) (*DLQBrowseResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BrowseDLQ not implemented")
+}
+func (UnimplementedMqGrpcServicesServer) ReplayDLQ(context.Context, *DLQReplayRequest) (*DLQReplayResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReplayDLQ not implemented")
+}
+func (UnimplementedMqGrpcServicesServer) ScheduleMessage(context.Context, *ScheduleRequest) (*ScheduleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ScheduleMessage not implemented")
+}
+func (UnimplementedMqGrpcServicesServer) ListScheduled(context.Context, *ListScheduledRequest) (*ListScheduledResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListScheduled not implemented")
+}
+func (UnimplementedMqGrpcServicesServer) CancelScheduled(context.Context, *CancelScheduledRequest) (*CancelScheduledResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelScheduled not implemented")
+}
+func (UnimplementedMqGrpcServicesServer) ExportQueue(*ExportQueueRequest, grpc.ServerStreamingServer[ArchiveChunk]) error {
+	return status.Error(codes.Unimplemented, "method ExportQueue not implemented")
+}
+func (UnimplementedMqGrpcServicesServer) ImportQueue(grpc.ClientStreamingServer[ImportQueueRequest, ImportQueueResponse]) error {
+	return status.Error(codes.Unimplemented, "method ImportQueue not implemented")
+}
+func (UnimplementedMqGrpcServicesServer) TransferMessages(*TransferRequest, grpc.ServerStreamingServer[TransferProgress]) error {
+	return status.Error(codes.Unimplemented, "method TransferMessages not implemented")
+}
 func (UnimplementedMqGrpcServicesServer) mustEmbedUnimplementedMqGrpcServicesServer() {}
 func (UnimplementedMqGrpcServicesServer) testEmbeddedByValue()                        {}
 
@@ -222,6 +475,24 @@ func _MqGrpcServices_BrowseNext_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MqGrpcServices_CloseBrowse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseBrowseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MqGrpcServicesServer).CloseBrowse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MqGrpcServices_CloseBrowse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MqGrpcServicesServer).CloseBrowse(ctx, req.(*CloseBrowseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MqGrpcServices_InquireQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(InquireQueueRequest)
 	if err := dec(in); err != nil {
@@ -240,6 +511,197 @@ func _MqGrpcServices_InquireQueue_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MqGrpcServices_PutGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MqGrpcServicesServer).PutGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MqGrpcServices_PutGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MqGrpcServicesServer).PutGroup(ctx, req.(*PutGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MqGrpcServices_GetGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MqGrpcServicesServer).GetGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MqGrpcServices_GetGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MqGrpcServicesServer).GetGroup(ctx, req.(*GetGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MqGrpcServices_PutBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MqGrpcServicesServer).PutBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MqGrpcServices_PutBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MqGrpcServicesServer).PutBatch(ctx, req.(*PutBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MqGrpcServices_GetBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MqGrpcServicesServer).GetBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MqGrpcServices_GetBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MqGrpcServicesServer).GetBatch(ctx, req.(*GetBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MqGrpcServices_BrowseDLQ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DLQBrowseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MqGrpcServicesServer).BrowseDLQ(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MqGrpcServices_BrowseDLQ_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MqGrpcServicesServer).BrowseDLQ(ctx, req.(*DLQBrowseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MqGrpcServices_ReplayDLQ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DLQReplayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MqGrpcServicesServer).ReplayDLQ(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MqGrpcServices_ReplayDLQ_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MqGrpcServicesServer).ReplayDLQ(ctx, req.(*DLQReplayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MqGrpcServices_ScheduleMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MqGrpcServicesServer).ScheduleMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MqGrpcServices_ScheduleMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MqGrpcServicesServer).ScheduleMessage(ctx, req.(*ScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MqGrpcServices_ListScheduled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScheduledRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MqGrpcServicesServer).ListScheduled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MqGrpcServices_ListScheduled_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MqGrpcServicesServer).ListScheduled(ctx, req.(*ListScheduledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MqGrpcServices_CancelScheduled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelScheduledRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MqGrpcServicesServer).CancelScheduled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MqGrpcServices_CancelScheduled_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MqGrpcServicesServer).CancelScheduled(ctx, req.(*CancelScheduledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MqGrpcServices_ExportQueue_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportQueueRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MqGrpcServicesServer).ExportQueue(m, &grpc.GenericServerStream[ExportQueueRequest, ArchiveChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MqGrpcServices_ExportQueueServer = grpc.ServerStreamingServer[ArchiveChunk]
+
+func _MqGrpcServices_ImportQueue_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MqGrpcServicesServer).ImportQueue(&grpc.GenericServerStream[ImportQueueRequest, ImportQueueResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MqGrpcServices_ImportQueueServer = grpc.ClientStreamingServer[ImportQueueRequest, ImportQueueResponse]
+
+func _MqGrpcServices_TransferMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TransferRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MqGrpcServicesServer).TransferMessages(m, &grpc.GenericServerStream[TransferRequest, TransferProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MqGrpcServices_TransferMessagesServer = grpc.ServerStreamingServer[TransferProgress]
+
 // MqGrpcServices_ServiceDesc is the grpc.ServiceDesc for MqGrpcServices service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -263,11 +725,67 @@ var MqGrpcServices_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BrowseNext",
 			Handler:    _MqGrpcServices_BrowseNext_Handler,
 		},
+		{
+			MethodName: "CloseBrowse",
+			Handler:    _MqGrpcServices_CloseBrowse_Handler,
+		},
 		{
 			MethodName: "InquireQueue",
 			Handler:    _MqGrpcServices_InquireQueue_Handler,
 		},
+		{
+			MethodName: "PutGroup",
+			Handler:    _MqGrpcServices_PutGroup_Handler,
+		},
+		{
+			MethodName: "GetGroup",
+			Handler:    _MqGrpcServices_GetGroup_Handler,
+		},
+		{
+			MethodName: "PutBatch",
+			Handler:    _MqGrpcServices_PutBatch_Handler,
+		},
+		{
+			MethodName: "GetBatch",
+			Handler:    _MqGrpcServices_GetBatch_Handler,
+		},
+		{
+			MethodName: "BrowseDLQ",
+			Handler:    _MqGrpcServices_BrowseDLQ_Handler,
+		},
+		{
+			MethodName: "ReplayDLQ",
+			Handler:    _MqGrpcServices_ReplayDLQ_Handler,
+		},
+		{
+			MethodName: "ScheduleMessage",
+			Handler:    _MqGrpcServices_ScheduleMessage_Handler,
+		},
+		{
+			MethodName: "ListScheduled",
+			Handler:    _MqGrpcServices_ListScheduled_Handler,
+		},
+		{
+			MethodName: "CancelScheduled",
+			Handler:    _MqGrpcServices_CancelScheduled_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportQueue",
+			Handler:       _MqGrpcServices_ExportQueue_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportQueue",
+			Handler:       _MqGrpcServices_ImportQueue_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "TransferMessages",
+			Handler:       _MqGrpcServices_TransferMessages_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "mq.proto",
 }
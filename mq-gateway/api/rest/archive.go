@@ -0,0 +1,195 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/audit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+// ArchiveResponse reports an import, or an export that failed before any
+// archive data was sent.
+type ArchiveResponse struct {
+	Status string `json:"status"`
+	// Messages is the number of messages imported or exported.
+	Messages int    `json:"messages"`
+	Error    string `json:"error,omitempty"`
+}
+
+func queryBool(q url.Values, key string) (bool, error) {
+	v := q.Get(key)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%s: want true or false", key)
+	}
+	return b, nil
+}
+
+func queryInt(q url.Values, key string) (int, error) {
+	v := q.Get(key)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s: want a non-negative integer", key)
+	}
+	return n, nil
+}
+
+// archiveStream sends the response headers on the first archive byte, so an
+// export that fails before producing data can still answer with an error status.
+type archiveStream struct {
+	w       http.ResponseWriter
+	format  mqcore.ArchiveFormat
+	queue   string
+	started bool
+}
+
+func (s *archiveStream) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+	h := s.w.Header()
+	contentType := "application/x-ndjson"
+	if s.format == mqcore.ArchiveTar {
+		contentType = "application/x-tar"
+	}
+	h.Set("Content-Type", contentType)
+	h.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", s.queue+"."+string(s.format)))
+	h.Set("Trailer", "X-Export-Count, X-Export-Error")
+	s.w.WriteHeader(http.StatusOK)
+}
+
+func (s *archiveStream) Write(p []byte) (int, error) {
+	s.start()
+	n, err := s.w.Write(p)
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return n, err
+}
+
+// Export streams a queue archive. Query parameters: queue (required), format
+// ("jsonl" or "tar"), destructive, max_messages and max_msg_bytes. The number
+// of messages exported and any error after streaming began are sent in the
+// X-Export-Count and X-Export-Error trailers.
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	queue := q.Get("queue")
+	if queue == "" {
+		http.Error(w, "queue required", http.StatusBadRequest)
+		return
+	}
+	format, err := mqcore.ParseArchiveFormat(q.Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var opts mqcore.ExportOptions
+	if opts.Destructive, err = queryBool(q, "destructive"); err == nil {
+		if opts.MaxMessages, err = queryInt(q, "max_messages"); err == nil {
+			opts.MaxBytes, err = queryInt(q, "max_msg_bytes")
+		}
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !h.admit(w, r, auth.OpAdmin, queue) {
+		return
+	}
+
+	out := &archiveStream{w: w, format: format, queue: queue}
+	aw, err := mqcore.NewArchiveWriter(format, out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	n, err := h.GW.ExportQueue(queue, aw, opts)
+	if err == nil {
+		err = aw.Close()
+	}
+	h.record(r, audit.Record{Operation: audit.OpExport, Queue: queue, Count: n}, err)
+	if err != nil {
+		slog.Error("[REST] Export error",
+			"error", err,
+			"queue", queue,
+			"exported", n,
+			"id", "1756ae1d-30e2-43d3-b22e-c7f5c3959cdc")
+
+		if !out.started {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadGateway)
+			_ = json.NewEncoder(w).Encode(ArchiveResponse{Status: "error", Messages: n, Error: err.Error()})
+			return
+		}
+		w.Header().Set("X-Export-Error", err.Error())
+	}
+	// An empty JSONL export writes nothing, so the headers may still be pending.
+	out.start()
+	w.Header().Set("X-Export-Count", strconv.Itoa(n))
+}
+
+// Import re-puts the messages of an archive sent as the request body. Query
+// parameters: queue (required), format ("jsonl" or "tar"), set_all_context
+// and new_msg_id. Messages are committed in batches, so on error Messages
+// reports how many were imported before it.
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	queue := q.Get("queue")
+	if queue == "" {
+		http.Error(w, "queue required", http.StatusBadRequest)
+		return
+	}
+	format, err := mqcore.ParseArchiveFormat(q.Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var opts mqcore.ImportOptions
+	if opts.SetAllContext, err = queryBool(q, "set_all_context"); err == nil {
+		opts.NewMsgID, err = queryBool(q, "new_msg_id")
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !h.admit(w, r, auth.OpAdmin, queue) {
+		return
+	}
+
+	ar, err := mqcore.NewArchiveReader(format, r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	n, err := h.GW.ImportQueue(queue, ar, opts)
+	h.record(r, audit.Record{Operation: audit.OpImport, Queue: queue, Count: n}, err)
+	resp := ArchiveResponse{Status: "ok", Messages: n}
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		slog.Error("[REST] Import error",
+			"error", err,
+			"queue", queue,
+			"imported", n,
+			"id", "ff8e8018-9d1e-4872-b343-7bda0ccea29b")
+
+		resp.Status = "error"
+		resp.Error = err.Error()
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,30 @@
+package rest
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPISpec is the contract for every route in Routes, including the /v2
+// routes transcoded from mq.proto. It is maintained by hand; the tests in
+// openapi_test.go fail when it drifts from the handlers or the proto.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// docsPage renders openAPISpec in the browser without any external assets.
+//
+//go:embed docs.html
+var docsPage []byte
+
+// OpenAPI serves the OpenAPI 3 document for this API.
+func (h *Handler) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPISpec)
+}
+
+// Docs serves the API documentation page, which reads /openapi.json.
+func (h *Handler) Docs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(docsPage)
+}
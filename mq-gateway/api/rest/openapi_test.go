@@ -0,0 +1,746 @@
+package rest
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/api/proto/mq_grpc_api"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/ratelimit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/router"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+// externalSchemas are the spec schemas for wire types declared outside this
+// package, keyed by schema name.
+var externalSchemas = map[string]reflect.Type{
+	"HandleCacheStats": reflect.TypeOf(mqcore.HandleCacheStats{}),
+	"BackoutStats":     reflect.TypeOf(mqcore.BackoutStats{}),
+	"RateLimitStats":   reflect.TypeOf(ratelimit.Stats{}),
+	"RouterStats":      reflect.TypeOf(router.Stats{}),
+	"RouterInputStats": reflect.TypeOf(router.InputStats{}),
+	"RouterRuleStats":  reflect.TypeOf(router.RuleStats{}),
+}
+
+// externalMessages are the spec schemas for proto messages that /v2 returns
+// but mq.proto does not declare.
+var externalMessages = []protoreflect.MessageDescriptor{
+	(&spb.Status{}).ProtoReflect().Descriptor(),
+}
+
+type specSchema struct {
+	Ref        string                 `json:"$ref"`
+	Type       string                 `json:"type"`
+	Items      *specSchema            `json:"items"`
+	Properties map[string]*specSchema `json:"properties"`
+	AllOf      []*specSchema          `json:"allOf"`
+}
+
+type specContent map[string]struct {
+	Schema *specSchema `json:"schema"`
+}
+
+type specOperation struct {
+	Parameters []struct {
+		Name string `json:"name"`
+		In   string `json:"in"`
+	} `json:"parameters"`
+	RequestBody *struct {
+		Content specContent `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Ref     string      `json:"$ref"`
+		Content specContent `json:"content"`
+	} `json:"responses"`
+}
+
+type spec struct {
+	OpenAPI    string                              `json:"openapi"`
+	Paths      map[string]map[string]specOperation `json:"paths"`
+	Components struct {
+		Schemas map[string]*specSchema `json:"schemas"`
+	} `json:"components"`
+}
+
+func loadSpec(t *testing.T) *spec {
+	t.Helper()
+	var s spec
+	if err := json.Unmarshal(openAPISpec, &s); err != nil {
+		t.Fatalf("openapi.json: %v", err)
+	}
+	if !strings.HasPrefix(s.OpenAPI, "3.") {
+		t.Fatalf("openapi = %q, want 3.x", s.OpenAPI)
+	}
+	return &s
+}
+
+// schemaRefs returns the schemas sch refers to, directly or transitively.
+func (s *spec) schemaRefs(sch *specSchema, seen map[string]bool) {
+	if sch == nil {
+		return
+	}
+	if sch.Ref != "" {
+		name := refName(sch.Ref)
+		if !seen[name] {
+			seen[name] = true
+			s.schemaRefs(s.Components.Schemas[name], seen)
+		}
+		return
+	}
+	s.schemaRefs(sch.Items, seen)
+	for _, p := range sch.Properties {
+		s.schemaRefs(p, seen)
+	}
+	for _, a := range sch.AllOf {
+		s.schemaRefs(a, seen)
+	}
+}
+
+// properties flattens sch, following allOf, into its JSON fields.
+func (s *spec) properties(sch *specSchema) map[string]*specSchema {
+	out := map[string]*specSchema{}
+	if sch.Ref != "" {
+		return s.properties(s.Components.Schemas[refName(sch.Ref)])
+	}
+	for _, a := range sch.AllOf {
+		for k, v := range s.properties(a) {
+			out[k] = v
+		}
+	}
+	for k, v := range sch.Properties {
+		out[k] = v
+	}
+	return out
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// kind describes a spec schema as "string", "integer", "boolean", "array of
+// <kind>", "object" or a schema name.
+func (sch *specSchema) kind() string {
+	switch {
+	case sch.Ref != "":
+		return refName(sch.Ref)
+	case sch.Type == "array" && sch.Items != nil:
+		return "array of " + sch.Items.kind()
+	}
+	return sch.Type
+}
+
+func goKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Slice:
+		return "array of " + goKind(t.Elem())
+	case reflect.Struct:
+		for name, typ := range externalSchemas {
+			if typ == t {
+				return name
+			}
+		}
+	}
+	return t.String()
+}
+
+// jsonName returns the JSON name of a struct field, or "" if it is not serialized.
+func jsonName(tag, field string) string {
+	name, _, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field
+	}
+	return name
+}
+
+// source is the parsed non-test Go code of this package.
+type source struct {
+	structs map[string]*ast.StructType
+	funcs   map[string]*ast.FuncDecl
+	methods map[string]*ast.FuncDecl
+	routes  []route
+}
+
+type route struct {
+	method, path, handler string
+}
+
+func (r route) String() string {
+	if r.method == "" {
+		return r.path
+	}
+	return strings.ToUpper(r.method) + " " + r.path
+}
+
+func parseSource(t *testing.T) *source {
+	t.Helper()
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := &source{structs: map[string]*ast.StructType{}, funcs: map[string]*ast.FuncDecl{}, methods: map[string]*ast.FuncDecl{}}
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, ds := range d.Specs {
+					if ts, ok := ds.(*ast.TypeSpec); ok {
+						if st, ok := ts.Type.(*ast.StructType); ok {
+							src.structs[ts.Name.Name] = st
+						}
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					src.funcs[d.Name.Name] = d
+				} else if recvName(d) == "Handler" {
+					src.methods[d.Name.Name] = d
+				}
+			}
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 2 {
+				return true
+			}
+			if sel, ok := call.Fun.(*ast.SelectorExpr); !ok || sel.Sel.Name != "HandleFunc" {
+				return true
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			handler, ok2 := call.Args[1].(*ast.SelectorExpr)
+			if !ok || !ok2 {
+				t.Errorf("%s: HandleFunc needs a literal pattern and a Handler method", fset.Position(call.Pos()))
+				return true
+			}
+			pattern, _ := strconv.Unquote(lit.Value)
+			r := route{path: pattern, handler: handler.Sel.Name}
+			if m, p, ok := strings.Cut(pattern, " "); ok {
+				r.method, r.path = strings.ToLower(m), p
+			}
+			src.routes = append(src.routes, r)
+			return true
+		})
+	}
+	if len(src.routes) == 0 {
+		t.Fatal("no routes found")
+	}
+	return src
+}
+
+func recvName(fn *ast.FuncDecl) string {
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if id, ok := typ.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+// wireType reports whether name is a struct sent or received as JSON.
+func (src *source) wireType(name string) bool {
+	st, ok := src.structs[name]
+	if !ok || !ast.IsExported(name) {
+		return false
+	}
+	for _, f := range st.Fields.List {
+		if f.Tag != nil && strings.Contains(f.Tag.Value, `json:"`) {
+			return true
+		}
+	}
+	return false
+}
+
+// fields returns the JSON fields of a wire type and their kinds, inlining
+// embedded structs like encoding/json does.
+func (src *source) fields(name string) map[string]string {
+	out := map[string]string{}
+	for _, f := range src.structs[name].Fields.List {
+		if len(f.Names) == 0 {
+			for k, v := range src.fields(f.Type.(*ast.Ident).Name) {
+				out[k] = v
+			}
+			continue
+		}
+		tag := ""
+		if f.Tag != nil {
+			tag, _ = strconv.Unquote(f.Tag.Value)
+		}
+		for _, n := range f.Names {
+			if jn := jsonName(tag, n.Name); jn != "" && n.IsExported() {
+				out[jn] = src.kind(f.Type)
+			}
+		}
+	}
+	return out
+}
+
+func (src *source) kind(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		switch e.Name {
+		case "string":
+			return "string"
+		case "bool":
+			return "boolean"
+		case "int", "int32", "int64", "uint", "uint32", "uint64":
+			return "integer"
+		}
+		return e.Name
+	case *ast.ArrayType:
+		return "array of " + src.kind(e.Elt)
+	case *ast.StarExpr:
+		return src.kind(e.X)
+	case *ast.SelectorExpr:
+		full := e.X.(*ast.Ident).Name + "." + e.Sel.Name
+		for name, typ := range externalSchemas {
+			if typ.String() == full {
+				return name
+			}
+		}
+		return full
+	}
+	return "?"
+}
+
+// usage is what a handler reads from the request and which wire types it builds.
+type usage struct {
+	body    string
+	query   map[string]bool
+	path    map[string]bool
+	header  map[string]bool
+	builds  map[string]bool
+	visited map[string]bool
+}
+
+func (src *source) usage(fn *ast.FuncDecl) *usage {
+	u := &usage{query: map[string]bool{}, path: map[string]bool{}, header: map[string]bool{}, builds: map[string]bool{}, visited: map[string]bool{}}
+	src.inspect(fn, u)
+	return u
+}
+
+// inspect walks fn and the package functions it calls.
+func (src *source) inspect(fn *ast.FuncDecl, u *usage) {
+	u.visited[fn.Name.Name] = true
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			if id, ok := n.Type.(*ast.Ident); ok && len(n.Names) == 1 && n.Names[0].Name == "req" {
+				u.body = id.Name
+			}
+		case *ast.CompositeLit:
+			if id, ok := n.Type.(*ast.Ident); ok && src.wireType(id.Name) {
+				u.builds[id.Name] = true
+			}
+		case *ast.CallExpr:
+			key := ""
+			if len(n.Args) > 0 {
+				if lit, ok := n.Args[len(n.Args)-1].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					key, _ = strconv.Unquote(lit.Value)
+				}
+			}
+			switch f := n.Fun.(type) {
+			case *ast.Ident:
+				switch {
+				case (f.Name == "queryInt" || f.Name == "queryBool") && key != "":
+					u.query[key] = true
+				case src.funcs[f.Name] != nil && !u.visited[f.Name]:
+					src.inspect(src.funcs[f.Name], u)
+				}
+			case *ast.SelectorExpr:
+				switch {
+				case key == "":
+				case f.Sel.Name == "PathValue":
+					u.path[key] = true
+				case f.Sel.Name == "Get" && isIdent(f.X, "q"):
+					u.query[key] = true
+				case f.Sel.Name == "Get" && isSelector(f.X, "Header"):
+					u.header[key] = true
+				}
+			}
+		}
+		return true
+	})
+}
+
+func isIdent(e ast.Expr, name string) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == name
+}
+
+func isSelector(e ast.Expr, name string) bool {
+	sel, ok := e.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == name
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// binding is a /v2 route transcoded from a google.api.http option in mq.proto.
+type binding struct {
+	method, path, body string
+	in, out            protoreflect.MessageDescriptor
+}
+
+func (b binding) vars() map[string]bool {
+	out := map[string]bool{}
+	for _, seg := range strings.Split(b.path, "/") {
+		if strings.HasPrefix(seg, "{") {
+			name, _, _ := strings.Cut(strings.Trim(seg, "{}"), "=")
+			out[name] = true
+		}
+	}
+	return out
+}
+
+func bindings(t *testing.T) []binding {
+	t.Helper()
+	var out []binding
+	services := mq_grpc_api.File_mq_proto.Services()
+	for i := 0; i < services.Len(); i++ {
+		methods := services.Get(i).Methods()
+		for j := 0; j < methods.Len(); j++ {
+			m := methods.Get(j)
+			rule := proto.GetExtension(m.Options(), annotations.E_Http).(*annotations.HttpRule)
+			b := binding{body: rule.GetBody(), in: m.Input(), out: m.Output()}
+			switch p := rule.GetPattern().(type) {
+			case nil:
+				continue
+			case *annotations.HttpRule_Get:
+				b.method, b.path = "get", p.Get
+			case *annotations.HttpRule_Put:
+				b.method, b.path = "put", p.Put
+			case *annotations.HttpRule_Post:
+				b.method, b.path = "post", p.Post
+			case *annotations.HttpRule_Delete:
+				b.method, b.path = "delete", p.Delete
+			case *annotations.HttpRule_Patch:
+				b.method, b.path = "patch", p.Patch
+			default:
+				t.Errorf("%s: unsupported http pattern %T", m.FullName(), p)
+				continue
+			}
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// protoKind describes a proto field the way kind describes a spec schema,
+// following the protojson mapping.
+func protoKind(fd protoreflect.FieldDescriptor) string {
+	var k string
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		k = "boolean"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		k = "integer"
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		k = string(fd.Message().Name())
+		if fd.Message().FullName() == "google.protobuf.Any" {
+			k = "object"
+		}
+	default:
+		// Strings, bytes, enums and 64-bit integers are JSON strings.
+		k = "string"
+	}
+	if fd.IsList() {
+		return "array of " + k
+	}
+	return k
+}
+
+// protoMessages adds md and every message its fields use to out.
+func protoMessages(md protoreflect.MessageDescriptor, out map[string]protoreflect.MessageDescriptor) {
+	if _, ok := out[string(md.Name())]; ok || md.FullName() == "google.protobuf.Any" {
+		return
+	}
+	out[string(md.Name())] = md
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if m := fields.Get(i).Message(); m != nil {
+			protoMessages(m, out)
+		}
+	}
+}
+
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	s := loadSpec(t)
+	src := parseSource(t)
+
+	routes := src.routes
+	for _, b := range bindings(t) {
+		routes = append(routes, route{method: b.method, path: b.path, handler: string(b.in.Name())})
+	}
+	for _, r := range routes {
+		item, ok := s.Paths[r.path]
+		if !ok || (r.method != "" && item[r.method].Responses == nil) {
+			t.Errorf("route %s is not in openapi.json", r)
+		}
+	}
+	for path, item := range s.Paths {
+		for method := range item {
+			found := false
+			for _, r := range routes {
+				if r.path == path && (r.method == "" || r.method == method) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("openapi.json documents %s %s, which is not routed", method, path)
+			}
+		}
+	}
+}
+
+func TestOpenAPIMatchesHandlers(t *testing.T) {
+	s := loadSpec(t)
+	src := parseSource(t)
+
+	for _, r := range src.routes {
+		fn := src.methods[r.handler]
+		if fn == nil {
+			t.Errorf("%s: no Handler method %s", r.path, r.handler)
+			continue
+		}
+		u := src.usage(fn)
+		for method, op := range s.Paths[r.path] {
+			if r.method != "" && r.method != method {
+				continue
+			}
+			name := method + " " + r.path
+
+			body := ""
+			if op.RequestBody != nil {
+				if c, ok := op.RequestBody.Content["application/json"]; ok && c.Schema != nil {
+					body = refName(c.Schema.Ref)
+				}
+			}
+			if body != u.body {
+				t.Errorf("%s: spec request body %q, %s decodes %q", name, body, r.handler, u.body)
+			}
+
+			params := map[string]map[string]bool{"query": {}, "path": {}, "header": {}}
+			for _, p := range op.Parameters {
+				params[p.In][p.Name] = true
+			}
+			if got, want := sortedKeys(params["query"]), sortedKeys(u.query); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: spec query parameters %v, %s reads %v", name, got, r.handler, want)
+			}
+			if got, want := sortedKeys(params["path"]), sortedKeys(u.path); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: spec path parameters %v, %s reads %v", name, got, r.handler, want)
+			}
+			for h := range params["header"] {
+				if !u.header[h] {
+					t.Errorf("%s: spec header %q is not read by %s", name, h, r.handler)
+				}
+			}
+
+			// Every wire type the handler builds must be reachable from a response.
+			reachable := map[string]bool{}
+			for _, resp := range op.Responses {
+				for _, c := range resp.Content {
+					s.schemaRefs(c.Schema, reachable)
+				}
+			}
+			for typ := range u.builds {
+				if typ != u.body && !reachable[typ] {
+					t.Errorf("%s: %s builds %s, which no documented response contains", name, r.handler, typ)
+				}
+			}
+		}
+	}
+}
+
+func TestOpenAPIMatchesTranscoding(t *testing.T) {
+	s := loadSpec(t)
+
+	for _, b := range bindings(t) {
+		op, ok := s.Paths[b.path][b.method]
+		if !ok {
+			continue // reported by TestOpenAPIMatchesRoutes
+		}
+		name := b.method + " " + b.path
+
+		params := map[string]map[string]bool{"query": {}, "path": {}, "header": {}}
+		for _, p := range op.Parameters {
+			params[p.In][p.Name] = true
+		}
+		if got, want := sortedKeys(params["path"]), sortedKeys(b.vars()); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: spec path parameters %v, binding has %v", name, got, want)
+		}
+
+		body := ""
+		if op.RequestBody != nil {
+			if c, ok := op.RequestBody.Content["application/json"]; ok && c.Schema != nil {
+				body = refName(c.Schema.Ref)
+			}
+		}
+		query := map[string]bool{}
+		switch b.body {
+		case "*":
+			if body != string(b.in.Name()) {
+				t.Errorf("%s: spec request body %q, binding takes %s", name, body, b.in.Name())
+			}
+		case "":
+			if body != "" {
+				t.Errorf("%s: spec request body %q, binding takes none", name, body)
+			}
+			fields := b.in.Fields()
+			for i := 0; i < fields.Len(); i++ {
+				if f := string(fields.Get(i).Name()); !b.vars()[f] {
+					query[f] = true
+				}
+			}
+		default:
+			t.Errorf("%s: body %q is not supported by this test", name, b.body)
+		}
+		if got, want := sortedKeys(params["query"]), sortedKeys(query); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: spec query parameters %v, binding reads %v", name, got, want)
+		}
+
+		for _, code := range []string{"200", "502"} {
+			c, ok := op.Responses[code].Content["application/json"]
+			if !ok || c.Schema == nil || refName(c.Schema.Ref) != string(b.out.Name()) {
+				t.Errorf("%s: spec %s response is not %s", name, code, b.out.Name())
+			}
+		}
+	}
+}
+
+func TestOpenAPIMatchesWireTypes(t *testing.T) {
+	s := loadSpec(t)
+	src := parseSource(t)
+
+	want := map[string]map[string]string{}
+	for name := range src.structs {
+		if src.wireType(name) {
+			want[name] = src.fields(name)
+		}
+	}
+	for name, typ := range externalSchemas {
+		fields := map[string]string{}
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			if jn := jsonName(string(f.Tag), f.Name); jn != "" && f.IsExported() {
+				fields[jn] = goKind(f.Type)
+			}
+		}
+		want[name] = fields
+	}
+
+	// /v2 schemas are proto messages, often sharing a name and shape with
+	// the v1 Go type. Requests read only from the path and query have none.
+	messages := map[string]protoreflect.MessageDescriptor{}
+	for _, b := range bindings(t) {
+		if b.body != "" {
+			protoMessages(b.in, messages)
+		}
+		protoMessages(b.out, messages)
+	}
+	for _, md := range externalMessages {
+		protoMessages(md, messages)
+	}
+	wantProto := map[string]map[string]string{}
+	for name, md := range messages {
+		fields := map[string]string{}
+		for i := 0; i < md.Fields().Len(); i++ {
+			fd := md.Fields().Get(i)
+			fields[string(fd.Name())] = protoKind(fd)
+		}
+		wantProto[name] = fields
+	}
+
+	compare := func(origin string, types map[string]map[string]string) {
+		for name, fields := range types {
+			sch, ok := s.Components.Schemas[name]
+			if !ok {
+				t.Errorf("%s %s has no schema in openapi.json", origin, name)
+				continue
+			}
+			props := s.properties(sch)
+			for field, kind := range fields {
+				p, ok := props[field]
+				switch {
+				case !ok:
+					t.Errorf("%s.%s is missing from the schema", name, field)
+				case p.kind() != kind:
+					t.Errorf("%s.%s: schema type %q, %s type %q", name, field, p.kind(), origin, kind)
+				}
+			}
+			for field := range props {
+				if _, ok := fields[field]; !ok {
+					t.Errorf("schema %s documents %s, which the %s type lacks", name, field, origin)
+				}
+			}
+		}
+	}
+	compare("Go", want)
+	compare("proto", wantProto)
+	for name := range s.Components.Schemas {
+		if _, ok := want[name]; !ok && wantProto[name] == nil {
+			t.Errorf("schema %s has no Go type or proto message", name)
+		}
+	}
+
+	// Every reference must resolve.
+	refs := map[string]bool{}
+	for _, sch := range s.Components.Schemas {
+		s.schemaRefs(sch, refs)
+	}
+	for _, item := range s.Paths {
+		for _, op := range item {
+			if op.RequestBody != nil {
+				for _, c := range op.RequestBody.Content {
+					s.schemaRefs(c.Schema, refs)
+				}
+			}
+			for _, resp := range op.Responses {
+				for _, c := range resp.Content {
+					s.schemaRefs(c.Schema, refs)
+				}
+			}
+		}
+	}
+	for name := range refs {
+		if _, ok := s.Components.Schemas[name]; !ok {
+			t.Errorf("$ref to undefined schema %s", name)
+		}
+	}
+}
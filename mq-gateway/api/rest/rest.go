@@ -2,9 +2,18 @@ package rest
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/audit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/ratelimit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/router"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/schema"
 	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
 )
 
@@ -13,11 +22,24 @@ type PutRequest struct {
 	Queue string `json:"queue"`
 	// Payload to put.
 	Message string `json:"message"`
+	// Optional MQMD grouping/segmentation fields; group_id is hex.
+	GroupID      string `json:"group_id,omitempty"`
+	MsgSeqNumber int32  `json:"msg_seq_number,omitempty"`
+	Offset       int32  `json:"offset,omitempty"`
+	MsgFlags     int32  `json:"msg_flags,omitempty"`
+	// Optional reply queue; marks the message as a request.
+	ReplyToQueue string `json:"reply_to_queue,omitempty"`
+	// Optional deduplication key, also accepted as the Idempotency-Key header.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
 }
 
 type PutResponse struct {
 	Status string `json:"status"`
-	Error  string `json:"error,omitempty"`
+	MsgID  string `json:"msg_id,omitempty"`
+	// Replayed is true when the result is that of an earlier put with the
+	// same idempotency key; nothing was put.
+	Replayed bool   `json:"replayed,omitempty"`
+	Error    string `json:"error,omitempty"`
 }
 
 type GetRequest struct {
@@ -27,15 +49,112 @@ type GetRequest struct {
 	WaitMs int `json:"wait_ms"`
 	// Max message size in bytes.
 	MaxMsgBytes int `json:"max_msg_bytes"`
+	// Reassemble segmented messages into one logical message.
+	CompleteMsg bool `json:"complete_msg,omitempty"`
+	// Only get the message with this CorrelId (hex), e.g. a reply.
+	CorrelID string `json:"correl_id,omitempty"`
 }
 
 type GetResponse struct {
+	Status       string `json:"status"`
+	Message      string `json:"message,omitempty"`
+	Empty        bool   `json:"empty"`
+	MsgID        string `json:"msg_id,omitempty"`
+	CorrelID     string `json:"correl_id,omitempty"`
+	GroupID      string `json:"group_id,omitempty"`
+	MsgSeqNumber int32  `json:"msg_seq_number,omitempty"`
+	Offset       int32  `json:"offset,omitempty"`
+	MsgFlags     int32  `json:"msg_flags,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+type PutGroupRequest struct {
+	// Target queue name.
+	Queue string `json:"queue"`
+	// Payloads put in order; the last one is flagged last-in-group.
+	Messages []string `json:"messages"`
+}
+
+type PutGroupResponse struct {
 	Status  string `json:"status"`
-	Message string `json:"message,omitempty"`
-	Empty   bool   `json:"empty"`
+	GroupID string `json:"group_id,omitempty"`
 	Error   string `json:"error,omitempty"`
 }
 
+type GetGroupRequest struct {
+	// Target queue name.
+	Queue string `json:"queue"`
+	// Wait interval in milliseconds.
+	WaitMs int `json:"wait_ms"`
+	// Max message size in bytes (per message).
+	MaxMsgBytes int `json:"max_msg_bytes"`
+}
+
+type GroupMessage struct {
+	Message      string `json:"message"`
+	MsgID        string `json:"msg_id,omitempty"`
+	MsgSeqNumber int32  `json:"msg_seq_number"`
+	Offset       int32  `json:"offset,omitempty"`
+	MsgFlags     int32  `json:"msg_flags,omitempty"`
+}
+
+type GetGroupResponse struct {
+	Status   string         `json:"status"`
+	GroupID  string         `json:"group_id,omitempty"`
+	Messages []GroupMessage `json:"messages,omitempty"`
+	Empty    bool           `json:"empty"`
+	Error    string         `json:"error,omitempty"`
+}
+
+type PutBatchRequest struct {
+	// Target queue name.
+	Queue string `json:"queue"`
+	// Payloads to put, in order.
+	Messages []string `json:"messages"`
+	// Put all messages in one unit of work (all-or-nothing).
+	Syncpoint bool `json:"syncpoint"`
+}
+
+type PutBatchResult struct {
+	Status string `json:"status"`
+	MsgID  string `json:"msg_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type PutBatchResponse struct {
+	Status  string           `json:"status"`
+	Results []PutBatchResult `json:"results,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+type GetBatchRequest struct {
+	// Target queue name.
+	Queue string `json:"queue"`
+	// Maximum number of messages to receive.
+	MaxMessages int `json:"max_messages"`
+	// Wait interval in milliseconds for the first message.
+	WaitMs int `json:"wait_ms"`
+	// Max message size in bytes (per message).
+	MaxMsgBytes int `json:"max_msg_bytes"`
+	// Get all messages in one unit of work (all-or-nothing).
+	Syncpoint bool `json:"syncpoint"`
+}
+
+type GetBatchResult struct {
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+	MsgID    string `json:"msg_id,omitempty"`
+	CorrelID string `json:"correl_id,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type GetBatchResponse struct {
+	Status  string           `json:"status"`
+	Results []GetBatchResult `json:"results,omitempty"`
+	Empty   bool             `json:"empty"`
+	Error   string           `json:"error,omitempty"`
+}
+
 type BrowseFirstRequest struct {
 	// Target queue name.
 	Queue string `json:"queue"`
@@ -85,9 +204,195 @@ type InquireQueueResponse struct {
 	Error           string `json:"error,omitempty"`
 }
 
+type DLQBrowseRequest struct {
+	// Dead-letter queue; empty uses the queue manager's DEADQ.
+	Queue string `json:"queue,omitempty"`
+	// Only return dead letters with one of these MQRC/MQFB reason codes.
+	Reasons []int32 `json:"reasons,omitempty"`
+	// Only return dead letters originally destined for this queue.
+	DestQueue string `json:"dest_queue,omitempty"`
+	// Maximum number of dead letters to return.
+	MaxMessages int `json:"max_messages"`
+	// Max message size in bytes (per message).
+	MaxMsgBytes int `json:"max_msg_bytes"`
+}
+
+type DeadLetterMessage struct {
+	MsgID       string `json:"msg_id,omitempty"`
+	Reason      int32  `json:"reason"`
+	ReasonText  string `json:"reason_text,omitempty"`
+	DestQueue   string `json:"dest_queue"`
+	DestQMgr    string `json:"dest_qmgr,omitempty"`
+	Format      string `json:"format,omitempty"`
+	PutApplName string `json:"put_appl_name,omitempty"`
+	// PutTime is RFC 3339, empty when the DLH carries no timestamp.
+	PutTime string `json:"put_time,omitempty"`
+	Message string `json:"message"`
+}
+
+type DLQBrowseResponse struct {
+	Status   string              `json:"status"`
+	Messages []DeadLetterMessage `json:"messages,omitempty"`
+	Error    string              `json:"error,omitempty"`
+}
+
+type DLQReplayRequest struct {
+	// Dead-letter queue; empty uses the queue manager's DEADQ.
+	Queue string `json:"queue,omitempty"`
+	// Only replay dead letters with one of these MQRC/MQFB reason codes.
+	Reasons []int32 `json:"reasons,omitempty"`
+	// Only replay dead letters originally destined for this queue.
+	DestQueue string `json:"dest_queue,omitempty"`
+	// Destination overrides the original destination from the DLH.
+	Destination string `json:"destination,omitempty"`
+	// Maximum number of dead letters to replay.
+	MaxMessages int `json:"max_messages"`
+	// Max message size in bytes (per message).
+	MaxMsgBytes int `json:"max_msg_bytes"`
+	// DryRun previews what would be replayed without moving anything.
+	DryRun bool `json:"dry_run"`
+}
+
+type DLQReplayResult struct {
+	// Status is "replayed", "preview" or "error".
+	Status     string `json:"status"`
+	MsgID      string `json:"msg_id,omitempty"`
+	Reason     int32  `json:"reason"`
+	ReasonText string `json:"reason_text,omitempty"`
+	DestQueue  string `json:"dest_queue"`
+	Target     string `json:"target"`
+	Error      string `json:"error,omitempty"`
+}
+
+type DLQReplayResponse struct {
+	Status  string            `json:"status"`
+	DryRun  bool              `json:"dry_run"`
+	Results []DLQReplayResult `json:"results,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+type StatsResponse struct {
+	Status      string                  `json:"status"`
+	HandleCache mqcore.HandleCacheStats `json:"handle_cache"`
+	Backout     mqcore.BackoutStats     `json:"backout"`
+	RateLimit   ratelimit.Stats         `json:"rate_limit"`
+	Router      router.Stats            `json:"router"`
+}
+
 type Handler struct {
 	// GW provides access to MQ operations.
 	GW mqcore.Gateway
+	// Authz restricts which principals may use which queues; nil allows all.
+	Authz *auth.Policy
+	// Audit receives one record per message operation; nil disables auditing.
+	Audit *audit.Logger
+	// Limits throttles callers per principal, queue and operation; nil disables limits.
+	Limits *ratelimit.Limiter
+	// Router reports routing counters on /stats; nil reports none.
+	Router *router.Router
+	// V2 serves /v2, normally grpcsrv.Server.Gateway; nil leaves /v2 unrouted.
+	V2 http.Handler
+	// streams maps the stream_id of each open SSE get stream to its acks.
+	streams sync.Map
+	// resolveDLQ replaces GW.ResolveDLQ in tests.
+	resolveDLQ func(queue string) (string, error)
+}
+
+// admit checks authorization and rate limits for op on queue. It writes a 403
+// or 429 and returns false when the call may not proceed.
+func (h *Handler) admit(w http.ResponseWriter, r *http.Request, op auth.Operation, queue string) bool {
+	if err := h.Authz.Authorize(r.Context(), op, queue); err != nil {
+		h.record(r, audit.Record{Operation: string(op), Queue: queue, Outcome: audit.OutcomeDenied, Error: err.Error()}, nil)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return false
+	}
+	if err := h.Limits.Allow(r.Context(), op, queue); err != nil {
+		h.record(r, audit.Record{Operation: string(op), Queue: queue, Outcome: audit.OutcomeThrottled, Error: err.Error()}, nil)
+		tooManyRequests(w, err)
+		return false
+	}
+	return true
+}
+
+// longPoll reserves a slot for a call that may wait waitMs. When it returns
+// true the caller must invoke release once the wait is over.
+func (h *Handler) longPoll(w http.ResponseWriter, r *http.Request, waitMs int) (release func(), ok bool) {
+	release, err := h.Limits.AcquireLongPoll(r.Context(), waitMs)
+	if err != nil {
+		tooManyRequests(w, err)
+		return release, false
+	}
+	return release, true
+}
+
+func tooManyRequests(w http.ResponseWriter, err *ratelimit.Error) {
+	w.Header().Set("Retry-After", strconv.Itoa(err.RetryAfterSeconds()))
+	http.Error(w, err.Error(), http.StatusTooManyRequests)
+}
+
+// record writes an audit record for r; a non-nil err marks it failed with its MQ reason code.
+func (h *Handler) record(r *http.Request, rec audit.Record, err error) {
+	if h.Audit == nil {
+		return
+	}
+	rec.Transport = "rest"
+	rec.ClientAddr = r.RemoteAddr
+	if err != nil {
+		rec.Outcome = audit.OutcomeError
+		rec.Error = err.Error()
+		rec.ReasonCode = mqcore.ReasonCode(err)
+		if errors.As(err, new(*schema.ValidationError)) {
+			rec.Outcome = audit.OutcomeInvalid
+		}
+	} else if rec.Outcome == "" {
+		rec.Outcome = audit.OutcomeOK
+	}
+	h.Audit.Log(r.Context(), rec)
+}
+
+// schemaError answers a payload rejected by its queue's schema with 422 and
+// the violations as text, and reports whether it did.
+func schemaError(w http.ResponseWriter, err error) bool {
+	if !errors.As(err, new(*schema.ValidationError)) {
+		return false
+	}
+	http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	return true
+}
+
+// messageRecord describes a received or browsed message.
+func messageRecord(op, queue string, m *mqcore.Message) audit.Record {
+	return audit.Record{
+		Operation: op,
+		Queue:     queue,
+		MsgID:     mqcore.FormatID(m.MsgID),
+		CorrelID:  mqcore.FormatID(m.CorrelID),
+		GroupID:   mqcore.FormatID(m.GroupID),
+	}.WithPayload(m.Payload)
+}
+
+func (resp *GetResponse) setMessage(msg *mqcore.Message) {
+	resp.Message = msg.Payload
+	resp.MsgID = mqcore.FormatID(msg.MsgID)
+	resp.CorrelID = mqcore.FormatID(msg.CorrelID)
+	resp.GroupID = mqcore.FormatID(msg.GroupID)
+	resp.MsgSeqNumber = msg.MsgSeqNumber
+	resp.Offset = msg.Offset
+	resp.MsgFlags = msg.MsgFlags
+}
+
+func (resp *InquireQueueResponse) setInfo(info *mqcore.QueueInfo) {
+	resp.Queue = info.Name
+	resp.QueueDesc = info.Description
+	resp.QueueType = info.Type
+	resp.QueueUsage = info.Usage
+	resp.DefPersistence = info.DefPersistence
+	resp.InhibitGet = info.InhibitGet
+	resp.InhibitPut = info.InhibitPut
+	resp.CurrentQDepth = info.CurrentDepth
+	resp.MaxQDepth = info.MaxDepth
+	resp.OpenInputCount = info.OpenInputCount
+	resp.OpenOutputCount = info.OpenOutputCount
 }
 
 func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
@@ -102,8 +407,62 @@ func (h *Handler) Put(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.GW.Put(req.Queue, req.Message)
-	resp := PutResponse{Status: "ok"}
+	groupID, err := mqcore.ParseID(req.GroupID)
+	if err != nil {
+		http.Error(w, "group_id: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// A client retrying after a timeout sends the same key and gets the
+	// first put's result back instead of a second message.
+	key := req.IdempotencyKey
+	if hk := r.Header.Get("Idempotency-Key"); hk != "" {
+		if key != "" && key != hk {
+			http.Error(w, "idempotency_key and Idempotency-Key header differ", http.StatusBadRequest)
+			return
+		}
+		key = hk
+	}
+	if len(key) > mqcore.MaxIdempotencyKeyLength {
+		http.Error(w, "idempotency key too long", http.StatusBadRequest)
+		return
+	}
+
+	if !h.admit(w, r, auth.OpPut, req.Queue) {
+		return
+	}
+
+	// Idempotency keys are per principal; without authentication all
+	// callers share one scope.
+	principal := ""
+	if p, ok := auth.FromContext(r.Context()); ok {
+		principal = p.Name
+	}
+	msgID, replayed, err := h.GW.PutIdempotent(principal, req.Queue, key, req.Message, mqcore.PutOptions{
+		GroupID:      groupID,
+		MsgSeqNumber: req.MsgSeqNumber,
+		Offset:       req.Offset,
+		MsgFlags:     req.MsgFlags,
+		ReplyToQueue: req.ReplyToQueue,
+	})
+	rec := audit.Record{
+		Operation: audit.OpPut,
+		Queue:     req.Queue,
+		MsgID:     mqcore.FormatID(msgID),
+		GroupID:   mqcore.FormatID(groupID),
+	}.WithPayload(req.Message)
+	if replayed {
+		rec.Outcome = audit.OutcomeReplayed
+	}
+	h.record(r, rec, err)
+	if errors.Is(err, mqcore.ErrIdempotencyConflict) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if schemaError(w, err) {
+		return
+	}
+	resp := PutResponse{Status: "ok", MsgID: mqcore.FormatID(msgID), Replayed: replayed}
 	if err != nil {
 		slog.Error("[REST] Put error",
 			"error", err,
@@ -129,8 +488,35 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	msg, empty, err := h.GW.Get(req.Queue, req.WaitMs, req.MaxMsgBytes)
-	resp := GetResponse{Status: "ok", Message: msg, Empty: empty}
+	correlID, err := mqcore.ParseID(req.CorrelID)
+	if err != nil {
+		http.Error(w, "correl_id: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !h.admit(w, r, auth.OpGet, req.Queue) {
+		return
+	}
+
+	release, ok := h.longPoll(w, r, req.WaitMs)
+	if !ok {
+		return
+	}
+	defer release()
+
+	msg, empty, err := h.GW.GetMessage(req.Queue, mqcore.GetOptions{
+		WaitMs:      req.WaitMs,
+		MaxBytes:    req.MaxMsgBytes,
+		CompleteMsg: req.CompleteMsg,
+		CorrelID:    correlID,
+	})
+	// Empty gets remove nothing and are not audited.
+	if msg != nil {
+		h.record(r, messageRecord(audit.OpGet, req.Queue, msg), nil)
+	} else if err != nil {
+		h.record(r, audit.Record{Operation: audit.OpGet, Queue: req.Queue}, err)
+	}
+	resp := GetResponse{Status: "ok", Empty: empty}
 	if err != nil {
 		slog.Error("[REST] Get error",
 			"error", err,
@@ -139,6 +525,219 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 		resp.Status = "error"
 		resp.Error = err.Error()
 		w.WriteHeader(http.StatusBadGateway)
+	} else if msg != nil {
+		resp.setMessage(msg)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) PutGroup(w http.ResponseWriter, r *http.Request) {
+	// Decode and validate the request.
+	var req PutGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Queue == "" {
+		http.Error(w, "queue required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Messages) == 0 {
+		http.Error(w, "messages required", http.StatusBadRequest)
+		return
+	}
+
+	if !h.admit(w, r, auth.OpPut, req.Queue) {
+		return
+	}
+
+	groupID, err := h.GW.PutGroup(req.Queue, req.Messages)
+	for _, m := range req.Messages {
+		h.record(r, audit.Record{Operation: audit.OpPut, Queue: req.Queue, GroupID: mqcore.FormatID(groupID)}.WithPayload(m), err)
+	}
+	if schemaError(w, err) {
+		return
+	}
+	resp := PutGroupResponse{Status: "ok", GroupID: mqcore.FormatID(groupID)}
+	if err != nil {
+		slog.Error("[REST] PutGroup error",
+			"error", err,
+			"id", "a7a6d411-505b-49c8-9d24-183e4420aa6f")
+		resp.Status = "error"
+		resp.Error = err.Error()
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	// Decode and validate the request.
+	var req GetGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Queue == "" {
+		http.Error(w, "queue required", http.StatusBadRequest)
+		return
+	}
+
+	if !h.admit(w, r, auth.OpGet, req.Queue) {
+		return
+	}
+
+	release, ok := h.longPoll(w, r, req.WaitMs)
+	if !ok {
+		return
+	}
+	defer release()
+
+	msgs, empty, err := h.GW.GetGroup(req.Queue, mqcore.GetOptions{
+		WaitMs:   req.WaitMs,
+		MaxBytes: req.MaxMsgBytes,
+	})
+	for i := range msgs {
+		h.record(r, messageRecord(audit.OpGet, req.Queue, &msgs[i]), nil)
+	}
+	if err != nil && len(msgs) == 0 {
+		h.record(r, audit.Record{Operation: audit.OpGet, Queue: req.Queue}, err)
+	}
+	resp := GetGroupResponse{Status: "ok", Empty: empty}
+	if err != nil {
+		slog.Error("[REST] GetGroup error",
+			"error", err,
+			"id", "d775f864-b1a0-4cd1-9aa3-7bf41fcf9f75")
+		resp.Status = "error"
+		resp.Error = err.Error()
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	for _, m := range msgs {
+		resp.GroupID = mqcore.FormatID(m.GroupID)
+		resp.Messages = append(resp.Messages, GroupMessage{
+			Message:      m.Payload,
+			MsgID:        mqcore.FormatID(m.MsgID),
+			MsgSeqNumber: m.MsgSeqNumber,
+			Offset:       m.Offset,
+			MsgFlags:     m.MsgFlags,
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) PutBatch(w http.ResponseWriter, r *http.Request) {
+	// Decode and validate the request.
+	var req PutBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Queue == "" {
+		http.Error(w, "queue required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Messages) == 0 {
+		http.Error(w, "messages required", http.StatusBadRequest)
+		return
+	}
+
+	if !h.admit(w, r, auth.OpPut, req.Queue) {
+		return
+	}
+
+	results, err := h.GW.PutBatch(req.Queue, req.Messages, req.Syncpoint)
+	for i, res := range results {
+		h.record(r, audit.Record{Operation: audit.OpPut, Queue: req.Queue, MsgID: mqcore.FormatID(res.MsgID)}.WithPayload(req.Messages[i]), res.Err)
+	}
+	if err != nil && len(results) == 0 {
+		h.record(r, audit.Record{Operation: audit.OpPut, Queue: req.Queue}, err)
+	}
+	// Without syncpoint a rejected payload fails only its own entry.
+	if schemaError(w, err) {
+		return
+	}
+	resp := PutBatchResponse{Status: "ok"}
+	for _, res := range results {
+		item := PutBatchResult{Status: "ok", MsgID: mqcore.FormatID(res.MsgID)}
+		if res.Err != nil {
+			item = PutBatchResult{Status: "error", Error: res.Err.Error()}
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	if err != nil {
+		slog.Error("[REST] PutBatch error",
+			"error", err,
+			"id", "925377de-d82e-459a-9530-d84b78b5f85b")
+		resp.Status = "error"
+		resp.Error = err.Error()
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) GetBatch(w http.ResponseWriter, r *http.Request) {
+	// Decode and validate the request.
+	var req GetBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Queue == "" {
+		http.Error(w, "queue required", http.StatusBadRequest)
+		return
+	}
+	if req.MaxMessages <= 0 {
+		http.Error(w, "max_messages required", http.StatusBadRequest)
+		return
+	}
+
+	if !h.admit(w, r, auth.OpGet, req.Queue) {
+		return
+	}
+
+	release, ok := h.longPoll(w, r, req.WaitMs)
+	if !ok {
+		return
+	}
+	defer release()
+
+	results, err := h.GW.GetBatch(req.Queue, req.MaxMessages, mqcore.GetOptions{
+		WaitMs:   req.WaitMs,
+		MaxBytes: req.MaxMsgBytes,
+	}, req.Syncpoint)
+	for _, res := range results {
+		if res.Message != nil {
+			h.record(r, messageRecord(audit.OpGet, req.Queue, res.Message), res.Err)
+		} else {
+			h.record(r, audit.Record{Operation: audit.OpGet, Queue: req.Queue}, res.Err)
+		}
+	}
+	if err != nil && len(results) == 0 {
+		h.record(r, audit.Record{Operation: audit.OpGet, Queue: req.Queue}, err)
+	}
+	resp := GetBatchResponse{Status: "ok", Empty: err == nil && len(results) == 0}
+	for _, res := range results {
+		item := GetBatchResult{Status: "error"}
+		if res.Err != nil {
+			item.Error = res.Err.Error()
+		} else {
+			item.Status = "ok"
+			item.Message = res.Message.Payload
+			item.MsgID = mqcore.FormatID(res.Message.MsgID)
+			item.CorrelID = mqcore.FormatID(res.Message.CorrelID)
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	if err != nil {
+		slog.Error("[REST] GetBatch error",
+			"error", err,
+			"id", "ec6fff68-bfff-4c05-8f79-d2b7b8bb7510")
+		resp.Status = "error"
+		resp.Error = err.Error()
+		w.WriteHeader(http.StatusBadGateway)
 	}
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(resp)
@@ -156,7 +755,20 @@ func (h *Handler) BrowseFirst(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.admit(w, r, auth.OpBrowse, req.Queue) {
+		return
+	}
+
+	release, ok := h.longPoll(w, r, req.WaitMs)
+	if !ok {
+		return
+	}
+	defer release()
+
 	msg, empty, browseID, err := h.GW.BrowseFirst(req.Queue, req.WaitMs, req.MaxMsgBytes)
+	if err != nil || !empty {
+		h.record(r, audit.Record{Operation: audit.OpBrowse, Queue: req.Queue}.WithPayload(msg), err)
+	}
 	resp := BrowseResponse{Status: "ok", Message: msg, Empty: empty, BrowseID: browseID}
 	if err != nil {
 		slog.Error("[REST] BrowseFirst error",
@@ -182,7 +794,22 @@ func (h *Handler) BrowseNext(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The cursor keeps its queue, so re-check browse rights on every call.
+	queue, qerr := h.GW.BrowseQueue(req.BrowseID)
+	if qerr == nil && !h.admit(w, r, auth.OpBrowse, queue) {
+		return
+	}
+
+	release, ok := h.longPoll(w, r, req.WaitMs)
+	if !ok {
+		return
+	}
+	defer release()
+
 	msg, empty, err := h.GW.BrowseNext(req.BrowseID, req.WaitMs, req.MaxMsgBytes)
+	if err != nil || !empty {
+		h.record(r, audit.Record{Operation: audit.OpBrowse, Queue: queue}.WithPayload(msg), err)
+	}
 	resp := BrowseResponse{Status: "ok", Message: msg, Empty: empty, BrowseID: req.BrowseID}
 	if err != nil {
 		slog.Error("[REST] BrowseNext error",
@@ -208,7 +835,12 @@ func (h *Handler) InquireQueue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.admit(w, r, auth.OpInquire, req.Queue) {
+		return
+	}
+
 	info, err := h.GW.InquireQueue(req.Queue)
+	h.record(r, audit.Record{Operation: audit.OpInquire, Queue: req.Queue}, err)
 	resp := InquireQueueResponse{Status: "ok"}
 	if err != nil {
 		slog.Error("[REST] InquireQueue error",
@@ -218,19 +850,188 @@ func (h *Handler) InquireQueue(w http.ResponseWriter, r *http.Request) {
 		resp.Error = err.Error()
 		w.WriteHeader(http.StatusBadGateway)
 	} else {
-		resp.Queue = info.Name
-		resp.QueueDesc = info.Description
-		resp.QueueType = info.Type
-		resp.QueueUsage = info.Usage
-		resp.DefPersistence = info.DefPersistence
-		resp.InhibitGet = info.InhibitGet
-		resp.InhibitPut = info.InhibitPut
-		resp.CurrentQDepth = info.CurrentDepth
-		resp.MaxQDepth = info.MaxDepth
-		resp.OpenInputCount = info.OpenInputCount
-		resp.OpenOutputCount = info.OpenOutputCount
+		resp.setInfo(info)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// deadLetterQueue resolves the dead-letter queue a DLQ call works on. The
+// caller answers 502 on an error before authorizing anything, so a DEADQ
+// that cannot be looked up never lets the call through unchecked.
+func (h *Handler) deadLetterQueue(queue string) (string, error) {
+	resolve := h.GW.ResolveDLQ
+	if h.resolveDLQ != nil {
+		resolve = h.resolveDLQ
+	}
+	dlq, err := resolve(queue)
+	if err != nil {
+		slog.Error("[REST] cannot resolve dead-letter queue",
+			"queue", queue,
+			"error", err,
+			"id", "4b8e1c27-6a3d-4f95-b0e2-9c7d5a1f8e63")
+		return "", err
+	}
+	return dlq, nil
+}
+
+func (h *Handler) BrowseDLQ(w http.ResponseWriter, r *http.Request) {
+	// Decode the request; every field is optional.
+	var req DLQBrowseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	filter := mqcore.DLQFilter{Reasons: req.Reasons, DestQueue: req.DestQueue}
+	dlq, err := h.deadLetterQueue(req.Queue)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		_ = json.NewEncoder(w).Encode(DLQBrowseResponse{Status: "error", Error: err.Error()})
+		return
+	}
+	if !h.admit(w, r, auth.OpBrowse, dlq) {
+		return
+	}
+
+	dls, err := h.GW.BrowseDLQ(dlq, filter, req.MaxMessages, req.MaxMsgBytes)
+	for _, dl := range dls {
+		h.record(r, audit.Record{Operation: audit.OpDLQBrowse, Queue: dlq, MsgID: mqcore.FormatID(dl.MsgID)}.WithPayload(dl.Payload), nil)
+	}
+	if err != nil && len(dls) == 0 {
+		h.record(r, audit.Record{Operation: audit.OpDLQBrowse, Queue: dlq}, err)
+	}
+	resp := DLQBrowseResponse{Status: "ok"}
+	for _, dl := range dls {
+		resp.Messages = append(resp.Messages, DeadLetterMessage{
+			MsgID:       mqcore.FormatID(dl.MsgID),
+			Reason:      dl.Reason,
+			ReasonText:  dl.ReasonText,
+			DestQueue:   dl.DestQName,
+			DestQMgr:    dl.DestQMgrName,
+			Format:      dl.Format,
+			PutApplName: dl.PutApplName,
+			PutTime:     formatTime(dl.PutTime),
+			Message:     dl.Payload,
+		})
+	}
+	if err != nil {
+		slog.Error("[REST] BrowseDLQ error",
+			"error", err,
+			"id", "3f09ab07-926b-4477-a23b-ae51ea130835")
+		resp.Status = "error"
+		resp.Error = err.Error()
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) ReplayDLQ(w http.ResponseWriter, r *http.Request) {
+	// Decode the request; every field is optional.
+	var req DLQReplayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
 	}
 
+	// Replay removes from the DLQ; a dry run only browses it.
+	dlqOp := auth.OpGet
+	if req.DryRun {
+		dlqOp = auth.OpBrowse
+	}
+	dlq, err := h.deadLetterQueue(req.Queue)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		_ = json.NewEncoder(w).Encode(DLQReplayResponse{Status: "error", Error: err.Error()})
+		return
+	}
+	if !h.admit(w, r, dlqOp, dlq) {
+		return
+	}
+
+	results, err := h.GW.ReplayDLQ(mqcore.ReplayOptions{
+		DLQ:         dlq,
+		Filter:      mqcore.DLQFilter{Reasons: req.Reasons, DestQueue: req.DestQueue},
+		Destination: req.Destination,
+		MaxMessages: req.MaxMessages,
+		MaxBytes:    req.MaxMsgBytes,
+		DryRun:      req.DryRun,
+		Authorize: func(target, targetQMgr string) error {
+			// Another queue manager's queues are outside the policy's
+			// namespace, so routing there also needs admin.
+			if targetQMgr != "" {
+				if err := h.Authz.Authorize(r.Context(), auth.OpAdmin, target); err != nil {
+					return err
+				}
+			}
+			return h.Authz.Authorize(r.Context(), auth.OpPut, target)
+		},
+	})
+	// Previews move nothing, so only real replays are audited.
+	for _, res := range results {
+		if req.DryRun {
+			break
+		}
+		h.record(r, audit.Record{
+			Operation: audit.OpDLQReplay,
+			Queue:     dlq,
+			Target:    res.Target,
+			MsgID:     mqcore.FormatID(res.MsgID),
+		}.WithPayload(res.Payload), res.Err)
+	}
+	if err != nil && len(results) == 0 {
+		h.record(r, audit.Record{Operation: audit.OpDLQReplay, Queue: dlq}, err)
+	}
+	resp := DLQReplayResponse{Status: "ok", DryRun: req.DryRun}
+	for _, res := range results {
+		item := DLQReplayResult{
+			Status:     "preview",
+			MsgID:      mqcore.FormatID(res.MsgID),
+			Reason:     res.Reason,
+			ReasonText: res.ReasonText,
+			DestQueue:  res.DestQName,
+			Target:     res.Target,
+		}
+		if res.Err != nil {
+			item.Status = "error"
+			item.Error = res.Err.Error()
+		} else if res.Replayed {
+			item.Status = "replayed"
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	if err != nil {
+		slog.Error("[REST] ReplayDLQ error",
+			"error", err,
+			"id", "3fe0e776-9b9b-43fd-bf18-1719dbe54315")
+		resp.Status = "error"
+		resp.Error = err.Error()
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func (h *Handler) Stats(w http.ResponseWriter, r *http.Request) {
+	// Stats reports gateway counters; it takes no request body.
+	resp := StatsResponse{
+		Status:      "ok",
+		HandleCache: h.GW.HandleCacheStats(),
+		Backout:     h.GW.BackoutStats(),
+		RateLimit:   h.Limits.Stats(),
+		Router:      h.Router.Stats(),
+	}
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(resp)
 }
@@ -240,8 +1041,26 @@ func (h *Handler) Routes() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/put", h.Put)
 	mux.HandleFunc("/get", h.Get)
+	mux.HandleFunc("/put/group", h.PutGroup)
+	mux.HandleFunc("/get/group", h.GetGroup)
+	mux.HandleFunc("/put/batch", h.PutBatch)
+	mux.HandleFunc("/get/batch", h.GetBatch)
 	mux.HandleFunc("/browse/first", h.BrowseFirst)
 	mux.HandleFunc("/browse/next", h.BrowseNext)
 	mux.HandleFunc("/inquire/queue", h.InquireQueue)
+	mux.HandleFunc("/dlq/browse", h.BrowseDLQ)
+	mux.HandleFunc("/dlq/replay", h.ReplayDLQ)
+	mux.HandleFunc("/admin/export", h.Export)
+	mux.HandleFunc("/admin/import", h.Import)
+	mux.HandleFunc("/transfer", h.Transfer)
+	mux.HandleFunc("/schedule", h.Schedule)
+	mux.HandleFunc("/schedule/list", h.ListScheduled)
+	mux.HandleFunc("/schedule/cancel", h.CancelScheduled)
+	mux.HandleFunc("GET /queues/{name}/stream", h.Stream)
+	mux.HandleFunc("POST /queues/{name}/stream/ack", h.StreamAck)
+	h.routesV2(mux)
+	mux.HandleFunc("/stats", h.Stats)
+	mux.HandleFunc("GET /openapi.json", h.OpenAPI)
+	mux.HandleFunc("GET /docs", h.Docs)
 	return mux
 }
@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+)
+
+func TestDLQResolveFailure(t *testing.T) {
+	// A DEADQ that cannot be looked up fails the call before authorization;
+	// the zero gateway behind h would fault if BrowseDLQ or ReplayDLQ ran.
+	h := &Handler{
+		Authz:      &auth.Policy{},
+		resolveDLQ: func(string) (string, error) { return "", errors.New("MQINQ(DEADQ): MQRC_CONNECTION_BROKEN") },
+	}
+	for _, c := range []struct {
+		name string
+		call http.HandlerFunc
+		body string
+	}{
+		{"browse", h.BrowseDLQ, `{}`},
+		{"replay", h.ReplayDLQ, `{}`},
+		{"dry run", h.ReplayDLQ, `{"dry_run":true}`},
+	} {
+		w := httptest.NewRecorder()
+		c.call(w, httptest.NewRequest("POST", "/dlq", strings.NewReader(c.body)))
+		var resp struct{ Status, Error string }
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if w.Code != http.StatusBadGateway || resp.Status != "error" || resp.Error == "" {
+			t.Errorf("%s: %d %+v, want 502 with the error", c.name, w.Code, resp)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/audit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+type ScheduleRequest struct {
+	// Target queue name.
+	Queue string `json:"queue"`
+	// Payload to deliver.
+	Message string `json:"message"`
+	// Exactly one of DeliverAt (RFC 3339) and DelayMs sets the due time.
+	DeliverAt string `json:"deliver_at,omitempty"`
+	DelayMs   int64  `json:"delay_ms,omitempty"`
+	// Optional reply queue; marks the message as a request.
+	ReplyToQueue string `json:"reply_to_queue,omitempty"`
+}
+
+type ScheduleResponse struct {
+	Status string `json:"status"`
+	// ScheduleID is the MsgId (hex), which the message keeps when delivered.
+	ScheduleID string `json:"schedule_id,omitempty"`
+	// DeliverAt is RFC 3339.
+	DeliverAt string `json:"deliver_at,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type ListScheduledRequest struct {
+	// Target queue name.
+	Queue string `json:"queue"`
+	// Maximum number of messages to return.
+	MaxMessages int `json:"max_messages"`
+	// Max message size in bytes (per message).
+	MaxMsgBytes int `json:"max_msg_bytes"`
+}
+
+type ScheduledMessage struct {
+	ScheduleID string `json:"schedule_id"`
+	Queue      string `json:"queue"`
+	// DeliverAt is RFC 3339.
+	DeliverAt    string `json:"deliver_at"`
+	ReplyToQueue string `json:"reply_to_queue,omitempty"`
+	Message      string `json:"message"`
+}
+
+type ListScheduledResponse struct {
+	Status   string             `json:"status"`
+	Messages []ScheduledMessage `json:"messages,omitempty"`
+	Error    string             `json:"error,omitempty"`
+}
+
+type CancelScheduledRequest struct {
+	// Target queue the message was scheduled for.
+	Queue      string `json:"queue"`
+	ScheduleID string `json:"schedule_id"`
+}
+
+type CancelScheduledResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// scheduleError writes the status for the scheduler's sentinel errors and
+// reports whether it did; other errors are answered as 502 by the caller.
+func scheduleError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, mqcore.ErrSchedulingDisabled):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	case errors.Is(err, mqcore.ErrScheduleNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		return false
+	}
+	return true
+}
+
+// Schedule stages a message for delivery to a queue at a later time.
+func (h *Handler) Schedule(w http.ResponseWriter, r *http.Request) {
+	var req ScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Queue == "" {
+		http.Error(w, "queue required", http.StatusBadRequest)
+		return
+	}
+	dueAt, err := mqcore.DueTime(req.DeliverAt, req.DelayMs, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Scheduling is a deferred put to the target queue.
+	if !h.admit(w, r, auth.OpPut, req.Queue) {
+		return
+	}
+
+	id, err := h.GW.Schedule(req.Queue, req.Message, dueAt, mqcore.PutOptions{ReplyToQueue: req.ReplyToQueue})
+	h.record(r, audit.Record{
+		Operation: audit.OpSchedule,
+		Queue:     req.Queue,
+		MsgID:     mqcore.FormatID(id),
+	}.WithPayload(req.Message), err)
+	if scheduleError(w, err) || schemaError(w, err) {
+		return
+	}
+	resp := ScheduleResponse{Status: "ok", ScheduleID: mqcore.FormatID(id), DeliverAt: formatTime(dueAt)}
+	if err != nil {
+		slog.Error("[REST] Schedule error",
+			"error", err,
+			"id", "baca47a3-2fae-4e18-a2da-17a7712ba783")
+		resp = ScheduleResponse{Status: "error", Error: err.Error()}
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// ListScheduled returns the messages still waiting for delivery to a queue.
+func (h *Handler) ListScheduled(w http.ResponseWriter, r *http.Request) {
+	var req ListScheduledRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Queue == "" {
+		http.Error(w, "queue required", http.StatusBadRequest)
+		return
+	}
+
+	if !h.admit(w, r, auth.OpBrowse, req.Queue) {
+		return
+	}
+
+	sms, err := h.GW.ListScheduled(req.Queue, req.MaxMessages, req.MaxMsgBytes)
+	for _, sm := range sms {
+		h.record(r, audit.Record{Operation: audit.OpScheduleList, Queue: sm.Queue, MsgID: mqcore.FormatID(sm.ID)}.WithPayload(sm.Payload), nil)
+	}
+	if err != nil && len(sms) == 0 {
+		h.record(r, audit.Record{Operation: audit.OpScheduleList, Queue: req.Queue}, err)
+	}
+	if scheduleError(w, err) {
+		return
+	}
+	resp := ListScheduledResponse{Status: "ok"}
+	for _, sm := range sms {
+		resp.Messages = append(resp.Messages, ScheduledMessage{
+			ScheduleID:   mqcore.FormatID(sm.ID),
+			Queue:        sm.Queue,
+			DeliverAt:    formatTime(sm.DueAt),
+			ReplyToQueue: sm.ReplyToQueue,
+			Message:      sm.Payload,
+		})
+	}
+	if err != nil {
+		slog.Error("[REST] ListScheduled error",
+			"error", err,
+			"id", "76195e16-4c05-47ee-ab23-668d2ca80e43")
+		resp.Status = "error"
+		resp.Error = err.Error()
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// CancelScheduled removes a pending message before it is delivered.
+func (h *Handler) CancelScheduled(w http.ResponseWriter, r *http.Request) {
+	var req CancelScheduledRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Queue == "" || req.ScheduleID == "" {
+		http.Error(w, "queue and schedule_id required", http.StatusBadRequest)
+		return
+	}
+	id, err := mqcore.ParseID(req.ScheduleID)
+	if err != nil {
+		http.Error(w, "schedule_id: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Cancelling withdraws a put that has not happened yet.
+	if !h.admit(w, r, auth.OpPut, req.Queue) {
+		return
+	}
+
+	sm, err := h.GW.CancelScheduled(req.Queue, id)
+	rec := audit.Record{Operation: audit.OpScheduleCancel, Queue: req.Queue, MsgID: req.ScheduleID}
+	if sm != nil {
+		rec = rec.WithPayload(sm.Payload)
+	}
+	h.record(r, rec, err)
+	if scheduleError(w, err) {
+		return
+	}
+	resp := CancelScheduledResponse{Status: "ok"}
+	if err != nil {
+		slog.Error("[REST] CancelScheduled error",
+			"error", err,
+			"id", "578846d7-7ecb-455e-bae0-5ea3fb410fd2")
+		resp.Status = "error"
+		resp.Error = err.Error()
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,452 @@
+package rest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/audit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+const (
+	// streamPollMs bounds each MQ wait so heartbeats and disconnects are
+	// noticed between messages.
+	streamPollMs = 1000
+	// streamWriteTimeout disconnects a client that stops reading, so a
+	// stalled consumer cannot pin a queue handle.
+	streamWriteTimeout = 10 * time.Second
+	defaultHeartbeat   = 15 * time.Second
+	defaultAckTimeout  = 30 * time.Second
+)
+
+// StreamEvent is one event of a queue stream. SSE sends it as the data of an
+// event named after Type; WebSocket sends it as a JSON text frame.
+type StreamEvent struct {
+	// Type is "open", "message", "heartbeat", "expired" or "error".
+	Type string `json:"type"`
+	// StreamID names an SSE get stream for POST /queues/{name}/stream/ack.
+	StreamID string `json:"stream_id,omitempty"`
+	// Resumed is set on "open" when a browse stream continues after its resume token.
+	Resumed  bool   `json:"resumed,omitempty"`
+	Message  string `json:"message,omitempty"`
+	MsgID    string `json:"msg_id,omitempty"`
+	CorrelID string `json:"correl_id,omitempty"`
+	// Resume continues a browse stream after this message when passed back as
+	// ?resume= or, for SSE, as Last-Event-ID.
+	Resume string `json:"resume,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// StreamAckRequest acknowledges a message of an SSE get stream. WebSocket
+// clients send {"ack":"<msg_id>"} or {"nack":"<msg_id>"} on the socket instead.
+type StreamAckRequest struct {
+	StreamID string `json:"stream_id"`
+	MsgID    string `json:"msg_id"`
+	// Nack releases the message back to the queue instead of removing it.
+	Nack bool `json:"nack,omitempty"`
+}
+
+type streamAck struct {
+	msgID   string
+	release bool
+}
+
+// ackQueue holds the latest acknowledgement for a stream. Anything older is
+// stale by the time a newer one arrives, so it is replaced.
+type ackQueue struct {
+	queue string
+	mu    sync.Mutex
+	ch    chan streamAck
+}
+
+func newAckQueue(queue string) *ackQueue {
+	return &ackQueue{queue: queue, ch: make(chan streamAck, 1)}
+}
+
+func (q *ackQueue) offer(a streamAck) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	select {
+	case <-q.ch:
+	default:
+	}
+	q.ch <- a
+}
+
+type streamSink interface {
+	send(ev StreamEvent) error
+}
+
+type sseSink struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+func (s sseSink) send(ev StreamEvent) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_ = s.rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if ev.Resume != "" {
+		fmt.Fprintf(s.w, "id: %s\n", ev.Resume)
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", ev.Type, data); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+type wsSink struct {
+	ws *websocket.Conn
+}
+
+func (s wsSink) send(ev StreamEvent) error {
+	_ = s.ws.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	return websocket.JSON.Send(s.ws, ev)
+}
+
+// stream is one client's subscription to a queue.
+type stream struct {
+	h          *Handler
+	r          *http.Request
+	queue      string
+	get        bool
+	maxBytes   int
+	heartbeat  time.Duration
+	ackTimeout time.Duration
+	resume     []byte
+	streamID   string
+	lastSend   time.Time
+}
+
+// Stream delivers the messages of queue {name} over Server-Sent Events, or
+// over WebSocket when the request asks for an upgrade. Query parameters:
+// mode ("browse", the default, or "get"), resume (browse only), max_msg_bytes,
+// heartbeat and ack_timeout (seconds). Browse streams leave the queue
+// unchanged. Get streams lock one message at a time and remove it only when
+// the client acknowledges it; a message not acknowledged within ack_timeout,
+// or left when the client goes away, is released for redelivery. The next
+// message is only fetched once the previous one is written (and, in get mode,
+// acknowledged), so a slow client slows the stream instead of buffering it.
+func (h *Handler) Stream(w http.ResponseWriter, r *http.Request) {
+	queue := r.PathValue("name")
+	q := r.URL.Query()
+	s := &stream{h: h, r: r, queue: queue, heartbeat: defaultHeartbeat, ackTimeout: defaultAckTimeout}
+	switch q.Get("mode") {
+	case "", "browse":
+	case "get":
+		s.get = true
+	default:
+		http.Error(w, "mode: want browse or get", http.StatusBadRequest)
+		return
+	}
+	var err error
+	var heartbeat, ackTimeout int
+	if s.maxBytes, err = queryInt(q, "max_msg_bytes"); err == nil {
+		if heartbeat, err = queryInt(q, "heartbeat"); err == nil {
+			ackTimeout, err = queryInt(q, "ack_timeout")
+		}
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if heartbeat > 0 {
+		s.heartbeat = time.Duration(heartbeat) * time.Second
+	}
+	if ackTimeout > 0 {
+		s.ackTimeout = time.Duration(ackTimeout) * time.Second
+	}
+	if !s.get {
+		if s.resume, err = resumeToken(r); err != nil {
+			http.Error(w, "resume: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else if q.Get("resume") != "" {
+		http.Error(w, "resume applies to browse streams only", http.StatusBadRequest)
+		return
+	}
+
+	op := auth.OpBrowse
+	if s.get {
+		op = auth.OpGet
+	}
+	if !h.admit(w, r, op, queue) {
+		return
+	}
+	// A stream waits on the queue for as long as it is open.
+	release, ok := h.longPoll(w, r, math.MaxInt32)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		websocket.Server{Handshake: sameOrigin, Handler: s.serveWebSocket}.ServeHTTP(w, r)
+		return
+	}
+	s.serveSSE(w)
+}
+
+// resumeToken returns the message id a browse stream resumes after: the
+// resume parameter, or the Last-Event-ID an EventSource sends on reconnect.
+func resumeToken(r *http.Request) ([]byte, error) {
+	token := r.URL.Query().Get("resume")
+	if token == "" {
+		token = r.Header.Get("Last-Event-ID")
+	}
+	return mqcore.ParseID(token)
+}
+
+// sameOrigin accepts non-browser clients, which send no Origin, and pages
+// served from the gateway's own host.
+func sameOrigin(_ *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || !strings.EqualFold(u.Host, r.Host) {
+		return fmt.Errorf("cross-origin WebSocket from %q not allowed", origin)
+	}
+	return nil
+}
+
+func (s *stream) serveSSE(w http.ResponseWriter) {
+	var acks *ackQueue
+	if s.get {
+		id := make([]byte, 16)
+		_, _ = rand.Read(id)
+		s.streamID = hex.EncodeToString(id)
+		acks = newAckQueue(s.queue)
+		s.h.streams.Store(s.streamID, acks)
+		defer s.h.streams.Delete(s.streamID)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	s.run(s.r.Context(), sseSink{w: w, rc: http.NewResponseController(w)}, acks)
+}
+
+func (s *stream) serveWebSocket(ws *websocket.Conn) {
+	defer ws.Close()
+	ctx, cancel := context.WithCancel(s.r.Context())
+	defer cancel()
+
+	// The server's read timeout would otherwise close an idle socket; a
+	// read error now means the client went away.
+	_ = ws.SetReadDeadline(time.Time{})
+	acks := newAckQueue(s.queue)
+	go func() {
+		defer cancel()
+		for {
+			var msg struct {
+				Ack  string `json:"ack"`
+				Nack string `json:"nack"`
+			}
+			if err := websocket.JSON.Receive(ws, &msg); err != nil {
+				return
+			}
+			if msg.Nack != "" {
+				acks.offer(streamAck{msgID: msg.Nack, release: true})
+			} else if msg.Ack != "" {
+				acks.offer(streamAck{msgID: msg.Ack})
+			}
+		}
+	}()
+	s.run(ctx, wsSink{ws: ws}, acks)
+}
+
+func (s *stream) run(ctx context.Context, sink streamSink, acks *ackQueue) {
+	var err error
+	if s.get {
+		err = s.consume(ctx, sink, acks)
+	} else {
+		err = s.browse(ctx, sink)
+	}
+	if err != nil && ctx.Err() == nil {
+		slog.Error("[REST] Stream error",
+			"error", err,
+			"queue", s.queue,
+			"id", "49e6da25-364a-480c-91be-b43b93dd41c4")
+		_ = s.send(sink, StreamEvent{Type: "error", Error: err.Error()})
+	}
+}
+
+func (s *stream) send(sink streamSink, ev StreamEvent) error {
+	s.lastSend = time.Now()
+	return sink.send(ev)
+}
+
+// idle sends a heartbeat when nothing was sent for a heartbeat interval.
+func (s *stream) idle(sink streamSink) error {
+	if time.Since(s.lastSend) < s.heartbeat {
+		return nil
+	}
+	return s.send(sink, StreamEvent{Type: "heartbeat"})
+}
+
+func messageEvent(msg *mqcore.Message) StreamEvent {
+	return StreamEvent{
+		Type:     "message",
+		Message:  msg.Payload,
+		MsgID:    mqcore.FormatID(msg.MsgID),
+		CorrelID: mqcore.FormatID(msg.CorrelID),
+	}
+}
+
+// browse follows a browse cursor. A resume token positions the cursor on the
+// message it names, so the stream continues after it; when that message has
+// left the queue the stream starts again from the first message.
+func (s *stream) browse(ctx context.Context, sink streamSink) error {
+	b, err := s.h.GW.OpenBrowser(s.queue, s.maxBytes)
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+	resumed := false
+	if s.resume != nil {
+		msg, err := b.Next(mqcore.GetOptions{MsgID: s.resume})
+		if err != nil {
+			return err
+		}
+		resumed = msg != nil
+	}
+	if err := s.send(sink, StreamEvent{Type: "open", Resumed: resumed}); err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		msg, err := b.Next(mqcore.GetOptions{WaitMs: streamPollMs})
+		if err != nil {
+			return err
+		}
+		if msg == nil {
+			if err := s.idle(sink); err != nil {
+				return err
+			}
+			continue
+		}
+
+		ev := messageEvent(msg)
+		ev.Resume = ev.MsgID
+		s.h.record(s.r, audit.Record{Operation: audit.OpBrowse, Queue: s.queue, MsgID: ev.MsgID, CorrelID: ev.CorrelID}.WithPayload(msg.Payload), nil)
+		if err := s.send(sink, ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// consume delivers one locked message at a time and waits for its ack.
+func (s *stream) consume(ctx context.Context, sink streamSink, acks *ackQueue) error {
+	c, err := s.h.GW.OpenConsumer(s.queue, s.maxBytes)
+	if err != nil {
+		return err
+	}
+	// Closing the handle releases a message that was never acknowledged.
+	defer c.Close()
+	if err := s.send(sink, StreamEvent{Type: "open", StreamID: s.streamID}); err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		msg, err := c.Next(streamPollMs)
+		if err != nil {
+			return err
+		}
+		if msg == nil {
+			if err := s.idle(sink); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.send(sink, messageEvent(msg)); err != nil {
+			return err
+		}
+		if err := s.awaitAck(ctx, sink, c, msg, acks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *stream) awaitAck(ctx context.Context, sink streamSink, c *mqcore.Consumer, msg *mqcore.Message, acks *ackQueue) error {
+	msgID := mqcore.FormatID(msg.MsgID)
+	expire := time.NewTimer(s.ackTimeout)
+	defer expire.Stop()
+	beat := time.NewTicker(s.heartbeat)
+	defer beat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-expire.C:
+			if err := c.Release(); err != nil {
+				return err
+			}
+			return s.send(sink, StreamEvent{Type: "expired", MsgID: msgID})
+		case <-beat.C:
+			if err := s.idle(sink); err != nil {
+				return err
+			}
+		case a := <-acks.ch:
+			if a.msgID != msgID {
+				// An ack for a message that already expired.
+				continue
+			}
+			if a.release {
+				return c.Release()
+			}
+			err := c.Ack()
+			s.h.record(s.r, audit.Record{
+				Operation: audit.OpGet,
+				Queue:     s.queue,
+				MsgID:     msgID,
+				CorrelID:  mqcore.FormatID(msg.CorrelID),
+			}.WithPayload(msg.Payload), err)
+			return err
+		}
+	}
+}
+
+// StreamAck acknowledges (or with nack, releases) the message an SSE get
+// stream is waiting on.
+func (h *Handler) StreamAck(w http.ResponseWriter, r *http.Request) {
+	queue := r.PathValue("name")
+	var req StreamAckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.StreamID == "" || req.MsgID == "" {
+		http.Error(w, "stream_id and msg_id required", http.StatusBadRequest)
+		return
+	}
+	if err := h.Authz.Authorize(r.Context(), auth.OpGet, queue); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	v, ok := h.streams.Load(req.StreamID)
+	if !ok || v.(*ackQueue).queue != queue {
+		http.Error(w, "stream not found", http.StatusNotFound)
+		return
+	}
+	v.(*ackQueue).offer(streamAck{msgID: req.MsgID, release: req.Nack})
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,123 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+func TestAckQueueOffer(t *testing.T) {
+	q := newAckQueue("Q")
+	q.offer(streamAck{msgID: "01"})
+	q.offer(streamAck{msgID: "02", release: true})
+	if a := <-q.ch; a.msgID != "02" || !a.release {
+		t.Fatalf("ack = %+v, want the newer one", a)
+	}
+	select {
+	case a := <-q.ch:
+		t.Fatalf("stale ack %+v left queued", a)
+	default:
+	}
+}
+
+func TestSSESinkSend(t *testing.T) {
+	w := httptest.NewRecorder()
+	sink := sseSink{w: w, rc: http.NewResponseController(w)}
+	if err := sink.send(StreamEvent{Type: "heartbeat"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.send(StreamEvent{Type: "message", Message: "hi", MsgID: "0a", Resume: "0a"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "event: heartbeat\ndata: {\"type\":\"heartbeat\"}\n\n" +
+		"id: 0a\nevent: message\ndata: {\"type\":\"message\",\"message\":\"hi\",\"msg_id\":\"0a\",\"resume\":\"0a\"}\n\n"
+	if got := w.Body.String(); got != want {
+		t.Fatalf("SSE frames:\n%q\nwant\n%q", got, want)
+	}
+	if !w.Flushed {
+		t.Fatal("frames not flushed")
+	}
+}
+
+func TestResumeTokenRoundTrip(t *testing.T) {
+	msgID := make([]byte, 24)
+	copy(msgID, "AMQ QM1         \x01\x02\x03")
+
+	// The id line of a message event comes back as Last-Event-ID.
+	w := httptest.NewRecorder()
+	ev := messageEvent(&mqcore.Message{MsgID: msgID})
+	ev.Resume = ev.MsgID
+	if err := (sseSink{w: w, rc: http.NewResponseController(w)}).send(ev); err != nil {
+		t.Fatal(err)
+	}
+	id, ok := strings.CutPrefix(strings.SplitN(w.Body.String(), "\n", 2)[0], "id: ")
+	if !ok {
+		t.Fatalf("no id line in %q", w.Body.String())
+	}
+	r := httptest.NewRequest("GET", "/queues/Q/stream", nil)
+	r.Header.Set("Last-Event-ID", id)
+	if got, err := resumeToken(r); err != nil || !bytes.Equal(got, msgID) {
+		t.Fatalf("Last-Event-ID resumes at %x, %v; want %x", got, err, msgID)
+	}
+
+	// ?resume= wins over the header.
+	r = httptest.NewRequest("GET", "/queues/Q/stream?resume=ff", nil)
+	r.Header.Set("Last-Event-ID", id)
+	if got, err := resumeToken(r); err != nil || got[0] != 0xff || len(got) != len(msgID) {
+		t.Fatalf("resume parameter gives %x, %v", got, err)
+	}
+	if _, err := resumeToken(httptest.NewRequest("GET", "/queues/Q/stream?resume=xyz", nil)); err == nil {
+		t.Fatal("invalid resume token accepted")
+	}
+	if got, err := resumeToken(httptest.NewRequest("GET", "/queues/Q/stream", nil)); err != nil || got != nil {
+		t.Fatalf("no token gives %x, %v", got, err)
+	}
+}
+
+func TestStreamAck(t *testing.T) {
+	h := &Handler{Authz: &auth.Policy{Rules: []auth.Rule{
+		{Principals: []string{"alice"}, Queues: []string{"*"}, Operations: []auth.Operation{auth.OpGet}},
+	}}}
+	acks := newAckQueue("Q")
+	h.streams.Store("s1", acks)
+
+	ack := func(principal, queue, body string) int {
+		r := httptest.NewRequest("POST", "/queues/"+queue+"/stream/ack", strings.NewReader(body))
+		r.SetPathValue("name", queue)
+		r = r.WithContext(auth.WithPrincipal(context.Background(), &auth.Principal{Name: principal}))
+		w := httptest.NewRecorder()
+		h.StreamAck(w, r)
+		return w.Code
+	}
+
+	if code := ack("alice", "Q", `{"stream_id":"nope","msg_id":"01"}`); code != http.StatusNotFound {
+		t.Fatalf("unknown stream: %d", code)
+	}
+	if code := ack("alice", "OTHER", `{"stream_id":"s1","msg_id":"01"}`); code != http.StatusNotFound {
+		t.Fatalf("stream of another queue: %d", code)
+	}
+	if code := ack("bob", "Q", `{"stream_id":"s1","msg_id":"01"}`); code != http.StatusForbidden {
+		t.Fatalf("denied principal: %d", code)
+	}
+	if code := ack("alice", "Q", `{"stream_id":"s1"}`); code != http.StatusBadRequest {
+		t.Fatalf("missing msg_id: %d", code)
+	}
+	select {
+	case a := <-acks.ch:
+		t.Fatalf("rejected request queued %+v", a)
+	default:
+	}
+
+	if code := ack("alice", "Q", `{"stream_id":"s1","msg_id":"01","nack":true}`); code != http.StatusAccepted {
+		t.Fatalf("nack: %d", code)
+	}
+	if a := <-acks.ch; a.msgID != "01" || !a.release {
+		t.Fatalf("queued %+v", a)
+	}
+}
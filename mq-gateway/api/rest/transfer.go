@@ -0,0 +1,118 @@
+package rest
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/audit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+type TransferRequest struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	// Copy browses the source instead of moving its messages.
+	Copy bool `json:"copy"`
+	// Optional filters; ids are hex and the selector is SQL92 on message properties.
+	MsgID    string `json:"msg_id,omitempty"`
+	CorrelID string `json:"correl_id,omitempty"`
+	Selector string `json:"selector,omitempty"`
+	// BatchSize is the number of messages per unit of work (default 100).
+	BatchSize   int `json:"batch_size,omitempty"`
+	MaxMessages int `json:"max_messages,omitempty"`
+	MaxMsgBytes int `json:"max_msg_bytes,omitempty"`
+}
+
+// TransferProgress is one line of the NDJSON transfer response: "progress"
+// after each committed batch, then a final "ok" or "error".
+type TransferProgress struct {
+	Status           string `json:"status"`
+	Transferred      int    `json:"transferred"`
+	ContextPreserved bool   `json:"context_preserved"`
+	Error            string `json:"error,omitempty"`
+}
+
+// Transfer moves or copies messages between queues and streams progress as
+// NDJSON. A transfer that fails before its first batch is committed answers
+// 502 with a single TransferProgress instead.
+func (h *Handler) Transfer(w http.ResponseWriter, r *http.Request) {
+	var req TransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Source == "" || req.Destination == "" {
+		http.Error(w, "source and destination required", http.StatusBadRequest)
+		return
+	}
+	if strings.EqualFold(req.Source, req.Destination) {
+		http.Error(w, "source and destination must differ", http.StatusBadRequest)
+		return
+	}
+	msgID, err := mqcore.ParseID(req.MsgID)
+	if err != nil {
+		http.Error(w, "msg_id: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	correlID, err := mqcore.ParseID(req.CorrelID)
+	if err != nil {
+		http.Error(w, "correl_id: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// A move consumes the source; a copy only browses it.
+	srcOp, auditOp := auth.OpGet, audit.OpMove
+	if req.Copy {
+		srcOp, auditOp = auth.OpBrowse, audit.OpCopy
+	}
+	if !h.admit(w, r, srcOp, req.Source) || !h.admit(w, r, auth.OpPut, req.Destination) {
+		return
+	}
+
+	enc := json.NewEncoder(w)
+	started := false
+	res, err := h.GW.TransferMessages(mqcore.TransferOptions{
+		Source:      req.Source,
+		Destination: req.Destination,
+		Copy:        req.Copy,
+		MsgID:       msgID,
+		CorrelID:    correlID,
+		Selector:    req.Selector,
+		BatchSize:   req.BatchSize,
+		MaxMessages: req.MaxMessages,
+		MaxBytes:    req.MaxMsgBytes,
+		Progress: func(p mqcore.TransferResult) {
+			if !started {
+				started = true
+				w.Header().Set("Content-Type", "application/x-ndjson")
+			}
+			_ = enc.Encode(TransferProgress{Status: "progress", Transferred: p.Transferred, ContextPreserved: p.ContextPreserved})
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		},
+	})
+	h.record(r, audit.Record{Operation: auditOp, Queue: req.Source, Target: req.Destination, Count: res.Transferred}, err)
+
+	resp := TransferProgress{Status: "ok", Transferred: res.Transferred, ContextPreserved: res.ContextPreserved}
+	if err != nil {
+		slog.Error("[REST] Transfer error",
+			"error", err,
+			"source", req.Source,
+			"destination", req.Destination,
+			"transferred", res.Transferred,
+			"id", "9c26ed4d-ee2f-47d0-8995-50a791ad982f")
+		resp.Status = "error"
+		resp.Error = err.Error()
+		if !started {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	} else if !started {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	_ = enc.Encode(resp)
+}
@@ -0,0 +1,14 @@
+package rest
+
+import "net/http"
+
+// The v2 API is not written by hand: it is the google.api.http bindings in
+// mq.proto, transcoded onto the gRPC service by Handler.V2. Requests and
+// responses use the proto messages with their field names, path and query
+// parameters fill request fields, and a response with status "error"
+// answers 502. New RPCs with an http option show up here without REST code.
+func (h *Handler) routesV2(mux *http.ServeMux) {
+	if h.V2 != nil {
+		mux.Handle("/v2/", h.V2)
+	}
+}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqclient"
+)
+
+var errNoMessage = errors.New("no message available")
+
+// parse parses a command's flags and returns its single queue argument,
+// which may come before or after the flags.
+func parse(fs *flag.FlagSet, args []string) (string, error) {
+	var queue string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		queue, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if queue == "" && fs.NArg() > 0 {
+		queue = fs.Arg(0)
+	}
+	if queue == "" || fs.NArg() > 1 || (fs.NArg() == 1 && fs.Arg(0) != queue) {
+		fs.Usage()
+		return "", errors.New("exactly one queue required")
+	}
+	return queue, nil
+}
+
+func newFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: mqgw %s [flags] <queue>\n", name)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// payloadFlags selects where put and request read their payload from.
+type payloadFlags struct {
+	message string
+	file    string
+}
+
+func (p *payloadFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&p.message, "m", "", "message payload")
+	fs.StringVar(&p.file, "f", "", `read the payload from a file ("-" for stdin)`)
+}
+
+// read returns the payload; with neither -m nor -f it reads stdin.
+func (p *payloadFlags) read() (string, error) {
+	switch {
+	case p.message != "" && p.file != "":
+		return "", errors.New("-m and -f are mutually exclusive")
+	case p.message != "":
+		return p.message, nil
+	case p.file != "" && p.file != "-":
+		b, err := os.ReadFile(p.file)
+		return string(b), err
+	default:
+		b, err := io.ReadAll(os.Stdin)
+		return string(b), err
+	}
+}
+
+func runPut(ctx context.Context, g *globals, args []string) error {
+	fs := newFlags("put")
+	var payload payloadFlags
+	payload.register(fs)
+	lines := fs.Bool("lines", false, "put each input line as a separate message")
+	replyTo := fs.String("reply-to", "", "reply-to queue")
+	key := fs.String("idempotency-key", "", "deduplication key, so a rerun does not put again; with -lines, line n uses key-n")
+	queue, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	body, err := payload.read()
+	if err != nil {
+		return err
+	}
+	messages := []string{body}
+	if *lines {
+		messages = nil
+		sc := bufio.NewScanner(strings.NewReader(body))
+		sc.Buffer(nil, 64<<20)
+		for sc.Scan() {
+			if sc.Text() != "" {
+				messages = append(messages, sc.Text())
+			}
+		}
+	}
+
+	c, err := g.connect()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	out := newPrinter(g.json)
+	for i, m := range messages {
+		opts := mqclient.PutOptions{ReplyToQueue: *replyTo, IdempotencyKey: *key}
+		if *key != "" && *lines {
+			opts.IdempotencyKey = fmt.Sprintf("%s-%d", *key, i+1)
+		}
+		msgID, err := c.Put(ctx, queue, m, opts)
+		if err != nil {
+			return err
+		}
+		out.put(queue, msgID)
+	}
+	return nil
+}
+
+// getFlags are shared by the commands that read messages.
+type getFlags struct {
+	waitMs   int
+	maxBytes int
+}
+
+func (f *getFlags) register(fs *flag.FlagSet, defaultWaitMs int) {
+	fs.IntVar(&f.waitMs, "wait", defaultWaitMs, "wait for a message, in milliseconds")
+	fs.IntVar(&f.maxBytes, "max-bytes", 0, "largest message accepted (0 uses the gateway default)")
+}
+
+func (f *getFlags) options() mqclient.GetOptions {
+	return mqclient.GetOptions{WaitMs: f.waitMs, MaxMsgBytes: f.maxBytes}
+}
+
+func runGet(ctx context.Context, g *globals, args []string) error {
+	fs := newFlags("get")
+	var gf getFlags
+	gf.register(fs, 0)
+	correlID := fs.String("correl-id", "", "only get the message with this hex CorrelId")
+	queue, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	c, err := g.connect()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	opts := gf.options()
+	opts.CorrelID = *correlID
+	msg, err := c.Get(ctx, queue, opts)
+	if err != nil {
+		return err
+	}
+	if msg == nil {
+		return errNoMessage
+	}
+	newPrinter(g.json).message(queue, msg)
+	return nil
+}
+
+func runBrowse(ctx context.Context, g *globals, args []string) error {
+	fs := newFlags("browse")
+	var gf getFlags
+	gf.register(fs, 0)
+	limit := fs.Int("n", 10, "print at most n messages (0 for all)")
+	queue, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	c, err := g.connect()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	out := newPrinter(g.json)
+	msg, browseID, err := c.BrowseFirst(ctx, queue, gf.options())
+	for n := 0; err == nil && msg != nil; n++ {
+		if *limit > 0 && n == *limit {
+			break
+		}
+		out.message(queue, msg)
+		msg, err = c.BrowseNext(ctx, browseID, mqclient.GetOptions{MaxMsgBytes: gf.maxBytes})
+	}
+	return err
+}
+
+func runInquire(ctx context.Context, g *globals, args []string) error {
+	fs := newFlags("inquire")
+	queue, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	c, err := g.connect()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	info, err := c.InquireQueue(ctx, queue)
+	if err != nil {
+		return err
+	}
+	newPrinter(g.json).queueInfo(info)
+	return nil
+}
+
+// runRequest puts a request naming -reply-to as its reply queue and waits for
+// the reply, matched by the convention that a reply's CorrelId is the
+// request's MsgId.
+func runRequest(ctx context.Context, g *globals, args []string) error {
+	fs := newFlags("request")
+	var payload payloadFlags
+	payload.register(fs)
+	var gf getFlags
+	gf.register(fs, 30000)
+	replyTo := fs.String("reply-to", "", "queue the reply is sent to (required)")
+	queue, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if *replyTo == "" {
+		fs.Usage()
+		return errors.New("-reply-to required")
+	}
+	body, err := payload.read()
+	if err != nil {
+		return err
+	}
+
+	c, err := g.connect()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	msgID, err := c.Put(ctx, queue, body, mqclient.PutOptions{ReplyToQueue: *replyTo})
+	if err != nil {
+		return err
+	}
+	opts := gf.options()
+	opts.CorrelID = msgID
+	reply, err := c.Get(ctx, *replyTo, opts)
+	if err != nil {
+		return err
+	}
+	if reply == nil {
+		return fmt.Errorf("no reply on %s within %dms (request msg_id %s)", *replyTo, gf.waitMs, msgID)
+	}
+	newPrinter(g.json).message(*replyTo, reply)
+	return nil
+}
+
+func runDrain(ctx context.Context, g *globals, args []string) error {
+	fs := newFlags("drain")
+	var gf getFlags
+	gf.register(fs, 0)
+	limit := fs.Int("max", 0, "stop after this many messages (0 for no limit)")
+	quiet := fs.Bool("q", false, "only report the number of messages removed")
+	queue, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	c, err := g.connect()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	out := newPrinter(g.json)
+	n := 0
+	for ; *limit == 0 || n < *limit; n++ {
+		msg, err := c.Get(ctx, queue, gf.options())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "drained %d messages from %s\n", n, queue)
+			return err
+		}
+		if msg == nil {
+			break
+		}
+		if !*quiet {
+			out.message(queue, msg)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "drained %d messages from %s\n", n, queue)
+	return nil
+}
+
+// runTail follows a queue with a browse cursor, so messages are printed as
+// they arrive but stay on the queue. Messages already on the queue are
+// skipped unless -all is set. It runs until interrupted.
+func runTail(ctx context.Context, g *globals, args []string) error {
+	fs := newFlags("tail")
+	var gf getFlags
+	gf.register(fs, 5000)
+	all := fs.Bool("all", false, "print messages already on the queue first")
+	queue, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if gf.waitMs <= 0 {
+		return errors.New("-wait must be positive")
+	}
+
+	c, err := g.connect()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	var browseID string
+	if !*all {
+		// Move the cursor past the current messages without printing them.
+		skip := mqclient.GetOptions{MaxMsgBytes: gf.maxBytes}
+		msg, id, err := c.BrowseFirst(ctx, queue, skip)
+		for err == nil && msg != nil {
+			browseID = id
+			msg, err = c.BrowseNext(ctx, browseID, skip)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	out := newPrinter(g.json)
+	for ctx.Err() == nil {
+		var msg *mqclient.Message
+		if browseID == "" {
+			// An empty queue leaves no cursor open, so keep waiting on a new one.
+			msg, browseID, err = c.BrowseFirst(ctx, queue, gf.options())
+		} else {
+			msg, err = c.BrowseNext(ctx, browseID, gf.options())
+		}
+		if err != nil {
+			return err
+		}
+		if msg != nil {
+			out.message(queue, msg)
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqrest"
+)
+
+func TestParseQueueArgument(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{[]string{"DEV.QUEUE.1"}, "DEV.QUEUE.1", false},
+		{[]string{"DEV.QUEUE.1", "-wait", "10"}, "DEV.QUEUE.1", false},
+		{[]string{"-wait", "10", "DEV.QUEUE.1"}, "DEV.QUEUE.1", false},
+		{[]string{}, "", true},
+		{[]string{"A", "B"}, "", true},
+	}
+	for _, tt := range tests {
+		fs := newFlags("get")
+		fs.SetOutput(io.Discard)
+		fs.Int("wait", 0, "")
+		got, err := parse(fs, tt.args)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parse(%q) = %q, %v", tt.args, got, err)
+		}
+	}
+}
+
+// fakeGateway is an in-memory REST gateway: puts append to a queue and the
+// reply to a request is a copy of it correlated by MsgId.
+type fakeGateway struct {
+	mu     sync.Mutex
+	queues map[string][]mqrest.GetResponse
+}
+
+func (f *fakeGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.URL.Path {
+	case "/put":
+		var req mqrest.PutRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		id := "4d5347" + string(rune('0'+len(f.queues[req.Queue])))
+		f.queues[req.Queue] = append(f.queues[req.Queue], mqrest.GetResponse{Status: "ok", Message: req.Message, MsgID: id})
+		if req.ReplyToQueue != "" {
+			f.queues[req.ReplyToQueue] = append(f.queues[req.ReplyToQueue], mqrest.GetResponse{Status: "ok", Message: "re: " + req.Message, CorrelID: id})
+		}
+		_ = json.NewEncoder(w).Encode(mqrest.PutResponse{Status: "ok", MsgID: id})
+	case "/get":
+		var req mqrest.GetRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		for i, m := range f.queues[req.Queue] {
+			if req.CorrelID == "" || m.CorrelID == req.CorrelID {
+				f.queues[req.Queue] = append(f.queues[req.Queue][:i], f.queues[req.Queue][i+1:]...)
+				_ = json.NewEncoder(w).Encode(m)
+				return
+			}
+		}
+		_ = json.NewEncoder(w).Encode(mqrest.GetResponse{Status: "ok", Empty: true})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestCommandsOverREST(t *testing.T) {
+	fake := &fakeGateway{queues: make(map[string][]mqrest.GetResponse)}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	g := &globals{transport: "rest", restURL: srv.URL, retries: 1}
+	ctx := context.Background()
+
+	if err := runPut(ctx, g, []string{"Q1", "-m", "a\nb\n", "-lines"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(fake.queues["Q1"]); n != 2 {
+		t.Fatalf("Q1 depth = %d, want 2", n)
+	}
+	if err := runRequest(ctx, g, []string{"SVC", "-m", "ping", "-reply-to", "REPLY"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(fake.queues["REPLY"]); n != 0 {
+		t.Fatalf("reply left on REPLY: %d", n)
+	}
+	if err := runDrain(ctx, g, []string{"Q1", "-q"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGet(ctx, g, []string{"Q1"}); err != errNoMessage {
+		t.Fatalf("get on drained queue = %v, want errNoMessage", err)
+	}
+}
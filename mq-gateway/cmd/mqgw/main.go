@@ -0,0 +1,175 @@
+// Command mqgw works with queues through the gateway over gRPC or REST.
+//
+//	mqgw [global flags] <command> [flags] <queue>
+//
+// Commands: put, get, browse, inquire, request, drain, tail. Run
+// "mqgw <command> -h" for the flags of each command.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqclient"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqgrpc"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqrest"
+)
+
+func getenv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// globals are the connection flags shared by every command.
+type globals struct {
+	transport string
+	restURL   string
+	grpcAddr  string
+	apiKey    string
+	token     string
+	tls       bool
+	caFile    string
+	certFile  string
+	keyFile   string
+	retries   int
+	json      bool
+}
+
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, g *globals, args []string) error
+}
+
+var commands = []command{
+	{"put", "put a message from -m, -f or stdin", runPut},
+	{"get", "destructively get one message", runGet},
+	{"browse", "print messages without removing them", runBrowse},
+	{"inquire", "show queue attributes", runInquire},
+	{"request", "put a request and wait for its reply", runRequest},
+	{"drain", "get and print messages until the queue is empty", runDrain},
+	{"tail", "follow new messages without removing them", runTail},
+}
+
+func usage(fs *flag.FlagSet) func() {
+	return func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "usage: mqgw [global flags] <command> [flags] <queue>\n\ncommands:\n")
+		for _, c := range commands {
+			fmt.Fprintf(out, "  %-8s %s\n", c.name, c.summary)
+		}
+		fmt.Fprintf(out, "\nglobal flags:\n")
+		fs.PrintDefaults()
+	}
+}
+
+func main() {
+	g := &globals{}
+	fs := flag.NewFlagSet("mqgw", flag.ExitOnError)
+	fs.StringVar(&g.transport, "transport", getenv("MQGW_TRANSPORT", "grpc"), "grpc or rest (MQGW_TRANSPORT)")
+	fs.StringVar(&g.restURL, "url", getenv("MQ_GATEWAY_URL", "http://localhost:8080"), "REST base URL (MQ_GATEWAY_URL)")
+	fs.StringVar(&g.grpcAddr, "addr", getenv("MQ_GRPC_ADDR", "localhost:9090"), "gRPC address (MQ_GRPC_ADDR)")
+	fs.StringVar(&g.apiKey, "api-key", getenv("MQGW_API_KEY", ""), "API key (MQGW_API_KEY)")
+	fs.StringVar(&g.token, "token", getenv("MQGW_TOKEN", ""), "bearer token (MQGW_TOKEN)")
+	fs.BoolVar(&g.tls, "tls", false, "use TLS for gRPC (REST follows the URL scheme)")
+	fs.StringVar(&g.caFile, "cacert", "", "PEM CA bundle for verifying the gateway")
+	fs.StringVar(&g.certFile, "cert", "", "PEM client certificate for mTLS")
+	fs.StringVar(&g.keyFile, "key", "", "PEM client key for mTLS")
+	fs.IntVar(&g.retries, "retries", 3, "attempts for throttled or unavailable calls")
+	fs.BoolVar(&g.json, "json", false, "print JSON instead of text")
+	fs.Usage = usage(fs)
+	_ = fs.Parse(os.Args[1:])
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	name, args := fs.Arg(0), fs.Args()[1:]
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for _, c := range commands {
+		if c.name == name {
+			err := c.run(ctx, g, args)
+			if errors.Is(err, flag.ErrHelp) {
+				return
+			}
+			// Interrupting tail or a long wait is not an error.
+			if err != nil && ctx.Err() == nil {
+				fmt.Fprintln(os.Stderr, "mqgw:", err)
+				stop()
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "mqgw: unknown command %q\n\n", name)
+	fs.Usage()
+	os.Exit(2)
+}
+
+// connect returns a client for the selected transport.
+func (g *globals) connect() (mqclient.Client, error) {
+	tlsCfg, err := g.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	creds := mqclient.Credentials{APIKey: g.apiKey, BearerToken: g.token}
+	retry := mqclient.RetryPolicy{MaxAttempts: max(g.retries, 1)}
+
+	switch g.transport {
+	case "grpc":
+		if tlsCfg == nil && g.tls {
+			tlsCfg = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		c, err := mqgrpc.NewClient(mqgrpc.Config{Address: g.grpcAddr, TLSConfig: tlsCfg, Credentials: creds, Retry: retry})
+		if err != nil {
+			return nil, err
+		}
+		return c.AsClient(), nil
+	case "rest":
+		return mqrest.NewClient(mqrest.Config{BaseURL: g.restURL, TLSConfig: tlsCfg, Credentials: creds, Retry: retry}).AsClient(), nil
+	default:
+		return nil, fmt.Errorf("unknown transport %q (want grpc or rest)", g.transport)
+	}
+}
+
+// tlsConfig builds a client TLS config from -cacert/-cert/-key, or nil when none is set.
+func (g *globals) tlsConfig() (*tls.Config, error) {
+	if g.caFile == "" && g.certFile == "" && g.keyFile == "" {
+		return nil, nil
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if g.caFile != "" {
+		pem, err := os.ReadFile(g.caFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: no certificates found", g.caFile)
+		}
+	}
+	if g.certFile != "" || g.keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(g.certFile, g.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if strings.HasPrefix(g.restURL, "http://") && g.transport == "rest" {
+		return nil, errors.New("-cacert/-cert/-key need an https:// URL")
+	}
+	return cfg, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqclient"
+)
+
+// printer writes results as plain text or as one JSON object per line.
+type printer struct {
+	json bool
+	w    io.Writer
+}
+
+func newPrinter(asJSON bool) *printer {
+	return &printer{json: asJSON, w: os.Stdout}
+}
+
+func (p *printer) encode(v any) {
+	_ = json.NewEncoder(p.w).Encode(v)
+}
+
+func (p *printer) put(queue, msgID string) {
+	if p.json {
+		p.encode(struct {
+			Queue string `json:"queue"`
+			MsgID string `json:"msg_id"`
+		}{queue, msgID})
+		return
+	}
+	fmt.Fprintln(p.w, msgID)
+}
+
+// message prints a payload on its own line in text mode; MQMD ids are only
+// included in JSON output.
+func (p *printer) message(queue string, m *mqclient.Message) {
+	if p.json {
+		p.encode(struct {
+			Queue    string `json:"queue"`
+			MsgID    string `json:"msg_id,omitempty"`
+			CorrelID string `json:"correl_id,omitempty"`
+			Payload  string `json:"payload"`
+		}{queue, m.MsgID, m.CorrelID, m.Payload})
+		return
+	}
+	fmt.Fprint(p.w, m.Payload)
+	if !strings.HasSuffix(m.Payload, "\n") {
+		fmt.Fprintln(p.w)
+	}
+}
+
+func (p *printer) queueInfo(q *mqclient.QueueInfo) {
+	if p.json {
+		p.encode(struct {
+			Queue           string `json:"queue"`
+			QueueDesc       string `json:"queue_desc"`
+			QueueType       int32  `json:"queue_type"`
+			QueueUsage      int32  `json:"queue_usage"`
+			DefPersistence  int32  `json:"def_persistence"`
+			InhibitGet      int32  `json:"inhibit_get"`
+			InhibitPut      int32  `json:"inhibit_put"`
+			CurrentQDepth   int32  `json:"current_q_depth"`
+			MaxQDepth       int32  `json:"max_q_depth"`
+			OpenInputCount  int32  `json:"open_input_count"`
+			OpenOutputCount int32  `json:"open_output_count"`
+		}(*q))
+		return
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Queue:\t%s\n", q.Queue)
+	fmt.Fprintf(tw, "Description:\t%s\n", q.QueueDesc)
+	fmt.Fprintf(tw, "Depth:\t%d / %d\n", q.CurrentQDepth, q.MaxQDepth)
+	fmt.Fprintf(tw, "Open input/output:\t%d / %d\n", q.OpenInputCount, q.OpenOutputCount)
+	fmt.Fprintf(tw, "Get inhibited:\t%t\n", q.InhibitGet == 1)
+	fmt.Fprintf(tw, "Put inhibited:\t%t\n", q.InhibitPut == 1)
+	fmt.Fprintf(tw, "Type / usage / persistence:\t%d / %d / %d\n", q.QueueType, q.QueueUsage, q.DefPersistence)
+	_ = tw.Flush()
+}
@@ -3,14 +3,16 @@ module github.com/jlambert68/MQDockerContainer2/mq-gateway
 go  1.25.5
 
 require (
+	github.com/grpc-ecosystem/grpc-gateway/v2 v2.27.7
 	github.com/ibm-messaging/mq-golang/v5 v5.7.0
+	golang.org/x/net v0.47.0
+	google.golang.org/genproto/googleapis/api v0.0.0-20260128011058-8636f8732409
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260128011058-8636f8732409
 	google.golang.org/grpc v1.78.0
 	google.golang.org/protobuf v1.36.11
 )
 
 require (
-	golang.org/x/net v0.47.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
-	golang.org/x/text v0.31.0 // indirect
-	google.golang.org/genproto/googleapis/rpc v0.0.0-20251029180050-ab9386a59fda // indirect
+	golang.org/x/text v0.33.0 // indirect
 )
@@ -0,0 +1,200 @@
+// Package audit records one structured entry per message operation for
+// compliance. Records go to their own sink, separate from the service log.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+)
+
+// Operation names used in records.
+const (
+	OpPut       = "put"
+	OpGet       = "get"
+	OpBrowse    = "browse"
+	OpInquire   = "inquire"
+	OpDLQBrowse = "dlq_browse"
+	OpDLQReplay = "dlq_replay"
+	OpExport    = "export"
+	OpImport    = "import"
+	OpMove      = "move"
+	OpCopy      = "copy"
+	// Scheduled delivery: staging, listing and cancelling a message.
+	OpSchedule       = "schedule"
+	OpScheduleList   = "schedule_list"
+	OpScheduleCancel = "schedule_cancel"
+	// OpWebhook is a message pushed to an HTTP endpoint; Target names the route.
+	OpWebhook = "webhook"
+	// OpRoute is a message forwarded by the router; Target names the rule.
+	OpRoute = "route"
+)
+
+// Outcomes used in records.
+const (
+	OutcomeOK        = "ok"
+	OutcomeError     = "error"
+	OutcomeDenied    = "denied"
+	OutcomeThrottled = "throttled"
+	// OutcomeReplayed marks a put answered from an earlier put with the same
+	// idempotency key; nothing was put.
+	OutcomeReplayed = "replayed"
+	// OutcomeBackedOut marks a message moved to a backout queue after too
+	// many failed deliveries.
+	OutcomeBackedOut = "backed_out"
+	// OutcomeUnmatched marks a routed message no rule matched, sent to the
+	// unmatched or dead-letter queue.
+	OutcomeUnmatched = "unmatched"
+	// OutcomeInvalid marks a put whose payload its queue's schema rejected;
+	// nothing was put.
+	OutcomeInvalid = "invalid"
+)
+
+// Record is one audited operation on one message (or one queue for inquire,
+// export, import, move and copy).
+type Record struct {
+	Time       time.Time `json:"time"`
+	Operation  string    `json:"operation"`
+	Transport  string    `json:"transport"`
+	Principal  string    `json:"principal,omitempty"`
+	AuthMethod string    `json:"auth_method,omitempty"`
+	ClientAddr string    `json:"client_addr,omitempty"`
+	Queue      string    `json:"queue"`
+	// Target is the destination queue of a DLQ replay, move or copy, the
+	// route of a webhook delivery, or the rule that routed a message.
+	Target   string `json:"target,omitempty"`
+	MsgID    string `json:"msg_id,omitempty"`
+	CorrelID string `json:"correl_id,omitempty"`
+	GroupID  string `json:"group_id,omitempty"`
+	Size     int    `json:"size"`
+	// Count is the number of messages handled by a bulk export, import, move or copy.
+	Count int `json:"count,omitempty"`
+	// SHA256 is the hex digest of the payload; the payload itself is never recorded.
+	SHA256     string `json:"sha256,omitempty"`
+	Outcome    string `json:"outcome"`
+	ReasonCode int32  `json:"reason_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// WithPayload returns r with the payload size and digest filled in.
+func (r Record) WithPayload(payload string) Record {
+	sum := sha256.Sum256([]byte(payload))
+	r.Size = len(payload)
+	r.SHA256 = hex.EncodeToString(sum[:])
+	return r
+}
+
+// Sink stores encoded records.
+type Sink interface {
+	Write(line []byte) error
+	Close() error
+}
+
+// Logger writes records to a sink. A nil *Logger discards everything.
+type Logger struct {
+	mu   sync.Mutex
+	sink Sink
+}
+
+func New(sink Sink) *Logger {
+	return &Logger{sink: sink}
+}
+
+// Log stamps rec with the time and the principal from ctx, then writes it.
+// Sink failures are reported to the service log, not to the caller.
+func (l *Logger) Log(ctx context.Context, rec Record) {
+	if l == nil {
+		return
+	}
+	if rec.Time.IsZero() {
+		rec.Time = time.Now().UTC()
+	}
+	if p, ok := auth.FromContext(ctx); ok {
+		rec.Principal = p.Name
+		rec.AuthMethod = p.Method
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	err = l.sink.Write(line)
+	l.mu.Unlock()
+	if err != nil {
+		slog.Error("[audit] failed to write record",
+			"error", err,
+			"operation", rec.Operation,
+			"queue", rec.Queue,
+			"id", "9660caf3-405f-406b-93cb-eea1ee4473a3")
+	}
+}
+
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.sink.Close()
+}
+
+func getenv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func getint(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// FromEnv builds the audit logger from environment variables:
+//
+//	AUDIT_SINK          "file" or "queue"; unset disables auditing
+//	AUDIT_FILE          JSONL file for the file sink (default audit.jsonl)
+//	AUDIT_FILE_MAX_MB   rotate the file once it reaches this size (default 100)
+//	AUDIT_FILE_BACKUPS  rotated files to keep (default 5)
+//	AUDIT_QUEUE         queue for the queue sink
+//
+// The queue sink puts records through putter, normally the gateway itself.
+func FromEnv(putter Putter) (*Logger, error) {
+	switch sink := getenv("AUDIT_SINK", ""); sink {
+	case "":
+		return nil, nil
+	case "file":
+		fs, err := NewFileSink(getenv("AUDIT_FILE", "audit.jsonl"),
+			int64(getint("AUDIT_FILE_MAX_MB", 100))<<20, getint("AUDIT_FILE_BACKUPS", 5))
+		if err != nil {
+			return nil, err
+		}
+		return New(fs), nil
+	case "queue":
+		queue := getenv("AUDIT_QUEUE", "")
+		if queue == "" {
+			return nil, fmt.Errorf("AUDIT_SINK=queue needs AUDIT_QUEUE")
+		}
+		return New(&QueueSink{Queue: queue, Putter: putter}), nil
+	default:
+		return nil, fmt.Errorf("unknown AUDIT_SINK %q", sink)
+	}
+}
@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+)
+
+type memSink struct{ lines []string }
+
+func (m *memSink) Write(line []byte) error { m.lines = append(m.lines, string(line)); return nil }
+func (m *memSink) Close() error            { return nil }
+
+func TestLoggerRecordsPrincipalAndDigest(t *testing.T) {
+	sink := &memSink{}
+	l := New(sink)
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Name: "orders-svc", Method: "jwt"})
+
+	l.Log(ctx, Record{Operation: OpPut, Queue: "ORDERS.IN", Outcome: OutcomeOK}.WithPayload("hello"))
+
+	if len(sink.lines) != 1 {
+		t.Fatalf("got %d records, want 1", len(sink.lines))
+	}
+	var rec Record
+	if err := json.Unmarshal([]byte(sink.lines[0]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Principal != "orders-svc" || rec.AuthMethod != "jwt" || rec.Time.IsZero() {
+		t.Fatalf("unexpected record %+v", rec)
+	}
+	// sha256("hello")
+	if rec.Size != 5 || rec.SHA256 != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Fatalf("payload digest: size=%d sha256=%s", rec.Size, rec.SHA256)
+	}
+	if strings.Contains(sink.lines[0], "hello") {
+		t.Fatalf("record leaked the payload: %s", sink.lines[0])
+	}
+
+	var nilLogger *Logger
+	nilLogger.Log(ctx, rec)
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	n := 0
+	for sc := bufio.NewScanner(f); sc.Scan(); {
+		n++
+	}
+	return n
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	line := []byte(strings.Repeat("x", 99))
+	// Room for three 100-byte lines per file.
+	s, err := NewFileSink(path, 300, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := s.Write(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := countLines(t, path); got != 1 {
+		t.Errorf("current file has %d lines, want 1", got)
+	}
+	for _, backup := range []string{path + ".1", path + ".2"} {
+		if got := countLines(t, backup); got != 3 {
+			t.Errorf("%s has %d lines, want 3", backup, got)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 backups, stat .3: %v", err)
+	}
+}
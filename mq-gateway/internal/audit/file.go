@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+)
+
+// FileSink appends records as JSON lines and rotates the file by size,
+// keeping path.1 (newest) through path.<backups>.
+type FileSink struct {
+	path     string
+	maxBytes int64
+	backups  int
+
+	f    *os.File
+	size int64
+}
+
+// NewFileSink opens (or creates) path for appending. maxBytes <= 0 disables rotation.
+func NewFileSink(path string, maxBytes int64, backups int) (*FileSink, error) {
+	s := &FileSink{path: path, maxBytes: maxBytes, backups: backups}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.f, s.size = f, st.Size()
+	return nil
+}
+
+func (s *FileSink) Write(line []byte) error {
+	if s.maxBytes > 0 && s.size > 0 && s.size+int64(len(line))+1 > s.maxBytes {
+		if err := s.rotate(); err != nil {
+			return fmt.Errorf("rotate %s: %w", s.path, err)
+		}
+	}
+	n, err := s.f.Write(append(line, '\n'))
+	s.size += int64(n)
+	return err
+}
+
+func (s *FileSink) rotate() error {
+	if err := s.f.Close(); err != nil {
+		return err
+	}
+	if s.backups <= 0 {
+		// Nothing to keep: start the file over.
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return s.open()
+	}
+	// Shift path.N-1 -> path.N ... path -> path.1; the oldest is overwritten.
+	for i := s.backups - 1; i >= 1; i-- {
+		from := fmt.Sprintf("%s.%d", s.path, i)
+		if err := os.Rename(from, fmt.Sprintf("%s.%d", s.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		return err
+	}
+	return s.open()
+}
+
+func (s *FileSink) Close() error {
+	return s.f.Close()
+}
@@ -0,0 +1,20 @@
+package audit
+
+// Putter puts a message on a queue; *mqcore.Gateway satisfies it.
+type Putter interface {
+	Put(queueName, message string) error
+}
+
+// QueueSink puts each record as a message on an MQ queue.
+type QueueSink struct {
+	Queue  string
+	Putter Putter
+}
+
+func (s *QueueSink) Write(line []byte) error {
+	return s.Putter.Put(s.Queue, string(line))
+}
+
+func (s *QueueSink) Close() error {
+	return nil
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// APIKeyAuthenticator accepts static keys sent as "X-API-Key: <key>" or
+// "Authorization: ApiKey <key>".
+type APIKeyAuthenticator struct {
+	// keys maps the SHA-256 of each key to its principal name.
+	keys map[[sha256.Size]byte]string
+}
+
+// NewAPIKeyAuthenticator loads name=key pairs from a comma-separated list and/or a file.
+func NewAPIKeyAuthenticator(list string, file string) (*APIKeyAuthenticator, error) {
+	a := &APIKeyAuthenticator{keys: make(map[[sha256.Size]byte]string)}
+
+	var pairs []string
+	if list != "" {
+		pairs = append(pairs, strings.Split(list, ",")...)
+	}
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			pairs = append(pairs, line)
+		}
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, pair := range pairs {
+		name, key, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		name, key = strings.TrimSpace(name), strings.TrimSpace(key)
+		if !ok || name == "" || key == "" {
+			return nil, fmt.Errorf("invalid entry %q, want name=key", pair)
+		}
+		a.keys[sha256.Sum256([]byte(key))] = name
+	}
+	if len(a.keys) == 0 {
+		return nil, errors.New("no keys configured")
+	}
+	return a, nil
+}
+
+func (a *APIKeyAuthenticator) Authenticate(req *Request) (*Principal, error) {
+	key := strings.TrimSpace(req.Header("X-API-Key"))
+	if key == "" {
+		if scheme, rest, ok := strings.Cut(req.Header("Authorization"), " "); ok && strings.EqualFold(scheme, "ApiKey") {
+			key = strings.TrimSpace(rest)
+		}
+	}
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+
+	// Compare digests in constant time so key length and content do not leak.
+	sum := sha256.Sum256([]byte(key))
+	for known, name := range a.keys {
+		if subtle.ConstantTimeCompare(sum[:], known[:]) == 1 {
+			return &Principal{Name: name, Method: "api_key"}, nil
+		}
+	}
+	return nil, errors.New("invalid API key")
+}
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// ErrNoCredentials is returned by an Authenticator when the request carries
+// none of the credentials it understands, so the next one can be tried.
+var ErrNoCredentials = errors.New("no credentials")
+
+// Principal is an authenticated caller.
+type Principal struct {
+	// Name identifies the caller (API key name, JWT subject or certificate CN).
+	Name string
+	// Method is "api_key", "jwt" or "mtls".
+	Method string
+	// RemoteAddr is the client address the request came from.
+	RemoteAddr string
+	// Claims holds verified JWT claims; nil for other methods.
+	Claims map[string]any
+}
+
+// Request is the transport-neutral view of an incoming call.
+type Request struct {
+	// Header returns the first value of a header (HTTP) or metadata key (gRPC).
+	Header func(name string) string
+	// TLS is the connection state when the listener serves TLS.
+	TLS *tls.ConnectionState
+	// RemoteAddr is the peer address.
+	RemoteAddr string
+}
+
+// Authenticator verifies the credentials on a request.
+type Authenticator interface {
+	Authenticate(req *Request) (*Principal, error)
+}
+
+// Chain tries each Authenticator in order and returns the first principal.
+// An Authenticator that finds bad credentials stops the chain.
+type Chain []Authenticator
+
+func (c Chain) Authenticate(req *Request) (*Principal, error) {
+	for _, a := range c {
+		p, err := a.Authenticate(req)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		p.RemoteAddr = req.RemoteAddr
+		return p, nil
+	}
+	return nil, ErrNoCredentials
+}
+
+type principalKey struct{}
+
+// WithPrincipal stores p in ctx.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored by the REST middleware or gRPC interceptors.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+func getenv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func getbool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "1", "true", "yes", "y", "on":
+		return true
+	case "0", "false", "no", "n", "off":
+		return false
+	default:
+		return def
+	}
+}
+
+// FromEnv builds the authenticator chain from environment variables:
+//
+//	AUTH_API_KEYS       comma-separated name=key pairs
+//	AUTH_API_KEYS_FILE  file with one name=key pair per line
+//	AUTH_JWKS_FILE      JWKS used to verify bearer tokens
+//	AUTH_JWT_ISSUER     required "iss" claim (optional)
+//	AUTH_JWT_AUDIENCE   required "aud" claim (optional)
+//	AUTH_MTLS           accept verified client certificates
+//
+// It returns nil when nothing is configured, which leaves the API open.
+func FromEnv() (Authenticator, error) {
+	var chain Chain
+
+	keys := getenv("AUTH_API_KEYS", "")
+	keysFile := getenv("AUTH_API_KEYS_FILE", "")
+	if keys != "" || keysFile != "" {
+		a, err := NewAPIKeyAuthenticator(keys, keysFile)
+		if err != nil {
+			return nil, fmt.Errorf("api keys: %w", err)
+		}
+		chain = append(chain, a)
+	}
+
+	if jwksFile := getenv("AUTH_JWKS_FILE", ""); jwksFile != "" {
+		a, err := NewJWTAuthenticator(jwksFile, getenv("AUTH_JWT_ISSUER", ""), getenv("AUTH_JWT_AUDIENCE", ""))
+		if err != nil {
+			return nil, fmt.Errorf("jwt: %w", err)
+		}
+		chain = append(chain, a)
+	}
+
+	if getbool("AUTH_MTLS", false) {
+		chain = append(chain, MTLSAuthenticator{})
+	}
+
+	if len(chain) == 0 {
+		slog.Warn("[auth] no authentication configured; REST and gRPC are open to any caller",
+			"id", "ae31e914-0118-4074-9b45-453ebc8a1420")
+		return nil, nil
+	}
+	return chain, nil
+}
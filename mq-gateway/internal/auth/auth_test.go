@@ -0,0 +1,235 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func headers(h map[string]string) *Request {
+	return &Request{Header: func(name string) string { return h[name] }, RemoteAddr: "10.0.0.1:5000"}
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	a, err := NewAPIKeyAuthenticator("alice=s3cret, bob=hunter2", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := a.Authenticate(headers(map[string]string{"X-API-Key": "s3cret"}))
+	if err != nil || p.Name != "alice" || p.Method != "api_key" {
+		t.Fatalf("X-API-Key: got %+v, %v", p, err)
+	}
+	p, err = a.Authenticate(headers(map[string]string{"Authorization": "ApiKey hunter2"}))
+	if err != nil || p.Name != "bob" {
+		t.Fatalf("Authorization: got %+v, %v", p, err)
+	}
+	if _, err := a.Authenticate(headers(map[string]string{"X-API-Key": "nope"})); err == nil || errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("wrong key: got %v", err)
+	}
+	if _, err := a.Authenticate(headers(nil)); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("no key: got %v", err)
+	}
+	if _, err := NewAPIKeyAuthenticator("missing-equals", ""); err == nil {
+		t.Fatal("expected error for malformed entry")
+	}
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	enc := func(v any) string {
+		b, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signed := enc(map[string]string{"alg": "RS256", "kid": kid}) + "." + enc(claims)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "k1",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewJWTAuthenticator(path, "issuer", "mq-gateway")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := float64(time.Now().Add(time.Hour).Unix())
+	bearer := func(tok string) *Request {
+		return headers(map[string]string{"Authorization": "Bearer " + tok})
+	}
+
+	tok := signRS256(t, key, "k1", map[string]any{"sub": "svc-a", "iss": "issuer", "aud": []string{"mq-gateway"}, "exp": exp})
+	p, err := a.Authenticate(bearer(tok))
+	if err != nil || p.Name != "svc-a" || p.Method != "jwt" || p.Claims["iss"] != "issuer" {
+		t.Fatalf("valid token: got %+v, %v", p, err)
+	}
+
+	tests := map[string]map[string]any{
+		"expired":        {"sub": "svc-a", "iss": "issuer", "aud": "mq-gateway", "exp": float64(time.Now().Add(-time.Hour).Unix())},
+		"missing exp":    {"sub": "svc-a", "iss": "issuer", "aud": "mq-gateway"},
+		"wrong issuer":   {"sub": "svc-a", "iss": "other", "aud": "mq-gateway", "exp": exp},
+		"wrong audience": {"sub": "svc-a", "iss": "issuer", "aud": "other", "exp": exp},
+		"missing sub":    {"iss": "issuer", "aud": "mq-gateway", "exp": exp},
+	}
+	for name, claims := range tests {
+		if _, err := a.Authenticate(bearer(signRS256(t, key, "k1", claims))); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	forged := signRS256(t, other, "k1", map[string]any{"sub": "svc-a", "iss": "issuer", "aud": "mq-gateway", "exp": exp})
+	if _, err := a.Authenticate(bearer(forged)); err == nil {
+		t.Error("forged signature: expected error")
+	}
+
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"k1"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"svc-a"}`)) + "."
+	if _, err := a.Authenticate(bearer(none)); err == nil {
+		t.Error("alg none: expected error")
+	}
+}
+
+func TestMTLSAuthenticator(t *testing.T) {
+	var a MTLSAuthenticator
+	if _, err := a.Authenticate(&Request{}); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("no TLS: got %v", err)
+	}
+
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "client-1"}}
+	unverified := &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	if _, err := a.Authenticate(&Request{TLS: unverified}); err == nil || errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("unverified: got %v", err)
+	}
+
+	verified := &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	}
+	p, err := a.Authenticate(&Request{TLS: verified})
+	if err != nil || p.Name != "client-1" || p.Method != "mtls" {
+		t.Fatalf("verified: got %+v, %v", p, err)
+	}
+}
+
+func TestChain(t *testing.T) {
+	keys, err := NewAPIKeyAuthenticator("alice=s3cret", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := Chain{MTLSAuthenticator{}, keys}
+
+	p, err := c.Authenticate(headers(map[string]string{"X-API-Key": "s3cret"}))
+	if err != nil || p.Name != "alice" || p.RemoteAddr != "10.0.0.1:5000" {
+		t.Fatalf("fallthrough: got %+v, %v", p, err)
+	}
+	if _, err := c.Authenticate(headers(nil)); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("no credentials: got %v", err)
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"ORDERS.*", "ORDERS.IN", true},
+		{"ORDERS.*", "ORDERS.", true},
+		{"ORDERS.*", "ORDERS", false},
+		{"ORDERS.*", "orders.IN", false},
+		{"*.IN", "ORDERS.EU.IN", true},
+		{"APP.?", "APP.1", true},
+		{"APP.?", "APP.12", false},
+		{"*", "", true},
+		{"A*B*C", "AxxBxxC", true},
+		{"A*B*C", "AxxBxx", false},
+		{"DEV/*", "DEV/Q1", true},
+	}
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	err := os.WriteFile(path, []byte(`{"rules": [
+		{"principals": ["orders-svc"], "queues": ["ORDERS.*"], "operations": ["put", "get"]},
+		{"principals": ["ops-*"], "queues": ["*"], "operations": ["browse", "inquire"]},
+		{"principals": ["admin"], "queues": ["*"], "operations": ["*"]}
+	]}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		principal string
+		op        Operation
+		queue     string
+		want      bool
+	}{
+		{"orders-svc", OpPut, "ORDERS.IN", true},
+		{"orders-svc", OpBrowse, "ORDERS.IN", false},
+		{"orders-svc", OpPut, "PAYMENTS.IN", false},
+		{"ops-alice", OpInquire, "PAYMENTS.IN", true},
+		{"ops-alice", OpGet, "PAYMENTS.IN", false},
+		{"admin", OpGet, "ANY.Q", true},
+		{"", OpPut, "ORDERS.IN", false},
+	}
+	for _, tt := range tests {
+		if got := p.Allowed(tt.principal, tt.op, tt.queue); got != tt.want {
+			t.Errorf("Allowed(%q, %s, %q) = %v, want %v", tt.principal, tt.op, tt.queue, got, tt.want)
+		}
+	}
+
+	ctx := WithPrincipal(context.Background(), &Principal{Name: "orders-svc"})
+	if err := p.Authorize(ctx, OpGet, "PAYMENTS.IN"); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("Authorize: got %v, want ErrPermissionDenied", err)
+	}
+	var open *Policy
+	if err := open.Authorize(context.Background(), OpPut, "ANY.Q"); err != nil {
+		t.Fatalf("nil policy: got %v", err)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.json")
+	_ = os.WriteFile(bad, []byte(`{"rules": [{"principals": ["a"], "queues": ["Q"], "operations": ["delete"]}]}`), 0o600)
+	if _, err := LoadPolicy(bad); err == nil {
+		t.Fatal("expected error for unknown operation")
+	}
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor authenticates unary RPCs and stores the principal in the context.
+func UnaryServerInterceptor(a Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticateGRPC(ctx, a, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates streaming RPCs and stores the principal in the stream context.
+func StreamServerInterceptor(a Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticateGRPC(ss.Context(), a, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &principalStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// principalStream overrides Context so handlers see the authenticated principal.
+type principalStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *principalStream) Context() context.Context {
+	return s.ctx
+}
+
+func authenticateGRPC(ctx context.Context, a Authenticator, method string) (context.Context, error) {
+	if a == nil {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	req := &Request{
+		Header: func(name string) string {
+			if v := md.Get(name); len(v) > 0 {
+				return v[0]
+			}
+			return ""
+		},
+	}
+	if pr, ok := peer.FromContext(ctx); ok {
+		req.RemoteAddr = pr.Addr.String()
+		if tlsInfo, ok := pr.AuthInfo.(credentials.TLSInfo); ok {
+			req.TLS = &tlsInfo.State
+		}
+	}
+
+	p, err := a.Authenticate(req)
+	if err != nil {
+		slog.Warn("[gRPC] authentication failed",
+			"error", err,
+			"remote_addr", req.RemoteAddr,
+			"method", method,
+			"id", "c73f77bd-7eea-41d6-9cf7-340ece03da87")
+		return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+	}
+	return WithPrincipal(ctx, p), nil
+}
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// Middleware authenticates every request before it reaches next and stores
+// the principal in the request context. A nil Authenticator disables the check.
+func Middleware(a Authenticator, next http.Handler) http.Handler {
+	if a == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, err := a.Authenticate(&Request{
+			Header:     r.Header.Get,
+			TLS:        r.TLS,
+			RemoteAddr: r.RemoteAddr,
+		})
+		if err != nil {
+			slog.Warn("[REST] authentication failed",
+				"error", err,
+				"remote_addr", r.RemoteAddr,
+				"path", r.URL.Path,
+				"id", "a245d86a-405e-4c23-8565-5e10031159bf")
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mq-gateway"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+	})
+}
@@ -0,0 +1,28 @@
+package auth
+
+import "errors"
+
+// MTLSAuthenticator accepts callers that presented a client certificate the
+// TLS listener verified. The principal is the certificate's common name, or
+// its first DNS name when the CN is empty.
+type MTLSAuthenticator struct{}
+
+func (MTLSAuthenticator) Authenticate(req *Request) (*Principal, error) {
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return nil, ErrNoCredentials
+	}
+	// Only chains verified against the listener's client CA count.
+	if len(req.TLS.VerifiedChains) == 0 {
+		return nil, errors.New("client certificate not verified")
+	}
+
+	leaf := req.TLS.VerifiedChains[0][0]
+	name := leaf.Subject.CommonName
+	if name == "" && len(leaf.DNSNames) > 0 {
+		name = leaf.DNSNames[0]
+	}
+	if name == "" {
+		return nil, errors.New("client certificate has no CN or DNS name")
+	}
+	return &Principal{Name: name, Method: "mtls"}, nil
+}
@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// ErrPermissionDenied is returned by Policy.Authorize when no rule grants access.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Operation is a class of queue access checked by a Policy.
+type Operation string
+
+const (
+	OpPut     Operation = "put"
+	OpGet     Operation = "get"
+	OpBrowse  Operation = "browse"
+	OpInquire Operation = "inquire"
+	// OpAdmin covers bulk queue export and import, and replaying dead letters
+	// to a remote queue manager. It is not implied by the other operations,
+	// only by "*".
+	OpAdmin Operation = "admin"
+)
+
+// Rule grants Operations on Queues to Principals. Principal and queue
+// entries are glob patterns where "*" matches any run of characters and "?"
+// one character, so "ORDERS.*" covers every queue under ORDERS. An operation
+// of "*" grants all of them.
+type Rule struct {
+	Principals []string    `json:"principals"`
+	Queues     []string    `json:"queues"`
+	Operations []Operation `json:"operations"`
+}
+
+// Policy is an allow-list of rules; anything not granted is denied.
+// A nil *Policy allows everything.
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// LoadPolicy reads a JSON policy file of the form {"rules": [...]}.
+func LoadPolicy(path string) (*Policy, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for i, r := range p.Rules {
+		if len(r.Principals) == 0 || len(r.Queues) == 0 || len(r.Operations) == 0 {
+			return nil, fmt.Errorf("%s: rule %d needs principals, queues and operations", path, i)
+		}
+		for _, op := range r.Operations {
+			switch op {
+			case OpPut, OpGet, OpBrowse, OpInquire, OpAdmin, "*":
+			default:
+				return nil, fmt.Errorf("%s: rule %d: unknown operation %q", path, i, op)
+			}
+		}
+	}
+	return &p, nil
+}
+
+// PolicyFromEnv loads the policy named by AUTHZ_POLICY_FILE. It returns nil
+// when the variable is unset, which leaves every queue open to every caller.
+func PolicyFromEnv() (*Policy, error) {
+	path := getenv("AUTHZ_POLICY_FILE", "")
+	if path == "" {
+		return nil, nil
+	}
+	return LoadPolicy(path)
+}
+
+// Allowed reports whether principal may perform op on queue.
+func (p *Policy) Allowed(principal string, op Operation, queue string) bool {
+	if p == nil {
+		return true
+	}
+	for _, r := range p.Rules {
+		if matchAny(r.Principals, principal) && matchAny(r.Queues, queue) && r.grants(op) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) grants(op Operation) bool {
+	for _, o := range r.Operations {
+		if o == op || o == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize checks the principal stored in ctx and logs denials.
+func (p *Policy) Authorize(ctx context.Context, op Operation, queue string) error {
+	if p == nil {
+		return nil
+	}
+	var name, remote string
+	if pr, ok := FromContext(ctx); ok {
+		name, remote = pr.Name, pr.RemoteAddr
+	}
+	if p.Allowed(name, op, queue) {
+		return nil
+	}
+
+	if name == "" {
+		name = "anonymous"
+	}
+	slog.Warn("[auth] access denied",
+		"principal", name,
+		"operation", string(op),
+		"queue", queue,
+		"remote_addr", remote,
+		"id", "0f4212d3-3cf5-4138-8e3e-19a9ccb665fc")
+	return fmt.Errorf("%w: %s may not %s %s", ErrPermissionDenied, name, op, queue)
+}
+
+func matchAny(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if MatchGlob(p, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchGlob matches s against a pattern with "*" and "?" wildcards. Unlike
+// path.Match, "*" also spans "/", which is legal in MQ object names.
+func MatchGlob(pattern, s string) bool {
+	px, sx := 0, 0
+	// Position to resume from after the last "*", for backtracking.
+	starPx, starSx := -1, 0
+	for sx < len(s) {
+		switch {
+		case px < len(pattern) && (pattern[px] == '?' || pattern[px] == s[sx]):
+			px++
+			sx++
+		case px < len(pattern) && pattern[px] == '*':
+			starPx, starSx = px, sx
+			px++
+		case starPx >= 0:
+			starSx++
+			px, sx = starPx+1, starSx
+		default:
+			return false
+		}
+	}
+	for px < len(pattern) && pattern[px] == '*' {
+		px++
+	}
+	return px == len(pattern)
+}
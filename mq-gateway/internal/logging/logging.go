@@ -3,11 +3,27 @@ package logging
 import (
 	"log/slog"
 	"os"
+	"strings"
 )
 
+// redactedValue replaces the value of any attribute whose key looks sensitive.
+const redactedValue = "[REDACTED]"
+
+// defaultSensitiveKeys are matched case-insensitively as substrings of attribute keys.
+var defaultSensitiveKeys = []string{"password", "passwd", "secret", "token", "apikey", "api_key", "authorization", "securityparms"}
+
 func Init(service string) {
+	// LOG_REDACT_KEYS adds comma-separated key fragments to the defaults.
+	keys := append([]string(nil), defaultSensitiveKeys...)
+	for _, k := range strings.Split(os.Getenv("LOG_REDACT_KEYS"), ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+
 	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
+		Level:       slog.LevelInfo,
+		ReplaceAttr: Redact(keys...),
 	})
 
 	logger := slog.New(handler).With(
@@ -16,3 +32,21 @@ func Init(service string) {
 
 	slog.SetDefault(logger)
 }
+
+// Redact returns a slog ReplaceAttr function that masks attributes whose key
+// contains any of keys, ignoring case. Nested group members are checked too.
+func Redact(keys ...string) func(groups []string, a slog.Attr) slog.Attr {
+	lower := make([]string, len(keys))
+	for i, k := range keys {
+		lower[i] = strings.ToLower(k)
+	}
+	return func(_ []string, a slog.Attr) slog.Attr {
+		key := strings.ToLower(a.Key)
+		for _, k := range lower {
+			if strings.Contains(key, k) {
+				return slog.String(a.Key, redactedValue)
+			}
+		}
+		return a
+	}
+}
@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: Redact(append(defaultSensitiveKeys, "ssn")...),
+	}))
+
+	logger.Info("connect",
+		"csp.UserId", "app",
+		"csp.Password", "passw0rd",
+		"Authorization", "Bearer abc.def.ghi",
+		"customer_ssn", "123-45-6789",
+		slog.Group("mq", "api_key", "k-123", "queue", "ORDERS.IN"))
+
+	out := buf.String()
+	for _, leaked := range []string{"passw0rd", "abc.def.ghi", "123-45-6789", "k-123"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("log output leaked %q: %s", leaked, out)
+		}
+	}
+	for _, kept := range []string{`"csp.UserId":"app"`, `"queue":"ORDERS.IN"`, `"csp.Password":"[REDACTED]"`} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("log output missing %s: %s", kept, out)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package mqcore
+
+import (
+	"archive/tar"
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ArchiveFormat selects how exported messages are serialized.
+type ArchiveFormat string
+
+const (
+	// ArchiveJSONL writes one JSON object per line with a base64 payload.
+	ArchiveJSONL ArchiveFormat = "jsonl"
+	// ArchiveTar writes a NNNNNN.json descriptor and a NNNNNN.payload file per message.
+	ArchiveTar ArchiveFormat = "tar"
+)
+
+// ParseArchiveFormat maps "jsonl" (the default for "") and "tar" to a format.
+func ParseArchiveFormat(s string) (ArchiveFormat, error) {
+	switch f := ArchiveFormat(strings.ToLower(s)); f {
+	case "":
+		return ArchiveJSONL, nil
+	case ArchiveJSONL, ArchiveTar:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown archive format %q (want jsonl or tar)", s)
+	}
+}
+
+// ArchivedMessage is one exported message: its MQMD, message properties and
+// the payload bytes exactly as stored on the queue. Ids and the accounting
+// token are hex-encoded.
+type ArchivedMessage struct {
+	MsgID        string `json:"msg_id,omitempty"`
+	CorrelID     string `json:"correl_id,omitempty"`
+	GroupID      string `json:"group_id,omitempty"`
+	MsgSeqNumber int32  `json:"msg_seq_number,omitempty"`
+	Offset       int32  `json:"offset,omitempty"`
+	MsgFlags     int32  `json:"msg_flags,omitempty"`
+
+	Format         string `json:"format,omitempty"`
+	Encoding       int32  `json:"encoding"`
+	CodedCharSetID int32  `json:"ccsid"`
+	MsgType        int32  `json:"msg_type"`
+	Persistence    int32  `json:"persistence"`
+	Priority       int32  `json:"priority"`
+	Expiry         int32  `json:"expiry"`
+	Report         int32  `json:"report,omitempty"`
+	Feedback       int32  `json:"feedback,omitempty"`
+	ReplyToQ       string `json:"reply_to_q,omitempty"`
+	ReplyToQMgr    string `json:"reply_to_qmgr,omitempty"`
+	BackoutCount   int32  `json:"backout_count,omitempty"`
+
+	// Identity context.
+	UserIdentifier   string `json:"user_identifier,omitempty"`
+	AccountingToken  string `json:"accounting_token,omitempty"`
+	ApplIdentityData string `json:"appl_identity_data,omitempty"`
+	// Origin context.
+	PutApplType    int32     `json:"put_appl_type,omitempty"`
+	PutApplName    string    `json:"put_appl_name,omitempty"`
+	PutDateTime    time.Time `json:"put_date_time"`
+	ApplOriginData string    `json:"appl_origin_data,omitempty"`
+
+	Properties []Property `json:"properties,omitempty"`
+	// Payload is base64 in JSONL; tar archives carry it in a separate file.
+	Payload []byte `json:"payload,omitempty"`
+}
+
+// Property is a message property with its MQ type, so the value can be
+// restored with the same type on import.
+type Property struct {
+	Name string `json:"name"`
+	// Type is int8, int16, int32, int64, float32, float64, boolean, string, bytes or null.
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// newProperty records a value returned by MQINQMP.
+func newProperty(name string, v any) (Property, error) {
+	p := Property{Name: name}
+	switch v.(type) {
+	case int8:
+		p.Type = "int8"
+	case int16:
+		p.Type = "int16"
+	case int32:
+		p.Type = "int32"
+	case int64:
+		p.Type = "int64"
+	case float32:
+		p.Type = "float32"
+	case float64:
+		p.Type = "float64"
+	case bool:
+		p.Type = "boolean"
+	case string:
+		p.Type = "string"
+	case []byte:
+		p.Type = "bytes"
+	case nil:
+		p.Type = "null"
+		return p, nil
+	default:
+		return p, fmt.Errorf("property %s: unsupported type %T", name, v)
+	}
+	raw, err := json.Marshal(v)
+	p.Value = raw
+	return p, err
+}
+
+// GoValue returns the value with the Go type MQSETMP maps to p.Type.
+func (p Property) GoValue() (any, error) {
+	var v any
+	switch p.Type {
+	case "int8":
+		v = new(int8)
+	case "int16":
+		v = new(int16)
+	case "int32":
+		v = new(int32)
+	case "int64":
+		v = new(int64)
+	case "float32":
+		v = new(float32)
+	case "float64":
+		v = new(float64)
+	case "boolean":
+		v = new(bool)
+	case "string":
+		v = new(string)
+	case "bytes":
+		v = new([]byte)
+	case "null":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("property %s: unknown type %q", p.Name, p.Type)
+	}
+	if err := json.Unmarshal(p.Value, v); err != nil {
+		return nil, fmt.Errorf("property %s: %w", p.Name, err)
+	}
+	switch t := v.(type) {
+	case *int8:
+		return *t, nil
+	case *int16:
+		return *t, nil
+	case *int32:
+		return *t, nil
+	case *int64:
+		return *t, nil
+	case *float32:
+		return *t, nil
+	case *float64:
+		return *t, nil
+	case *bool:
+		return *t, nil
+	case *string:
+		return *t, nil
+	default:
+		return *v.(*[]byte), nil
+	}
+}
+
+// decodeToken decodes a hex id of up to n bytes, zero-padded to n.
+func decodeToken(s string, n int) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex: %w", err)
+	}
+	if len(b) > n {
+		return nil, fmt.Errorf("longer than %d bytes", n)
+	}
+	out := make([]byte, n)
+	copy(out, b)
+	return out, nil
+}
+
+// ArchiveWriter serializes exported messages. Close finishes the archive but
+// does not close the underlying writer.
+type ArchiveWriter interface {
+	WriteMessage(m *ArchivedMessage) error
+	Close() error
+}
+
+// ArchiveReader reads messages back; ReadMessage returns io.EOF at the end.
+type ArchiveReader interface {
+	ReadMessage() (*ArchivedMessage, error)
+}
+
+// NewArchiveWriter returns a writer for format on w.
+func NewArchiveWriter(format ArchiveFormat, w io.Writer) (ArchiveWriter, error) {
+	switch format {
+	case ArchiveJSONL:
+		bw := bufio.NewWriter(w)
+		return &jsonlWriter{bw: bw, enc: json.NewEncoder(bw)}, nil
+	case ArchiveTar:
+		return &tarWriter{tw: tar.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("unknown archive format %q", format)
+	}
+}
+
+// NewArchiveReader returns a reader for format on r.
+func NewArchiveReader(format ArchiveFormat, r io.Reader) (ArchiveReader, error) {
+	switch format {
+	case ArchiveJSONL:
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		return &jsonlReader{dec: dec}, nil
+	case ArchiveTar:
+		return &tarReader{tr: tar.NewReader(r)}, nil
+	default:
+		return nil, fmt.Errorf("unknown archive format %q", format)
+	}
+}
+
+type jsonlWriter struct {
+	bw  *bufio.Writer
+	enc *json.Encoder
+}
+
+func (w *jsonlWriter) WriteMessage(m *ArchivedMessage) error {
+	if err := w.enc.Encode(m); err != nil {
+		return err
+	}
+	// Flush per message so a streamed export makes steady progress.
+	return w.bw.Flush()
+}
+
+func (w *jsonlWriter) Close() error { return w.bw.Flush() }
+
+type jsonlReader struct {
+	dec *json.Decoder
+	n   int
+}
+
+func (r *jsonlReader) ReadMessage() (*ArchivedMessage, error) {
+	var m ArchivedMessage
+	if err := r.dec.Decode(&m); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("message %d: %w", r.n+1, err)
+	}
+	r.n++
+	return &m, nil
+}
+
+type tarWriter struct {
+	tw *tar.Writer
+	n  int
+}
+
+func (w *tarWriter) WriteMessage(m *ArchivedMessage) error {
+	w.n++
+	meta := *m
+	meta.Payload = nil
+	desc, err := json.MarshalIndent(&meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	modTime := m.PutDateTime
+	if modTime.IsZero() {
+		modTime = time.Now()
+	}
+	base := fmt.Sprintf("%06d", w.n)
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{{base + ".json", desc}, {base + ".payload", m.Payload}} {
+		hdr := &tar.Header{Name: f.name, Mode: 0o644, Size: int64(len(f.data)), ModTime: modTime, Typeflag: tar.TypeReg}
+		if err := w.tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := w.tw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	return w.tw.Flush()
+}
+
+func (w *tarWriter) Close() error { return w.tw.Close() }
+
+type tarReader struct {
+	tr *tar.Reader
+}
+
+// next returns the next regular file in the archive.
+func (r *tarReader) next() (*tar.Header, []byte, error) {
+	for {
+		hdr, err := r.tr.Next()
+		if err != nil {
+			return nil, nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(r.tr)
+		return hdr, data, err
+	}
+}
+
+func (r *tarReader) ReadMessage() (*ArchivedMessage, error) {
+	hdr, desc, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+	base, ok := strings.CutSuffix(hdr.Name, ".json")
+	if !ok {
+		return nil, fmt.Errorf("%s: expected a .json descriptor", hdr.Name)
+	}
+	var m ArchivedMessage
+	if err := json.Unmarshal(desc, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", hdr.Name, err)
+	}
+
+	hdr, payload, err := r.next()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: missing payload file", base)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if hdr.Name != base+".payload" {
+		return nil, fmt.Errorf("%s: expected %s.payload", hdr.Name, base)
+	}
+	m.Payload = payload
+	return &m, nil
+}
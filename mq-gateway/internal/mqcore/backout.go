@@ -0,0 +1,202 @@
+package mqcore
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// backoutPolicyTTL bounds how long a queue's BOTHRESH/BOQNAME stay cached.
+const backoutPolicyTTL = time.Minute
+
+// backoutPolicy is a queue's backout threshold and requeue queue read through MQINQ.
+type backoutPolicy struct {
+	threshold int32
+	requeueQ  string
+	loadedAt  time.Time
+}
+
+// BackoutStats counts poison messages moved off their source queues.
+type BackoutStats struct {
+	MovedToBackoutQ uint64 `json:"moved_to_backout_q"`
+	MovedToDLQ      uint64 `json:"moved_to_dlq"`
+	Failures        uint64 `json:"failures"`
+}
+
+// BackoutStats returns a snapshot of the poison message counters.
+func (g *Gateway) BackoutStats() BackoutStats {
+	g.backoutMu.Lock()
+	defer g.backoutMu.Unlock()
+	return g.backoutStats
+}
+
+func (g *Gateway) backoutPolicy(queueName string) (backoutPolicy, error) {
+	// Serve from cache while fresh; BOTHRESH rarely changes.
+	g.backoutMu.Lock()
+	policy, ok := g.backoutPolicies[queueName]
+	g.backoutMu.Unlock()
+	if ok && time.Since(policy.loadedAt) < backoutPolicyTTL {
+		return policy, nil
+	}
+
+	h, err := g.acquireHandle(queueName, ibmmq.MQOO_INQUIRE)
+	if err != nil {
+		return backoutPolicy{}, fmt.Errorf("MQOPEN: %w", err)
+	}
+	attrs, err := h.obj.Inq([]int32{ibmmq.MQIA_BACKOUT_THRESHOLD, ibmmq.MQCA_BACKOUT_REQ_Q_NAME})
+	g.releaseHandle(h, err)
+	if err != nil {
+		return backoutPolicy{}, fmt.Errorf("MQINQ(BOTHRESH): %w", err)
+	}
+
+	policy = backoutPolicy{
+		threshold: intAttr(attrs, ibmmq.MQIA_BACKOUT_THRESHOLD),
+		requeueQ:  strings.TrimSpace(stringAttr(attrs, ibmmq.MQCA_BACKOUT_REQ_Q_NAME)),
+		loadedAt:  time.Now(),
+	}
+	g.backoutMu.Lock()
+	g.backoutPolicies[queueName] = policy
+	g.backoutMu.Unlock()
+	return policy, nil
+}
+
+func (g *Gateway) deadLetterQueue() (string, error) {
+	// The queue manager's DEADQ is read once and reused.
+	g.backoutMu.Lock()
+	dlq := g.deadLetterQ
+	g.backoutMu.Unlock()
+	if dlq != "" {
+		return dlq, nil
+	}
+
+	od := ibmmq.NewMQOD()
+	od.ObjectType = ibmmq.MQOT_Q_MGR
+	qmgrObj, err := g.QMgr.Open(od, ibmmq.MQOO_INQUIRE)
+	if err != nil {
+		return "", fmt.Errorf("MQOPEN(qmgr): %w", err)
+	}
+	defer qmgrObj.Close(0)
+
+	attrs, err := qmgrObj.Inq([]int32{ibmmq.MQCA_DEAD_LETTER_Q_NAME})
+	if err != nil {
+		return "", fmt.Errorf("MQINQ(DEADQ): %w", err)
+	}
+	dlq = strings.TrimSpace(stringAttr(attrs, ibmmq.MQCA_DEAD_LETTER_Q_NAME))
+
+	g.backoutMu.Lock()
+	g.deadLetterQ = dlq
+	g.backoutMu.Unlock()
+	return dlq, nil
+}
+
+// reached reports whether a message backed out count times has hit the
+// threshold. A threshold of 0 (BOTHRESH unset) never triggers.
+func (p backoutPolicy) reached(count int32) bool {
+	return p.threshold > 0 && count >= p.threshold
+}
+
+// poisonTarget returns where a poison message from queueName goes: BOQNAME,
+// or else the queue manager's DEADQ, in which case toDLQ is set and the
+// message needs an MQDLH.
+func (g *Gateway) poisonTarget(queueName string, policy backoutPolicy) (target string, toDLQ bool, err error) {
+	if policy.requeueQ != "" {
+		return policy.requeueQ, false, nil
+	}
+	target, err = g.deadLetterQueue()
+	if err != nil {
+		return "", false, err
+	}
+	if target == "" {
+		return "", false, fmt.Errorf("backout threshold reached on %s but neither BOQNAME nor DEADQ is set", queueName)
+	}
+	return target, true, nil
+}
+
+// withDeadLetterHeader prepends an MQDLH naming queueName on qMgrName as the
+// original destination. NewMQDLH copies format/encoding from md and rewrites
+// md to describe the DLH, so the payload's format is chained behind it.
+func withDeadLetterHeader(md *ibmmq.MQMD, payload []byte, queueName, qMgrName string) []byte {
+	dlh := ibmmq.NewMQDLH(md)
+	dlh.Reason = ibmmq.MQRC_BACKOUT_THRESHOLD_REACHED
+	dlh.DestQName = queueName
+	dlh.DestQMgrName = qMgrName
+	return append(dlh.Bytes(), payload...)
+}
+
+// syncpointPut puts data to queueName inside the caller's unit of work.
+type syncpointPut func(queueName string, md *ibmmq.MQMD, data []byte) error
+
+// sharedPut is the syncpointPut of callers holding syncMu on the shared
+// connection.
+func (g *Gateway) sharedPut(queueName string, md *ibmmq.MQMD, data []byte) error {
+	h, err := g.acquireHandle(queueName, ibmmq.MQOO_OUTPUT)
+	if err != nil {
+		return fmt.Errorf("MQOPEN(%s): %w", queueName, err)
+	}
+	pmo := ibmmq.NewMQPMO()
+	pmo.Options = ibmmq.MQPMO_SYNCPOINT | ibmmq.MQPMO_FAIL_IF_QUIESCING
+	err = h.obj.Put(md, pmo, data)
+	g.releaseHandle(h, err)
+	if err != nil {
+		return fmt.Errorf("MQPUT(%s): %w", queueName, err)
+	}
+	return nil
+}
+
+// screenPoison checks a message received under syncpoint against its queue's
+// backout threshold. A message that has reached BOTHRESH is moved with put, in
+// the same unit of work, to BOQNAME or else to the dead-letter queue behind an
+// MQDLH, and true is returned so the caller skips it. The caller still owns
+// the commit.
+func (g *Gateway) screenPoison(queueName string, md *ibmmq.MQMD, payload []byte, put syncpointPut) (bool, error) {
+	if md.BackoutCount == 0 {
+		return false, nil
+	}
+	policy, err := g.backoutPolicy(queueName)
+	if err != nil {
+		return false, err
+	}
+	if !policy.reached(md.BackoutCount) {
+		return false, nil
+	}
+
+	backoutCount := md.BackoutCount
+	target, toDLQ, err := g.poisonTarget(queueName, policy)
+	if err != nil {
+		g.countBackout(func(s *BackoutStats) { s.Failures++ })
+		return false, err
+	}
+	data := payload
+	if toDLQ {
+		data = withDeadLetterHeader(md, payload, queueName, g.QMgr.Name)
+	}
+
+	if err := put(target, md, data); err != nil {
+		g.countBackout(func(s *BackoutStats) { s.Failures++ })
+		return false, err
+	}
+
+	if toDLQ {
+		g.countBackout(func(s *BackoutStats) { s.MovedToDLQ++ })
+	} else {
+		g.countBackout(func(s *BackoutStats) { s.MovedToBackoutQ++ })
+	}
+	slog.Warn("[mqcore] poison message moved",
+		"queue", queueName,
+		"target", target,
+		"dead_letter", toDLQ,
+		"backout_count", backoutCount,
+		"threshold", policy.threshold,
+		"msg_id", FormatID(md.MsgId),
+		"id", "9b701656-733f-477a-9b73-0fd13aae5aa8")
+	return true, nil
+}
+
+func (g *Gateway) countBackout(update func(*BackoutStats)) {
+	g.backoutMu.Lock()
+	update(&g.backoutStats)
+	g.backoutMu.Unlock()
+}
@@ -0,0 +1,195 @@
+package mqcore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// MaxBatchSize caps how many messages a single batch call may move.
+const MaxBatchSize = 1000
+
+// ErrRolledBack marks batch entries undone by a syncpoint backout.
+var ErrRolledBack = errors.New("rolled back")
+
+// BatchResult is the outcome of one message in a batch operation.
+type BatchResult struct {
+	// MsgID is the MQMD MsgId of the message put or received.
+	MsgID []byte
+	// Message is set for successful batch gets.
+	Message *Message
+	// Err is set when this entry failed or was rolled back.
+	Err error
+}
+
+// PutBatch opens the queue once and puts every message in order.
+// With syncpoint set the batch is all-or-nothing: the first failure backs out
+// every earlier put and the whole batch is reported as failed. A payload
+// rejected by the validator fails a syncpoint batch before anything is put,
+// and only its own entry otherwise.
+func (g *Gateway) PutBatch(queueName string, messages []string, syncpoint bool) ([]BatchResult, error) {
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("messages required")
+	}
+	if len(messages) > MaxBatchSize {
+		return nil, fmt.Errorf("batch exceeds %d messages", MaxBatchSize)
+	}
+	results := make([]BatchResult, len(messages))
+	for i, message := range messages {
+		if err := g.validate(queueName, message); err != nil {
+			if syncpoint {
+				return nil, fmt.Errorf("message %d: %w", i+1, err)
+			}
+			results[i].Err = err
+		}
+	}
+
+	od := ibmmq.NewMQOD()
+	od.ObjectType = ibmmq.MQOT_Q
+	od.ObjectName = queueName
+
+	if syncpoint {
+		g.syncMu.Lock()
+		defer g.syncMu.Unlock()
+	}
+
+	qObj, err := g.QMgr.Open(od, ibmmq.MQOO_OUTPUT)
+	if err != nil {
+		return nil, fmt.Errorf("MQOPEN: %w", err)
+	}
+	defer qObj.Close(0)
+
+	for i, message := range messages {
+		if results[i].Err != nil {
+			continue
+		}
+		md := ibmmq.NewMQMD()
+		pmo := ibmmq.NewMQPMO()
+		pmo.Options = ibmmq.MQPMO_NO_SYNCPOINT | ibmmq.MQPMO_FAIL_IF_QUIESCING
+		if syncpoint {
+			pmo.Options = ibmmq.MQPMO_SYNCPOINT | ibmmq.MQPMO_FAIL_IF_QUIESCING
+		}
+
+		if err := qObj.Put(md, pmo, []byte(message)); err != nil {
+			results[i].Err = fmt.Errorf("MQPUT: %w", err)
+			if syncpoint {
+				_ = g.QMgr.Back()
+				markRolledBack(results[:i])
+				markRolledBack(results[i+1:])
+				return results, fmt.Errorf("batch put backed out at message %d: %w", i+1, err)
+			}
+			continue
+		}
+		results[i].MsgID = md.MsgId
+	}
+
+	if syncpoint {
+		if err := g.QMgr.Cmit(); err != nil {
+			markRolledBack(results)
+			return results, fmt.Errorf("MQCMIT: %w", err)
+		}
+	}
+	return results, nil
+}
+
+// GetBatch opens the queue once and receives up to maxMessages messages.
+// Only the first get waits; the batch ends early once the queue is empty.
+// With syncpoint set a failure backs out every message already received, and
+// messages at their queue's backout threshold are moved aside instead of returned.
+func (g *Gateway) GetBatch(queueName string, maxMessages int, opts GetOptions, syncpoint bool) ([]BatchResult, error) {
+	if maxMessages <= 0 {
+		return nil, fmt.Errorf("max_messages must be positive")
+	}
+	if maxMessages > MaxBatchSize {
+		return nil, fmt.Errorf("batch exceeds %d messages", MaxBatchSize)
+	}
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = 64 * 1024
+	}
+
+	od := ibmmq.NewMQOD()
+	od.ObjectType = ibmmq.MQOT_Q
+	od.ObjectName = queueName
+
+	if syncpoint {
+		g.syncMu.Lock()
+		defer g.syncMu.Unlock()
+	}
+
+	qObj, err := g.QMgr.Open(od, ibmmq.MQOO_INPUT_AS_Q_DEF)
+	if err != nil {
+		return nil, fmt.Errorf("MQOPEN: %w", err)
+	}
+	defer qObj.Close(0)
+
+	var results []BatchResult
+	for len(results) < maxMessages {
+		md := ibmmq.NewMQMD()
+		md.Version = ibmmq.MQMD_VERSION_2
+		gmo := ibmmq.NewMQGMO()
+		gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_CONVERT | ibmmq.MQGMO_NO_SYNCPOINT
+		if syncpoint {
+			gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_CONVERT | ibmmq.MQGMO_SYNCPOINT
+		}
+
+		if opts.WaitMs > 0 && len(results) == 0 {
+			gmo.Options |= ibmmq.MQGMO_WAIT
+			gmo.WaitInterval = int32(opts.WaitMs)
+		} else {
+			gmo.Options |= ibmmq.MQGMO_NO_WAIT
+		}
+
+		buf := make([]byte, maxBytes)
+		msgLen, err := qObj.Get(md, gmo, buf)
+		if err != nil {
+			if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
+				break
+			}
+			if syncpoint {
+				_ = g.QMgr.Back()
+				markRolledBack(results)
+				return results, fmt.Errorf("batch get backed out after %d messages: %w", len(results), err)
+			}
+			// Without syncpoint the messages already received are gone, so report them.
+			results = append(results, BatchResult{Err: fmt.Errorf("MQGET: %w", err)})
+			return results, nil
+		}
+		if syncpoint {
+			// Poison messages are moved aside inside this unit of work and skipped.
+			moved, err := g.screenPoison(queueName, md, buf[:msgLen], g.sharedPut)
+			if err != nil {
+				_ = g.QMgr.Back()
+				markRolledBack(results)
+				return results, fmt.Errorf("batch get backed out after %d messages: %w", len(results), err)
+			}
+			if moved {
+				continue
+			}
+		}
+		results = append(results, BatchResult{
+			MsgID:   md.MsgId,
+			Message: newMessage(md, buf[:msgLen]),
+		})
+	}
+
+	if syncpoint {
+		// Commit even an empty batch so poison moves are not left pending.
+		if err := g.QMgr.Cmit(); err != nil {
+			markRolledBack(results)
+			return results, fmt.Errorf("MQCMIT: %w", err)
+		}
+	}
+	return results, nil
+}
+
+func markRolledBack(results []BatchResult) {
+	// Entries that were not already failed are undone by the backout.
+	for i := range results {
+		if results[i].Err == nil {
+			results[i].Err = ErrRolledBack
+			results[i].Message = nil
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package mqcore
+
+import (
+	"fmt"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// Consumer hands out one message at a time and removes it only once the
+// caller acknowledges it. The message is browsed with MQGMO_LOCK, which hides
+// it from other consumers. The lock belongs to the queue handle rather than
+// to a unit of work, so a consumer needs no syncpoint. It has its own
+// connection, as the shared one would answer MQRC_CALL_IN_PROGRESS to every
+// other call while Next waits. Closing the consumer releases an
+// unacknowledged message.
+type Consumer struct {
+	qMgr     ibmmq.MQQueueManager
+	qObj     ibmmq.MQObject
+	maxBytes int
+	locked   bool
+}
+
+// OpenConsumer connects to the queue manager and opens queueName for
+// acknowledged consumption.
+func (g *Gateway) OpenConsumer(queueName string, maxBytes int) (*Consumer, error) {
+	if maxBytes <= 0 {
+		maxBytes = 64 * 1024
+	}
+	qMgr, qObj, err := g.openDedicated(queueName, ibmmq.MQOO_BROWSE|ibmmq.MQOO_INPUT_AS_Q_DEF|ibmmq.MQOO_FAIL_IF_QUIESCING)
+	if err != nil {
+		return nil, err
+	}
+	return &Consumer{qMgr: qMgr, qObj: qObj, maxBytes: maxBytes}, nil
+}
+
+// openDedicated opens queueName on a connection of its own.
+func (g *Gateway) openDedicated(queueName string, openOptions int32) (ibmmq.MQQueueManager, ibmmq.MQObject, error) {
+	qMgr, err := g.connect()
+	if err != nil {
+		return ibmmq.MQQueueManager{}, ibmmq.MQObject{}, fmt.Errorf("MQCONNX: %w", err)
+	}
+	od := ibmmq.NewMQOD()
+	od.ObjectType = ibmmq.MQOT_Q
+	od.ObjectName = queueName
+	qObj, err := qMgr.Open(od, openOptions)
+	if err != nil {
+		_ = qMgr.Disc()
+		return ibmmq.MQQueueManager{}, ibmmq.MQObject{}, fmt.Errorf("MQOPEN(%s): %w", queueName, err)
+	}
+	return qMgr, qObj, nil
+}
+
+// Next locks and returns the first available message, waiting up to waitMs.
+// It returns nil when none arrived. The previous message must have been
+// acknowledged or released first.
+func (c *Consumer) Next(waitMs int) (*Message, error) {
+	if c.locked {
+		return nil, fmt.Errorf("previous message not acknowledged")
+	}
+	md := ibmmq.NewMQMD()
+	gmo := ibmmq.NewMQGMO()
+	// Browsing from the start each time redelivers released messages first.
+	gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_BROWSE_FIRST | ibmmq.MQGMO_LOCK | ibmmq.MQGMO_CONVERT
+	GetOptions{WaitMs: waitMs}.apply(md, gmo)
+
+	buf := make([]byte, c.maxBytes)
+	msgLen, err := c.qObj.Get(md, gmo, buf)
+	if err != nil {
+		if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("MQGET(BROWSE_FIRST+LOCK): %w", err)
+	}
+	c.locked = true
+	return newMessage(md, buf[:msgLen]), nil
+}
+
+// Ack removes the locked message from the queue.
+func (c *Consumer) Ack() error {
+	if !c.locked {
+		return fmt.Errorf("no message to acknowledge")
+	}
+	md := ibmmq.NewMQMD()
+	gmo := ibmmq.NewMQGMO()
+	gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_NO_WAIT | ibmmq.MQGMO_NO_SYNCPOINT | ibmmq.MQGMO_MSG_UNDER_CURSOR
+	if _, err := c.qObj.Get(md, gmo, make([]byte, c.maxBytes)); err != nil {
+		return fmt.Errorf("MQGET(UNDER_CURSOR): %w", err)
+	}
+	c.locked = false
+	return nil
+}
+
+// Release unlocks the message, leaving it on the queue for redelivery.
+func (c *Consumer) Release() error {
+	if !c.locked {
+		return nil
+	}
+	md := ibmmq.NewMQMD()
+	gmo := ibmmq.NewMQGMO()
+	gmo.Options = ibmmq.MQGMO_UNLOCK
+	if _, err := c.qObj.Get(md, gmo, nil); err != nil {
+		return fmt.Errorf("MQGET(UNLOCK): %w", err)
+	}
+	c.locked = false
+	return nil
+}
+
+// Close releases any locked message, closes the queue handle and disconnects.
+func (c *Consumer) Close() error {
+	err := c.qObj.Close(0)
+	_ = c.qMgr.Disc()
+	return err
+}
+
+// Browser follows a browse cursor through a queue on its own connection, for
+// the same reason as Consumer. Unlike a browse_id it does not expire while
+// the queue stays empty.
+type Browser struct {
+	qMgr     ibmmq.MQQueueManager
+	qObj     ibmmq.MQObject
+	maxBytes int
+	// started is set once the cursor is on a message; until then Next
+	// browses from the start of the queue.
+	started bool
+}
+
+// OpenBrowser connects to the queue manager and opens queueName for browsing.
+func (g *Gateway) OpenBrowser(queueName string, maxBytes int) (*Browser, error) {
+	if maxBytes <= 0 {
+		maxBytes = 64 * 1024
+	}
+	qMgr, qObj, err := g.openDedicated(queueName, ibmmq.MQOO_BROWSE|ibmmq.MQOO_FAIL_IF_QUIESCING)
+	if err != nil {
+		return nil, err
+	}
+	return &Browser{qMgr: qMgr, qObj: qObj, maxBytes: maxBytes}, nil
+}
+
+// Next moves the cursor to the next message matching opts and returns it,
+// or nil when none arrived within opts.WaitMs. The first message found is
+// browsed from the start of the queue, so a first call matching a MsgID
+// positions the cursor on that message.
+func (b *Browser) Next(opts GetOptions) (*Message, error) {
+	md := ibmmq.NewMQMD()
+	gmo := ibmmq.NewMQGMO()
+	gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_BROWSE_NEXT | ibmmq.MQGMO_CONVERT
+	if !b.started {
+		gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_BROWSE_FIRST | ibmmq.MQGMO_CONVERT
+	}
+	opts.apply(md, gmo)
+
+	buf := make([]byte, b.maxBytes)
+	msgLen, err := b.qObj.Get(md, gmo, buf)
+	if err != nil {
+		if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("MQGET(BROWSE): %w", err)
+	}
+	b.started = true
+	return newMessage(md, buf[:msgLen]), nil
+}
+
+// Close closes the cursor and disconnects.
+func (b *Browser) Close() error {
+	err := b.qObj.Close(0)
+	_ = b.qMgr.Disc()
+	return err
+}
@@ -0,0 +1,314 @@
+package mqcore
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// DeadLetter is a dead-letter queue message with its MQDLH decoded.
+type DeadLetter struct {
+	MsgID []byte
+	// Reason is the MQRC/MQFB code recorded when the message was dead-lettered.
+	Reason     int32
+	ReasonText string
+	// DestQName and DestQMgrName are the original destination.
+	DestQName    string
+	DestQMgrName string
+	// Format is the format of the payload behind the DLH.
+	Format      string
+	PutApplName string
+	// PutTime is when the message was put to the dead-letter queue.
+	PutTime time.Time
+	Payload string
+}
+
+// DLQFilter selects dead letters by reason code and/or original destination.
+// Empty fields match everything.
+type DLQFilter struct {
+	Reasons   []int32
+	DestQueue string
+}
+
+func (f DLQFilter) matches(d *DeadLetter) bool {
+	if f.DestQueue != "" && !strings.EqualFold(f.DestQueue, d.DestQName) {
+		return false
+	}
+	if len(f.Reasons) == 0 {
+		return true
+	}
+	for _, r := range f.Reasons {
+		if r == d.Reason {
+			return true
+		}
+	}
+	return false
+}
+
+// ReplayOptions controls ReplayDLQ.
+type ReplayOptions struct {
+	// DLQ is the dead-letter queue to read; empty uses the queue manager's DEADQ.
+	DLQ    string
+	Filter DLQFilter
+	// Destination overrides the DLH destination for every replayed message.
+	Destination string
+	// MaxMessages caps how many matching messages are replayed (or previewed).
+	MaxMessages int
+	MaxBytes    int
+	// DryRun only reports what would be replayed.
+	DryRun bool
+	// Authorize, when set, vets each target queue and the remote queue
+	// manager it is routed to ("" for this one); a non-nil error leaves that
+	// message on the dead-letter queue and is reported in its result.
+	Authorize func(target, targetQMgr string) error
+}
+
+// ReplayResult is the outcome for one dead letter.
+type ReplayResult struct {
+	DeadLetter
+	// Target is the queue the message was (or would be) put to, on
+	// TargetQMgr when that is another queue manager.
+	Target     string
+	TargetQMgr string
+	Replayed   bool
+	Err        error
+}
+
+// parseDeadLetter splits a DLQ message into its decoded DLH and payload.
+func parseDeadLetter(md *ibmmq.MQMD, buf []byte) (*DeadLetter, *ibmmq.MQDLH, []byte, error) {
+	if strings.TrimSpace(md.Format) != strings.TrimSpace(ibmmq.MQFMT_DEAD_LETTER_HEADER) {
+		return nil, nil, nil, fmt.Errorf("message has no MQDLH (format %q)", md.Format)
+	}
+	hdr, hdrLen, err := ibmmq.GetHeader(md, buf)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("decode MQDLH: %w", err)
+	}
+	dlh := hdr.(*ibmmq.MQDLH)
+	payload := buf[hdrLen:]
+
+	return &DeadLetter{
+		MsgID:        md.MsgId,
+		Reason:       dlh.Reason,
+		ReasonText:   ibmmq.MQItoString("RC", int(dlh.Reason)),
+		DestQName:    strings.TrimSpace(dlh.DestQName),
+		DestQMgrName: strings.TrimSpace(dlh.DestQMgrName),
+		Format:       strings.TrimSpace(dlh.Format),
+		PutApplName:  strings.TrimSpace(dlh.PutApplName),
+		PutTime:      dlh.PutDateTime,
+		Payload:      string(payload),
+	}, dlh, payload, nil
+}
+
+// ResolveDLQ returns dlq, or the queue manager's DEADQ when dlq is empty.
+func (g *Gateway) ResolveDLQ(dlq string) (string, error) {
+	if dlq != "" {
+		return dlq, nil
+	}
+	dlq, err := g.deadLetterQueue()
+	if err != nil {
+		return "", err
+	}
+	if dlq == "" {
+		return "", fmt.Errorf("queue manager has no DEADQ; pass the queue explicitly")
+	}
+	return dlq, nil
+}
+
+// BrowseDLQ browses up to maxMessages dead letters matching filter without removing them.
+func (g *Gateway) BrowseDLQ(dlq string, filter DLQFilter, maxMessages int, maxBytes int) ([]DeadLetter, error) {
+	if maxMessages <= 0 || maxMessages > MaxBatchSize {
+		maxMessages = MaxBatchSize
+	}
+	if maxBytes <= 0 {
+		maxBytes = 64 * 1024
+	}
+	dlq, err := g.ResolveDLQ(dlq)
+	if err != nil {
+		return nil, err
+	}
+
+	od := ibmmq.NewMQOD()
+	od.ObjectType = ibmmq.MQOT_Q
+	od.ObjectName = dlq
+	qObj, err := g.QMgr.Open(od, ibmmq.MQOO_BROWSE)
+	if err != nil {
+		return nil, fmt.Errorf("MQOPEN: %w", err)
+	}
+	defer qObj.Close(0)
+
+	var out []DeadLetter
+	browse := ibmmq.MQGMO_BROWSE_FIRST
+	for len(out) < maxMessages {
+		md := ibmmq.NewMQMD()
+		gmo := ibmmq.NewMQGMO()
+		gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_NO_WAIT | ibmmq.MQGMO_CONVERT | browse
+		browse = ibmmq.MQGMO_BROWSE_NEXT
+
+		buf := make([]byte, maxBytes)
+		msgLen, err := qObj.Get(md, gmo, buf)
+		if err != nil {
+			if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
+				break
+			}
+			return out, fmt.Errorf("MQGET(BROWSE): %w", err)
+		}
+		dl, _, _, err := parseDeadLetter(md, buf[:msgLen])
+		if err != nil {
+			// Messages without a DLH are left alone and skipped.
+			continue
+		}
+		if filter.matches(dl) {
+			out = append(out, *dl)
+		}
+	}
+	return out, nil
+}
+
+// ReplayDLQ strips the MQDLH from matching dead letters and puts them back to their
+// original destination (or opts.Destination). Each message is moved in its own unit
+// of work, so a failed put leaves that message on the dead-letter queue.
+func (g *Gateway) ReplayDLQ(opts ReplayOptions) ([]ReplayResult, error) {
+	if opts.MaxMessages <= 0 || opts.MaxMessages > MaxBatchSize {
+		opts.MaxMessages = MaxBatchSize
+	}
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = 64 * 1024
+	}
+	dlq, err := g.ResolveDLQ(opts.DLQ)
+	if err != nil {
+		return nil, err
+	}
+
+	od := ibmmq.NewMQOD()
+	od.ObjectType = ibmmq.MQOT_Q
+	od.ObjectName = dlq
+	openOpts := ibmmq.MQOO_BROWSE
+	if !opts.DryRun {
+		openOpts |= ibmmq.MQOO_INPUT_AS_Q_DEF
+	}
+	qObj, err := g.QMgr.Open(od, openOpts)
+	if err != nil {
+		return nil, fmt.Errorf("MQOPEN: %w", err)
+	}
+	defer qObj.Close(0)
+
+	if !opts.DryRun {
+		g.syncMu.Lock()
+		defer g.syncMu.Unlock()
+	}
+
+	// Destination handles are opened once per replay call.
+	targets := make(map[string]ibmmq.MQObject)
+	defer func() {
+		for _, t := range targets {
+			_ = t.Close(0)
+		}
+	}()
+
+	var results []ReplayResult
+	browse := ibmmq.MQGMO_BROWSE_FIRST
+	for len(results) < opts.MaxMessages {
+		// Browse without conversion so the replayed bytes are untouched.
+		md := ibmmq.NewMQMD()
+		gmo := ibmmq.NewMQGMO()
+		gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_NO_WAIT | browse
+		browse = ibmmq.MQGMO_BROWSE_NEXT
+
+		buf := make([]byte, maxBytes)
+		msgLen, err := qObj.Get(md, gmo, buf)
+		if err != nil {
+			if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
+				break
+			}
+			return results, fmt.Errorf("MQGET(BROWSE): %w", err)
+		}
+		dl, dlh, payload, err := parseDeadLetter(md, buf[:msgLen])
+		if err != nil || !opts.Filter.matches(dl) {
+			continue
+		}
+
+		res := ReplayResult{DeadLetter: *dl, Target: dl.DestQName}
+		if opts.Destination != "" {
+			res.Target = opts.Destination
+		}
+		res.TargetQMgr = g.replayQMgr(dl.DestQMgrName, opts.Destination != "")
+		if res.Target == "" {
+			res.Err = fmt.Errorf("dead letter has no destination queue")
+			results = append(results, res)
+			continue
+		}
+		if opts.Authorize != nil {
+			if res.Err = opts.Authorize(res.Target, res.TargetQMgr); res.Err != nil {
+				results = append(results, res)
+				continue
+			}
+		}
+		if opts.DryRun {
+			results = append(results, res)
+			continue
+		}
+
+		res.Err = g.replayOne(qObj, targets, res.Target, res.TargetQMgr, md, dlh, payload, maxBytes)
+		res.Replayed = res.Err == nil
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// replayQMgr returns the remote queue manager a dead letter is replayed to,
+// or "" when it stays on this one. An overridden destination is always local.
+func (g *Gateway) replayQMgr(destQMgr string, overridden bool) string {
+	destQMgr = strings.TrimSpace(destQMgr)
+	if overridden || destQMgr == strings.TrimSpace(g.QMgr.Name) {
+		return ""
+	}
+	return destQMgr
+}
+
+func (g *Gateway) replayOne(dlqObj ibmmq.MQObject, targets map[string]ibmmq.MQObject, target, targetQMgr string,
+	md *ibmmq.MQMD, dlh *ibmmq.MQDLH, payload []byte, maxBytes int) error {
+	// Remove the browsed message under syncpoint so the put and the get commit together.
+	getMD := ibmmq.NewMQMD()
+	gmo := ibmmq.NewMQGMO()
+	gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_NO_WAIT | ibmmq.MQGMO_SYNCPOINT | ibmmq.MQGMO_MSG_UNDER_CURSOR
+	if _, err := dlqObj.Get(getMD, gmo, make([]byte, maxBytes)); err != nil {
+		return fmt.Errorf("MQGET(UNDER_CURSOR): %w", err)
+	}
+
+	key := target + "@" + targetQMgr
+	tObj, ok := targets[key]
+	if !ok {
+		od := ibmmq.NewMQOD()
+		od.ObjectType = ibmmq.MQOT_Q
+		od.ObjectName = target
+		od.ObjectQMgrName = targetQMgr
+		var err error
+		tObj, err = g.QMgr.Open(od, ibmmq.MQOO_OUTPUT)
+		if err != nil {
+			_ = g.QMgr.Back()
+			return fmt.Errorf("MQOPEN(%s): %w", target, err)
+		}
+		targets[key] = tObj
+	}
+
+	// Restore the payload description the DLH saved from the original MQMD.
+	// An inherited CCSID means the payload already matches the DLQ message's MQMD.
+	md.Format = dlh.Format
+	md.Encoding = dlh.Encoding
+	if dlh.CodedCharSetId != ibmmq.MQCCSI_INHERIT {
+		md.CodedCharSetId = dlh.CodedCharSetId
+	}
+	pmo := ibmmq.NewMQPMO()
+	pmo.Options = ibmmq.MQPMO_SYNCPOINT | ibmmq.MQPMO_FAIL_IF_QUIESCING
+	if err := tObj.Put(md, pmo, payload); err != nil {
+		_ = g.QMgr.Back()
+		return fmt.Errorf("MQPUT(%s): %w", target, err)
+	}
+	if err := g.QMgr.Cmit(); err != nil {
+		return fmt.Errorf("MQCMIT: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,341 @@
+package mqcore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// exportCommitEvery bounds the unit of work of a destructive export or an import.
+const exportCommitEvery = 100
+
+// ExportOptions controls ExportQueue.
+type ExportOptions struct {
+	// Destructive removes each exported message from the queue. Messages are
+	// got under syncpoint and committed only after they have been written.
+	Destructive bool
+	// MaxMessages caps the export; 0 exports every message.
+	MaxMessages int
+	// MaxBytes is the largest message accepted (default 4 MiB).
+	MaxBytes int
+}
+
+// ImportOptions controls ImportQueue.
+type ImportOptions struct {
+	// SetAllContext restores the archived identity and origin context
+	// (MQPMO_SET_ALL_CONTEXT). The gateway's user needs setall authority.
+	SetAllContext bool
+	// NewMsgID gives every message a fresh MsgId instead of the archived one.
+	NewMsgID bool
+}
+
+// ExportQueue writes the messages on queueName to w, with their MQMD and
+// properties and without data conversion. Without opts.Destructive the queue
+// is browsed and left unchanged. It returns how many messages were exported
+// (for a destructive export, removed); the caller closes w. A destructive
+// export runs on a connection of its own, so a slow reader of w never holds
+// up the units of work on the gateway's shared one.
+func (g *Gateway) ExportQueue(queueName string, w ArchiveWriter, opts ExportOptions) (int, error) {
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = 4 << 20
+	}
+
+	qMgr := g.QMgr
+	if opts.Destructive {
+		var err error
+		if qMgr, err = g.connect(); err != nil {
+			return 0, fmt.Errorf("MQCONNX: %w", err)
+		}
+		defer qMgr.Disc()
+	}
+
+	od := ibmmq.NewMQOD()
+	od.ObjectType = ibmmq.MQOT_Q
+	od.ObjectName = queueName
+	openOpts := ibmmq.MQOO_BROWSE
+	if opts.Destructive {
+		openOpts = ibmmq.MQOO_INPUT_AS_Q_DEF
+	}
+	qObj, err := qMgr.Open(od, openOpts)
+	if err != nil {
+		return 0, fmt.Errorf("MQOPEN: %w", err)
+	}
+	defer qObj.Close(0)
+
+	mh, err := qMgr.CrtMH(ibmmq.NewMQCMHO())
+	if err != nil {
+		return 0, fmt.Errorf("MQCRTMH: %w", err)
+	}
+	defer mh.DltMH(ibmmq.NewMQDMHO())
+
+	n, pending := 0, 0
+	browse := ibmmq.MQGMO_BROWSE_FIRST
+	for opts.MaxMessages <= 0 || n < opts.MaxMessages {
+		md := ibmmq.NewMQMD()
+		md.Version = ibmmq.MQMD_VERSION_2
+		gmo := ibmmq.NewMQGMO()
+		gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_NO_WAIT | ibmmq.MQGMO_PROPERTIES_IN_HANDLE
+		if opts.Destructive {
+			gmo.Options |= ibmmq.MQGMO_SYNCPOINT
+		} else {
+			gmo.Options |= browse
+			browse = ibmmq.MQGMO_BROWSE_NEXT
+		}
+		gmo.MsgHandle = mh
+
+		buf := make([]byte, maxBytes)
+		msgLen, err := qObj.Get(md, gmo, buf)
+		if err != nil {
+			if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
+				break
+			}
+			return abortExport(qMgr, opts.Destructive, n, pending, fmt.Errorf("MQGET: %w", err))
+		}
+
+		m, err := archivedMessage(md, mh, buf[:msgLen])
+		if err == nil {
+			err = w.WriteMessage(m)
+		}
+		if err != nil {
+			return abortExport(qMgr, opts.Destructive, n, pending, err)
+		}
+		n++
+		if !opts.Destructive {
+			continue
+		}
+
+		if pending++; pending == exportCommitEvery {
+			if err := qMgr.Cmit(); err != nil {
+				return n - pending, fmt.Errorf("MQCMIT: %w", err)
+			}
+			pending = 0
+		}
+	}
+
+	if pending > 0 {
+		if err := qMgr.Cmit(); err != nil {
+			return n - pending, fmt.Errorf("MQCMIT: %w", err)
+		}
+	}
+	return n, nil
+}
+
+// abortExport backs out messages got since the last commit, so a failed
+// destructive export only removes the batches it finished, and returns that count.
+func abortExport(qMgr ibmmq.MQQueueManager, destructive bool, n, pending int, err error) (int, error) {
+	if destructive {
+		_ = qMgr.Back()
+		n -= pending
+	}
+	return n, err
+}
+
+func archivedMessage(md *ibmmq.MQMD, mh ibmmq.MQMessageHandle, payload []byte) (*ArchivedMessage, error) {
+	m := &ArchivedMessage{
+		MsgID:            FormatID(md.MsgId),
+		CorrelID:         FormatID(md.CorrelId),
+		GroupID:          FormatID(md.GroupId),
+		MsgSeqNumber:     md.MsgSeqNumber,
+		Offset:           md.Offset,
+		MsgFlags:         md.MsgFlags,
+		Format:           strings.TrimSpace(md.Format),
+		Encoding:         md.Encoding,
+		CodedCharSetID:   md.CodedCharSetId,
+		MsgType:          md.MsgType,
+		Persistence:      md.Persistence,
+		Priority:         md.Priority,
+		Expiry:           md.Expiry,
+		Report:           md.Report,
+		Feedback:         md.Feedback,
+		ReplyToQ:         strings.TrimSpace(md.ReplyToQ),
+		ReplyToQMgr:      strings.TrimSpace(md.ReplyToQMgr),
+		BackoutCount:     md.BackoutCount,
+		UserIdentifier:   strings.TrimSpace(md.UserIdentifier),
+		AccountingToken:  FormatID(md.AccountingToken),
+		ApplIdentityData: strings.TrimSpace(md.ApplIdentityData),
+		PutApplType:      md.PutApplType,
+		PutApplName:      strings.TrimSpace(md.PutApplName),
+		PutDateTime:      md.PutDateTime,
+		ApplOriginData:   strings.TrimSpace(md.ApplOriginData),
+		Payload:          payload,
+	}
+
+	impo := ibmmq.NewMQIMPO()
+	pd := ibmmq.NewMQPD()
+	impo.Options = ibmmq.MQIMPO_INQ_FIRST
+	for {
+		name, value, err := mh.InqMP(impo, pd, "%")
+		impo.Options = ibmmq.MQIMPO_INQ_NEXT
+		if err != nil {
+			if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_PROPERTY_NOT_AVAILABLE {
+				return m, nil
+			}
+			return nil, fmt.Errorf("MQINQMP: %w", err)
+		}
+		p, err := newProperty(name, value)
+		if err != nil {
+			return nil, err
+		}
+		m.Properties = append(m.Properties, p)
+	}
+}
+
+// ImportQueue puts every message read from r to queueName and returns how many
+// were committed. Messages are put in units of work of up to 100; on error the
+// current unit is backed out. Like a destructive export, the import runs on a
+// connection of its own while it waits on r.
+func (g *Gateway) ImportQueue(queueName string, r ArchiveReader, opts ImportOptions) (int, error) {
+	qMgr, err := g.connect()
+	if err != nil {
+		return 0, fmt.Errorf("MQCONNX: %w", err)
+	}
+	defer qMgr.Disc()
+
+	od := ibmmq.NewMQOD()
+	od.ObjectType = ibmmq.MQOT_Q
+	od.ObjectName = queueName
+	openOpts := ibmmq.MQOO_OUTPUT | ibmmq.MQOO_FAIL_IF_QUIESCING
+	if opts.SetAllContext {
+		openOpts |= ibmmq.MQOO_SET_ALL_CONTEXT
+	}
+	qObj, err := qMgr.Open(od, openOpts)
+	if err != nil {
+		return 0, fmt.Errorf("MQOPEN: %w", err)
+	}
+	defer qObj.Close(0)
+
+	committed, pending := 0, 0
+	for {
+		m, err := r.ReadMessage()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err == nil {
+			err = importOne(qMgr, qObj, m, opts)
+		}
+		if err != nil {
+			_ = qMgr.Back()
+			return committed, fmt.Errorf("message %d: %w", committed+pending+1, err)
+		}
+
+		if pending++; pending == exportCommitEvery {
+			if err := qMgr.Cmit(); err != nil {
+				return committed, fmt.Errorf("MQCMIT: %w", err)
+			}
+			committed += pending
+			pending = 0
+		}
+	}
+	if pending > 0 {
+		if err := qMgr.Cmit(); err != nil {
+			return committed, fmt.Errorf("MQCMIT: %w", err)
+		}
+		committed += pending
+	}
+	return committed, nil
+}
+
+func importOne(qMgr ibmmq.MQQueueManager, qObj ibmmq.MQObject, m *ArchivedMessage, opts ImportOptions) error {
+	md, err := m.mqmd(opts)
+	if err != nil {
+		return err
+	}
+	pmo := ibmmq.NewMQPMO()
+	pmo.Options = ibmmq.MQPMO_SYNCPOINT | ibmmq.MQPMO_FAIL_IF_QUIESCING
+	if opts.SetAllContext {
+		pmo.Options |= ibmmq.MQPMO_SET_ALL_CONTEXT
+	}
+	if opts.NewMsgID {
+		pmo.Options |= ibmmq.MQPMO_NEW_MSG_ID
+	}
+
+	if len(m.Properties) > 0 {
+		mh, err := qMgr.CrtMH(ibmmq.NewMQCMHO())
+		if err != nil {
+			return fmt.Errorf("MQCRTMH: %w", err)
+		}
+		defer mh.DltMH(ibmmq.NewMQDMHO())
+		smpo := ibmmq.NewMQSMPO()
+		pd := ibmmq.NewMQPD()
+		for _, p := range m.Properties {
+			v, err := p.GoValue()
+			if err != nil {
+				return err
+			}
+			if err := mh.SetMP(smpo, p.Name, pd, v); err != nil {
+				return fmt.Errorf("MQSETMP(%s): %w", p.Name, err)
+			}
+		}
+		pmo.OriginalMsgHandle = mh
+	}
+
+	if err := qObj.Put(md, pmo, m.Payload); err != nil {
+		return fmt.Errorf("MQPUT: %w", err)
+	}
+	return nil
+}
+
+// mqmd rebuilds the MQMD for re-putting m. Context fields are only honoured
+// by the queue manager with MQPMO_SET_ALL_CONTEXT.
+func (m *ArchivedMessage) mqmd(opts ImportOptions) (*ibmmq.MQMD, error) {
+	md := ibmmq.NewMQMD()
+	md.Version = ibmmq.MQMD_VERSION_2
+	// Empty ids keep the MQMD defaults (MQMI_NONE etc.).
+	ids := []struct {
+		name  string
+		value string
+		dst   *[]byte
+	}{
+		{"msg_id", m.MsgID, &md.MsgId},
+		{"correl_id", m.CorrelID, &md.CorrelId},
+		{"group_id", m.GroupID, &md.GroupId},
+	}
+	if opts.NewMsgID {
+		ids = ids[1:]
+	}
+	for _, id := range ids {
+		b, err := ParseID(id.value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", id.name, err)
+		}
+		if b != nil {
+			*id.dst = b
+		}
+	}
+	md.MsgSeqNumber = max(m.MsgSeqNumber, 1)
+	md.Offset = m.Offset
+	md.MsgFlags = m.MsgFlags
+
+	md.Format = m.Format
+	md.Encoding = m.Encoding
+	md.CodedCharSetId = m.CodedCharSetID
+	md.MsgType = m.MsgType
+	md.Persistence = m.Persistence
+	md.Priority = m.Priority
+	md.Expiry = m.Expiry
+	md.Report = m.Report
+	md.Feedback = m.Feedback
+	md.ReplyToQ = m.ReplyToQ
+	md.ReplyToQMgr = m.ReplyToQMgr
+
+	if opts.SetAllContext {
+		md.UserIdentifier = m.UserIdentifier
+		token, err := decodeToken(m.AccountingToken, int(ibmmq.MQ_ACCOUNTING_TOKEN_LENGTH))
+		if err != nil {
+			return nil, fmt.Errorf("accounting_token: %w", err)
+		}
+		if token != nil {
+			md.AccountingToken = token
+		}
+		md.ApplIdentityData = m.ApplIdentityData
+		md.PutApplType = m.PutApplType
+		md.PutApplName = m.PutApplName
+		md.PutDateTime = m.PutDateTime
+		md.ApplOriginData = m.ApplOriginData
+	}
+	return md, nil
+}
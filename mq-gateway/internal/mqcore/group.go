@@ -0,0 +1,148 @@
+package mqcore
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// PutGroup sends messages as one ordered MQ group and returns the GroupId.
+func (g *Gateway) PutGroup(queueName string, messages []string) ([]byte, error) {
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("messages required")
+	}
+	for i, message := range messages {
+		if err := g.validate(queueName, message); err != nil {
+			return nil, fmt.Errorf("group msg %d: %w", i+1, err)
+		}
+	}
+
+	od := ibmmq.NewMQOD()
+	od.ObjectType = ibmmq.MQOT_Q
+	od.ObjectName = queueName
+
+	// The whole group is put in one unit of work so consumers never see a partial group.
+	g.syncMu.Lock()
+	defer g.syncMu.Unlock()
+
+	qObj, err := g.QMgr.Open(od, ibmmq.MQOO_OUTPUT)
+	if err != nil {
+		return nil, fmt.Errorf("MQOPEN: %w", err)
+	}
+	defer qObj.Close(0)
+
+	var groupID []byte
+	for i, message := range messages {
+		md := ibmmq.NewMQMD()
+		md.Version = ibmmq.MQMD_VERSION_2
+		md.MsgFlags = ibmmq.MQMF_MSG_IN_GROUP
+		if i == len(messages)-1 {
+			md.MsgFlags = ibmmq.MQMF_LAST_MSG_IN_GROUP
+		}
+
+		// LOGICAL_ORDER lets the queue manager assign GroupId and MsgSeqNumber.
+		pmo := ibmmq.NewMQPMO()
+		pmo.Options = ibmmq.MQPMO_SYNCPOINT | ibmmq.MQPMO_LOGICAL_ORDER | ibmmq.MQPMO_FAIL_IF_QUIESCING
+
+		if err := qObj.Put(md, pmo, []byte(message)); err != nil {
+			_ = g.QMgr.Back()
+			return nil, fmt.Errorf("MQPUT(group msg %d): %w", i+1, err)
+		}
+		groupID = md.GroupId
+	}
+
+	if err := g.QMgr.Cmit(); err != nil {
+		return nil, fmt.Errorf("MQCMIT: %w", err)
+	}
+	return groupID, nil
+}
+
+// GetGroup receives one complete logical group in sequence order.
+func (g *Gateway) GetGroup(queueName string, opts GetOptions) ([]Message, bool, error) {
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = 64 * 1024
+	}
+
+	od := ibmmq.NewMQOD()
+	od.ObjectType = ibmmq.MQOT_Q
+	od.ObjectName = queueName
+
+	// The group is removed in one unit of work so a failure leaves it intact.
+	g.syncMu.Lock()
+	defer g.syncMu.Unlock()
+
+	qObj, err := g.QMgr.Open(od, ibmmq.MQOO_INPUT_AS_Q_DEF)
+	if err != nil {
+		return nil, false, fmt.Errorf("MQOPEN: %w", err)
+	}
+	defer qObj.Close(0)
+
+	var msgs []Message
+	for {
+		md := ibmmq.NewMQMD()
+		md.Version = ibmmq.MQMD_VERSION_2
+		gmo := ibmmq.NewMQGMO()
+		gmo.Version = ibmmq.MQGMO_VERSION_2
+		gmo.MatchOptions = ibmmq.MQMO_NONE
+		gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_CONVERT | ibmmq.MQGMO_SYNCPOINT |
+			ibmmq.MQGMO_LOGICAL_ORDER | ibmmq.MQGMO_ALL_MSGS_AVAILABLE | ibmmq.MQGMO_COMPLETE_MSG
+
+		if opts.WaitMs > 0 && len(msgs) == 0 {
+			// Only the first message waits; the rest of the group is already available.
+			gmo.Options |= ibmmq.MQGMO_WAIT
+			gmo.WaitInterval = int32(opts.WaitMs)
+		} else {
+			gmo.Options |= ibmmq.MQGMO_NO_WAIT
+		}
+
+		buf := make([]byte, maxBytes)
+		msgLen, err := qObj.Get(md, gmo, buf)
+		if err != nil {
+			_ = g.QMgr.Back()
+			if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE && len(msgs) == 0 {
+				return nil, true, nil
+			}
+			return nil, false, fmt.Errorf("MQGET(group msg %d): %w", len(msgs)+1, err)
+		}
+		msgs = append(msgs, *newMessage(md, buf[:msgLen]))
+
+		if gmo.GroupStatus == ibmmq.MQGS_LAST_MSG_IN_GROUP || gmo.GroupStatus == ibmmq.MQGS_NOT_IN_GROUP {
+			break
+		}
+	}
+
+	if err := g.QMgr.Cmit(); err != nil {
+		return nil, false, fmt.Errorf("MQCMIT: %w", err)
+	}
+	return msgs, false, nil
+}
+
+// ParseID decodes a hex MsgId/CorrelId/GroupId, padding it to the 24-byte MQ length.
+// An empty string yields nil.
+func ParseID(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex id: %w", err)
+	}
+	if len(b) > int(ibmmq.MQ_MSG_ID_LENGTH) {
+		return nil, fmt.Errorf("id longer than %d bytes", ibmmq.MQ_MSG_ID_LENGTH)
+	}
+	id := make([]byte, ibmmq.MQ_MSG_ID_LENGTH)
+	copy(id, b)
+	return id, nil
+}
+
+// FormatID hex-encodes an MQ id, returning "" for the all-zero "none" value.
+func FormatID(id []byte) string {
+	for _, b := range id {
+		if b != 0 {
+			return hex.EncodeToString(id)
+		}
+	}
+	return ""
+}
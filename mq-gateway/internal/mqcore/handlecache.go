@@ -0,0 +1,204 @@
+package mqcore
+
+import (
+	"container/list"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// handleKey identifies a cached object handle.
+type handleKey struct {
+	queue   string
+	options int32
+}
+
+// cachedHandle is an open MQ object shared by concurrent callers.
+type cachedHandle struct {
+	key handleKey
+	obj ibmmq.MQObject
+	// lastUsed tracks idle time for eviction.
+	lastUsed time.Time
+	// refs counts callers currently using obj.
+	refs int
+	// closing marks a handle dropped from the cache; it is closed once refs reaches 0.
+	closing bool
+	elem    *list.Element
+}
+
+// HandleCacheStats is a snapshot of open-handle cache counters.
+type HandleCacheStats struct {
+	Size          int    `json:"size"`
+	Capacity      int    `json:"capacity"`
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Evictions     uint64 `json:"evictions"`
+	Invalidations uint64 `json:"invalidations"`
+}
+
+// handleCache is an LRU of open object handles keyed by queue name and open options.
+// Idle entries are evicted lazily on access, like browse sessions.
+type handleCache struct {
+	mu      sync.Mutex
+	max     int
+	idleTTL time.Duration
+	entries map[handleKey]*cachedHandle
+	// lru orders entries from most (front) to least (back) recently used.
+	lru   *list.List
+	stats HandleCacheStats
+}
+
+func newHandleCache(max int, idleTTL time.Duration) *handleCache {
+	return &handleCache{
+		max:     max,
+		idleTTL: idleTTL,
+		entries: make(map[handleKey]*cachedHandle),
+		lru:     list.New(),
+		stats:   HandleCacheStats{Capacity: max},
+	}
+}
+
+// acquireHandle returns an open handle for queueName/options, reusing a cached one when possible.
+// Every successful call must be paired with releaseHandle.
+func (g *Gateway) acquireHandle(queueName string, options int32) (*cachedHandle, error) {
+	c := g.handles
+	key := handleKey{queue: queueName, options: options}
+
+	c.mu.Lock()
+	c.evictIdleLocked()
+	if h := c.entries[key]; h != nil {
+		h.refs++
+		h.lastUsed = time.Now()
+		c.lru.MoveToFront(h.elem)
+		c.stats.Hits++
+		c.mu.Unlock()
+		return h, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	// Open outside the lock so a slow MQOPEN does not block other queues.
+	od := ibmmq.NewMQOD()
+	od.ObjectType = ibmmq.MQOT_Q
+	od.ObjectName = queueName
+	obj, err := g.QMgr.Open(od, options)
+	if err != nil {
+		return nil, err
+	}
+	h := &cachedHandle{key: key, obj: obj, lastUsed: time.Now(), refs: 1}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.max <= 0 {
+		// Caching disabled: the handle is closed on release.
+		h.closing = true
+		return h, nil
+	}
+	if existing := c.entries[key]; existing != nil {
+		// Another caller opened the same object meanwhile; keep theirs.
+		existing.refs++
+		existing.lastUsed = time.Now()
+		c.lru.MoveToFront(existing.elem)
+		_ = obj.Close(0)
+		return existing, nil
+	}
+	h.elem = c.lru.PushFront(h)
+	c.entries[key] = h
+	for c.lru.Len() > c.max {
+		c.removeLocked(c.lru.Back().Value.(*cachedHandle))
+		c.stats.Evictions++
+	}
+	return h, nil
+}
+
+// releaseHandle returns a handle after use. An error that leaves the handle
+// unusable drops it from the cache so the next caller reopens the object.
+func (g *Gateway) releaseHandle(h *cachedHandle, opErr error) {
+	c := g.handles
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	h.refs--
+	if isHandleInvalidating(opErr) && !h.closing {
+		slog.Warn("[mqcore] invalidating cached queue handle",
+			"queue", h.key.queue,
+			"error", opErr,
+			"id", "9a7de437-fc73-4944-b7b6-e4ca1698c3d7")
+		c.stats.Invalidations++
+		c.removeLocked(h)
+		return
+	}
+	if h.closing && h.refs <= 0 {
+		_ = h.obj.Close(0)
+	}
+}
+
+// HandleCacheStats returns a snapshot of the open-handle cache counters.
+func (g *Gateway) HandleCacheStats() HandleCacheStats {
+	c := g.handles
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Size = c.lru.Len()
+	return stats
+}
+
+func (c *handleCache) evictIdleLocked() {
+	// Walk from the least recently used end until a fresh entry is found.
+	// Handles in use are passed over, so a long wait on one does not keep
+	// the idle ones behind it open.
+	now := time.Now()
+	for e := c.lru.Back(); e != nil; {
+		h := e.Value.(*cachedHandle)
+		e = e.Prev()
+		if h.refs > 0 {
+			continue
+		}
+		if now.Sub(h.lastUsed) <= c.idleTTL {
+			break
+		}
+		c.removeLocked(h)
+		c.stats.Evictions++
+	}
+}
+
+func (c *handleCache) removeLocked(h *cachedHandle) {
+	// Unlink and close now, or defer the close until the last user releases it.
+	if h.closing {
+		return
+	}
+	h.closing = true
+	c.lru.Remove(h.elem)
+	delete(c.entries, h.key)
+	if h.refs <= 0 {
+		_ = h.obj.Close(0)
+	}
+}
+
+func (c *handleCache) closeAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, h := range c.entries {
+		c.removeLocked(h)
+	}
+}
+
+func isHandleInvalidating(err error) bool {
+	// These reason codes mean the object or connection handle can no longer be trusted.
+	var mqret *ibmmq.MQReturn
+	if !errors.As(err, &mqret) {
+		return false
+	}
+	switch mqret.MQRC {
+	case ibmmq.MQRC_OBJECT_CHANGED,
+		ibmmq.MQRC_HCONN_ERROR,
+		ibmmq.MQRC_HOBJ_ERROR,
+		ibmmq.MQRC_CONNECTION_BROKEN,
+		ibmmq.MQRC_Q_DELETED:
+		return true
+	}
+	return false
+}
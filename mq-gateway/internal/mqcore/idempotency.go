@@ -0,0 +1,184 @@
+package mqcore
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// MaxIdempotencyKeyLength bounds client-supplied idempotency keys.
+const MaxIdempotencyKeyLength = 255
+
+// ErrIdempotencyConflict reports an idempotency key that was already used
+// for a put with a different payload or options.
+var ErrIdempotencyConflict = errors.New("idempotency key was already used for a different message")
+
+// IdempotentMsgID derives the MQMD MsgId for a put by principal to queue with
+// key, so a retried put carries the same MsgId even after the gateway forgot
+// the key and consumers can deduplicate on it.
+func IdempotentMsgID(principal, queue, key string) []byte {
+	sum := sha256.Sum256([]byte(idempotencyScope(principal, queue, key)))
+	return sum[:ibmmq.MQ_MSG_ID_LENGTH]
+}
+
+// idempotencyScope names a key as used by principal on queue; keys of
+// different principals never meet.
+func idempotencyScope(principal, queue, key string) string {
+	return principal + "\x00" + queue + "\x00" + key
+}
+
+// idempotentPut is the outcome of the first put with a given key.
+type idempotentPut struct {
+	key string
+	// digest identifies the payload and options, to detect reused keys.
+	digest  [sha256.Size]byte
+	expires time.Time
+	// done is closed when the put finished; msgID and err are set before.
+	done  chan struct{}
+	msgID []byte
+	err   error
+	elem  *list.Element
+}
+
+// idempotencyStore remembers recent idempotent puts, bounded in number and
+// age. Expired entries are dropped lazily, like handle cache entries.
+type idempotencyStore struct {
+	mu      sync.Mutex
+	max     int
+	ttl     time.Duration
+	entries map[string]*idempotentPut
+	// order holds entries from newest (front) to oldest (back).
+	order *list.List
+}
+
+func newIdempotencyStore(max int, ttl time.Duration) *idempotencyStore {
+	return &idempotencyStore{
+		max:     max,
+		ttl:     ttl,
+		entries: make(map[string]*idempotentPut),
+		order:   list.New(),
+	}
+}
+
+// begin returns the entry for key and whether the caller owns it and must
+// put. A caller that does not own it waits on done.
+func (s *idempotencyStore) begin(key string, digest [sha256.Size]byte, now time.Time) (*idempotentPut, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Puts still in flight are passed over, so a slow one does not keep
+	// the finished entries behind it.
+	for e := s.order.Back(); e != nil; {
+		p := e.Value.(*idempotentPut)
+		e = e.Prev()
+		if !p.finished() {
+			continue
+		}
+		if now.Before(p.expires) && len(s.entries) < s.max {
+			break
+		}
+		s.remove(p)
+	}
+
+	if p, ok := s.entries[key]; ok {
+		if p.digest != digest {
+			return nil, false, ErrIdempotencyConflict
+		}
+		return p, false, nil
+	}
+	p := &idempotentPut{key: key, digest: digest, done: make(chan struct{})}
+	p.elem = s.order.PushFront(p)
+	s.entries[key] = p
+	return p, true, nil
+}
+
+// finish records the outcome of p. Failed puts are forgotten so a retry
+// puts again.
+func (s *idempotencyStore) finish(p *idempotentPut, msgID []byte, err error, now time.Time) {
+	s.mu.Lock()
+	p.msgID, p.err = msgID, err
+	p.expires = now.Add(s.ttl)
+	if err != nil {
+		s.remove(p)
+	}
+	s.mu.Unlock()
+	close(p.done)
+}
+
+func (s *idempotencyStore) remove(p *idempotentPut) {
+	if s.entries[p.key] == p {
+		delete(s.entries, p.key)
+	}
+	if p.elem != nil {
+		s.order.Remove(p.elem)
+		p.elem = nil
+	}
+}
+
+func (p *idempotentPut) finished() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// putDigest hashes everything that makes two puts the same message.
+func putDigest(message string, opts PutOptions) [sha256.Size]byte {
+	h := sha256.New()
+	for _, s := range []string{message, string(opts.GroupID), opts.ReplyToQueue} {
+		_ = binary.Write(h, binary.BigEndian, int64(len(s)))
+		h.Write([]byte(s))
+	}
+	_ = binary.Write(h, binary.BigEndian, [3]int32{opts.MsgSeqNumber, opts.Offset, opts.MsgFlags})
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// PutIdempotent puts message like PutMessage, unless a put by principal to
+// queue with the same key succeeded within the idempotency TTL; then it
+// returns that put's MsgId and replayed is true. Keys are scoped to the
+// principal, and the MsgId is derived from principal, queue and key. An
+// empty key puts unconditionally. Concurrent puts with one key wait for the
+// first; reusing a key for a different message fails with
+// ErrIdempotencyConflict.
+func (g *Gateway) PutIdempotent(principal, queueName, key, message string, opts PutOptions) (msgID []byte, replayed bool, err error) {
+	if key == "" {
+		msgID, err = g.PutMessage(queueName, message, opts)
+		return msgID, false, err
+	}
+	if len(key) > MaxIdempotencyKeyLength {
+		return nil, false, fmt.Errorf("idempotency key longer than %d bytes", MaxIdempotencyKeyLength)
+	}
+	if opts.MsgID != nil {
+		return nil, false, errors.New("an idempotent put derives its own MsgId")
+	}
+
+	digest := putDigest(message, opts)
+	for {
+		p, owner, err := g.idempotency.begin(idempotencyScope(principal, queueName, key), digest, time.Now())
+		if err != nil {
+			return nil, false, err
+		}
+		if !owner {
+			<-p.done
+			if p.err != nil {
+				// The first put failed and was forgotten; try again.
+				continue
+			}
+			return p.msgID, true, nil
+		}
+		opts.MsgID = IdempotentMsgID(principal, queueName, key)
+		msgID, err = g.PutMessage(queueName, message, opts)
+		g.idempotency.finish(p, msgID, err, time.Now())
+		return msgID, false, err
+	}
+}
@@ -3,9 +3,11 @@ package mqcore
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -15,12 +17,38 @@ import (
 
 type Gateway struct {
 	QMgr ibmmq.MQQueueManager
+	// qMgrName and cno are kept so workers that need a unit of work of their
+	// own can open further connections with the same settings.
+	qMgrName string
+	cno      *ibmmq.MQCNO
 	// browseMu protects browseSessions and browse cursor state.
 	browseMu sync.Mutex
 	// browseSessions holds active browse cursors keyed by browse_id.
 	browseSessions map[string]*browseSession
 	// browseSessionTTL limits how long an idle browse cursor can stay open.
 	browseSessionTTL time.Duration
+	// syncMu serializes units of work, which are scoped to the shared connection.
+	syncMu sync.Mutex
+	// handles caches open object handles for Put, Get and InquireQueue.
+	handles *handleCache
+	// backoutMu protects the poison message policy cache and counters.
+	backoutMu sync.Mutex
+	// backoutPolicies caches BOTHRESH/BOQNAME per queue.
+	backoutPolicies map[string]backoutPolicy
+	// deadLetterQ is the queue manager's DEADQ, resolved on first use.
+	deadLetterQ  string
+	backoutStats BackoutStats
+	// idempotency remembers recent PutIdempotent keys and their MsgIds.
+	idempotency *idempotencyStore
+	// scheduleQueue stages scheduled messages; empty disables scheduling.
+	scheduleQueue    string
+	scheduleInterval time.Duration
+	// stagingMu serializes Schedule, ListScheduled and CancelScheduled on
+	// staging, a connection of their own opened on first use.
+	stagingMu sync.Mutex
+	staging   *ibmmq.MQQueueManager
+	// validator checks payloads before they are put; nil accepts all.
+	validator PayloadValidator
 }
 
 func getenv(key, def string) string {
@@ -46,6 +74,57 @@ func getbool(key string, def bool) bool {
 	}
 }
 
+// getsecret reads key from the environment, or from the file named by key_FILE
+// (e.g. a Docker or Kubernetes secret mount). Setting both is an error.
+func getsecret(key, def string) (string, error) {
+	file := os.Getenv(key + "_FILE")
+	if file == "" {
+		return getenv(key, def), nil
+	}
+	if os.Getenv(key) != "" {
+		return "", fmt.Errorf("both %s and %s_FILE are set", key, key)
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("%s_FILE: %w", key, err)
+	}
+	// Secret files usually end with a newline that is not part of the value.
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+func getint(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+func getduration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(v))
+	if err != nil {
+		return def
+	}
+	return d
+}
+
+// ReasonCode returns the MQRC carried by err, or 0 when err is not an MQ error.
+func ReasonCode(err error) int32 {
+	var mqret *ibmmq.MQReturn
+	if errors.As(err, &mqret) {
+		return mqret.MQRC
+	}
+	return 0
+}
+
 func NewGateway() (*Gateway, error) {
 	// Read connection settings from environment variables.
 	tlsEnabled := getbool("MQ_TLS_ENABLED", false)
@@ -53,10 +132,25 @@ func NewGateway() (*Gateway, error) {
 	channel := getenv("MQ_CHANNEL", "DEV.TLS.SVRCONN") //"DEV.APP.SVRCONN")
 	host := getenv("MQ_HOST", "mq-local_host")
 	port := getenv("MQ_PORT", "1414")
-	user := getenv("MQ_USER", "app")
-	password := getenv("MQ_PASSWORD", "passw0rd")
+	user, err := getsecret("MQ_USER", "app")
+	if err != nil {
+		return nil, err
+	}
+	password, err := getsecret("MQ_PASSWORD", "passw0rd")
+	if err != nil {
+		return nil, err
+	}
 	sslCipherSpec := getenv("MQ_SSLCIPH", "")
 	sslKeyRepo := getenv("MQ_KEY_REPOSITORY", "")
+	handleCacheSize := getint("MQ_HANDLE_CACHE_SIZE", 64)
+	handleCacheIdle := getduration("MQ_HANDLE_CACHE_IDLE", 5*time.Minute)
+	idempotencyKeys := getint("MQ_IDEMPOTENCY_MAX_KEYS", 10000)
+	idempotencyTTL := getduration("MQ_IDEMPOTENCY_TTL", 24*time.Hour)
+	scheduleQueue := getenv("MQ_SCHEDULE_QUEUE", "")
+	scheduleInterval := getduration("MQ_SCHEDULE_INTERVAL", time.Second)
+	if scheduleInterval <= 0 {
+		scheduleInterval = time.Second
+	}
 
 	connName := fmt.Sprintf("%s(%s)", host, port)
 
@@ -94,8 +188,6 @@ func NewGateway() (*Gateway, error) {
 	slog.Info(fmt.Sprintf("[mqcore] Connecting to MQ qmgr=%s at %s over channel=%s", qMgrName, connName, channel),
 		"csp.AuthenticationType ", ibmmq.MQCSP_AUTH_USER_ID_AND_PWD,
 		"csp.UserId", user,
-		"csp.Password", password,
-		"cno.SecurityParms", cno.SecurityParms,
 		"id", "6d63fb38-b7b3-44ae-96de-81787257d3aa")
 
 	qMgr, err := ibmmq.Connx(qMgrName, cno)
@@ -114,11 +206,24 @@ func NewGateway() (*Gateway, error) {
 
 	return &Gateway{
 		QMgr:             qMgr,
+		qMgrName:         qMgrName,
+		cno:              cno,
 		browseSessions:   make(map[string]*browseSession),
 		browseSessionTTL: 5 * time.Minute,
+		handles:          newHandleCache(handleCacheSize, handleCacheIdle),
+		backoutPolicies:  make(map[string]backoutPolicy),
+		idempotency:      newIdempotencyStore(idempotencyKeys, idempotencyTTL),
+		scheduleQueue:    scheduleQueue,
+		scheduleInterval: scheduleInterval,
 	}, nil
 }
 
+// connect opens another connection to the queue manager. Units of work are
+// scoped to a connection, so each one runs independently of syncMu.
+func (g *Gateway) connect() (ibmmq.MQQueueManager, error) {
+	return ibmmq.Connx(g.qMgrName, g.cno)
+}
+
 func (g *Gateway) Close() {
 	// Close any browse cursors before disconnecting the QMgr.
 	g.browseMu.Lock()
@@ -127,12 +232,21 @@ func (g *Gateway) Close() {
 	}
 	g.browseSessions = make(map[string]*browseSession)
 	g.browseMu.Unlock()
+	g.handles.closeAll()
+	g.stagingMu.Lock()
+	if g.staging != nil {
+		_ = g.staging.Disc()
+		g.staging = nil
+	}
+	g.stagingMu.Unlock()
 	_ = g.QMgr.Disc()
 }
 
 type browseSession struct {
 	// qObj is the open queue handle used for browsing.
 	qObj ibmmq.MQObject
+	// queue is the queue the cursor was opened on.
+	queue string
 	// lastUsed tracks idle time for cleanup.
 	lastUsed time.Time
 }
@@ -275,66 +389,190 @@ func logTLSStatus(qMgr ibmmq.MQQueueManager, channel string) {
 
 // Put sends a message to the given queue.
 func (g *Gateway) Put(queueName, message string) error {
-	// Put writes a single message to the queue (non-transactional).
-	od := ibmmq.NewMQOD()
-	od.ObjectType = ibmmq.MQOT_Q
-	od.ObjectName = queueName
+	_, err := g.PutMessage(queueName, message, PutOptions{})
+	return err
+}
+
+// PutOptions carries optional MQMD fields for a put.
+type PutOptions struct {
+	// GroupID is the 24-byte MQMD GroupId; nil leaves it unset.
+	GroupID []byte
+	// MsgSeqNumber is the sequence number of the message within its group.
+	MsgSeqNumber int32
+	// Offset is the offset of a segment within its logical message.
+	Offset int32
+	// MsgFlags holds MQMF_* segmentation and grouping flags.
+	MsgFlags int32
+	// ReplyToQueue, when set, marks the message as a request whose reply
+	// should go to this queue.
+	ReplyToQueue string
+	// MsgID is the 24-byte MQMD MsgId; nil lets the queue manager generate one.
+	MsgID []byte
+}
+
+// PutMessage sends a message with optional grouping fields and returns its MsgId.
+func (g *Gateway) PutMessage(queueName, message string, opts PutOptions) ([]byte, error) {
+	// PutMessage writes a single message to the queue (non-transactional).
+	if err := g.validate(queueName, message); err != nil {
+		return nil, err
+	}
+	md := ibmmq.NewMQMD()
+	if err := opts.apply(md); err != nil {
+		return nil, err
+	}
 
-	qObj, err := g.QMgr.Open(od, ibmmq.MQOO_OUTPUT)
+	h, err := g.acquireHandle(queueName, ibmmq.MQOO_OUTPUT)
 	if err != nil {
-		return fmt.Errorf("MQOPEN: %w", err)
+		return nil, fmt.Errorf("MQOPEN: %w", err)
 	}
-	defer qObj.Close(0)
 
-	md := ibmmq.NewMQMD()
 	pmo := ibmmq.NewMQPMO()
 	pmo.Options = ibmmq.MQPMO_NO_SYNCPOINT
 
-	if err := qObj.Put(md, pmo, []byte(message)); err != nil {
-		return fmt.Errorf("MQPUT: %w", err)
+	err = h.obj.Put(md, pmo, []byte(message))
+	g.releaseHandle(h, err)
+	if err != nil {
+		return nil, fmt.Errorf("MQPUT: %w", err)
+	}
+	return md.MsgId, nil
+}
+
+func (o PutOptions) apply(md *ibmmq.MQMD) error {
+	if o.ReplyToQueue != "" {
+		md.MsgType = ibmmq.MQMT_REQUEST
+		md.ReplyToQ = o.ReplyToQueue
+	}
+	if o.MsgID != nil {
+		if len(o.MsgID) != int(ibmmq.MQ_MSG_ID_LENGTH) {
+			return fmt.Errorf("msg_id must be %d bytes", ibmmq.MQ_MSG_ID_LENGTH)
+		}
+		md.MsgId = o.MsgID
 	}
+	// Grouping fields live in MQMD version 2.
+	if o.GroupID == nil && o.MsgSeqNumber == 0 && o.Offset == 0 && o.MsgFlags == 0 {
+		return nil
+	}
+	md.Version = ibmmq.MQMD_VERSION_2
+	if o.GroupID != nil {
+		if len(o.GroupID) != int(ibmmq.MQ_GROUP_ID_LENGTH) {
+			return fmt.Errorf("group_id must be %d bytes", ibmmq.MQ_GROUP_ID_LENGTH)
+		}
+		md.GroupId = o.GroupID
+	}
+	if o.MsgSeqNumber > 0 {
+		md.MsgSeqNumber = o.MsgSeqNumber
+	}
+	md.Offset = o.Offset
+	md.MsgFlags = o.MsgFlags
 	return nil
 }
 
 // Get receives a message from the given queue.
 func (g *Gateway) Get(queueName string, waitMs int, maxBytes int) (string, bool, error) {
-	// Get consumes one message from the queue.
-	if maxBytes <= 0 {
-		maxBytes = 64 * 1024
+	msg, empty, err := g.GetMessage(queueName, GetOptions{WaitMs: waitMs, MaxBytes: maxBytes})
+	if err != nil || empty {
+		return "", empty, err
 	}
+	return msg.Payload, false, nil
+}
 
-	od := ibmmq.NewMQOD()
-	od.ObjectType = ibmmq.MQOT_Q
-	od.ObjectName = queueName
+// GetOptions controls how a message is retrieved.
+type GetOptions struct {
+	// WaitMs is the wait interval in milliseconds (0 returns immediately).
+	WaitMs int
+	// MaxBytes caps the receive buffer size.
+	MaxBytes int
+	// CompleteMsg asks the queue manager to reassemble segments into one
+	// logical message (MQGMO_COMPLETE_MSG).
+	CompleteMsg bool
+	// CorrelID, when set, only matches messages with this 24-byte CorrelId.
+	CorrelID []byte
+	// MsgID, when set, only matches messages with this 24-byte MsgId.
+	MsgID []byte
+}
 
-	qObj, err := g.QMgr.Open(od, ibmmq.MQOO_INPUT_AS_Q_DEF)
-	if err != nil {
-		return "", false, fmt.Errorf("MQOPEN: %w", err)
+// apply sets the match and wait options shared by gets and browses.
+func (o GetOptions) apply(md *ibmmq.MQMD, gmo *ibmmq.MQGMO) {
+	if o.CompleteMsg {
+		// Segment reassembly needs a version 2 GMO.
+		gmo.Version = ibmmq.MQGMO_VERSION_2
+		gmo.MatchOptions = ibmmq.MQMO_NONE
+		gmo.Options |= ibmmq.MQGMO_COMPLETE_MSG
+	}
+	if o.CorrelID != nil {
+		// Select the reply to a request by its correlation id.
+		gmo.Version = ibmmq.MQGMO_VERSION_2
+		gmo.MatchOptions |= ibmmq.MQMO_MATCH_CORREL_ID
+		md.CorrelId = o.CorrelID
+	}
+	if o.MsgID != nil {
+		gmo.Version = ibmmq.MQGMO_VERSION_2
+		gmo.MatchOptions |= ibmmq.MQMO_MATCH_MSG_ID
+		md.MsgId = o.MsgID
 	}
-	defer qObj.Close(0)
-
-	md := ibmmq.NewMQMD()
-	gmo := ibmmq.NewMQGMO()
-	gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_CONVERT
 
-	if waitMs > 0 {
-		// Wait for up to waitMs.
+	if o.WaitMs > 0 {
+		// Wait for up to WaitMs.
 		gmo.Options |= ibmmq.MQGMO_WAIT
-		gmo.WaitInterval = int32(waitMs)
+		gmo.WaitInterval = int32(o.WaitMs)
 	} else {
 		// Return immediately if no message is available.
 		gmo.Options |= ibmmq.MQGMO_NO_WAIT
 	}
+}
+
+// Message is a received payload together with the MQMD fields we expose.
+type Message struct {
+	Payload      string
+	MsgID        []byte
+	CorrelID     []byte
+	GroupID      []byte
+	MsgSeqNumber int32
+	Offset       int32
+	MsgFlags     int32
+}
+
+func newMessage(md *ibmmq.MQMD, payload []byte) *Message {
+	return &Message{
+		Payload:      string(payload),
+		MsgID:        md.MsgId,
+		CorrelID:     md.CorrelId,
+		GroupID:      md.GroupId,
+		MsgSeqNumber: md.MsgSeqNumber,
+		Offset:       md.Offset,
+		MsgFlags:     md.MsgFlags,
+	}
+}
+
+// GetMessage receives one message and returns it with its MQMD fields.
+func (g *Gateway) GetMessage(queueName string, opts GetOptions) (*Message, bool, error) {
+	// GetMessage consumes one message from the queue.
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = 64 * 1024
+	}
+
+	h, err := g.acquireHandle(queueName, ibmmq.MQOO_INPUT_AS_Q_DEF)
+	if err != nil {
+		return nil, false, fmt.Errorf("MQOPEN: %w", err)
+	}
+
+	md := ibmmq.NewMQMD()
+	md.Version = ibmmq.MQMD_VERSION_2
+	gmo := ibmmq.NewMQGMO()
+	gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_CONVERT
+	opts.apply(md, gmo)
 
 	buf := make([]byte, maxBytes)
-	msgLen, err := qObj.Get(md, gmo, buf)
+	msgLen, err := h.obj.Get(md, gmo, buf)
+	g.releaseHandle(h, err)
 	if err != nil {
 		if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
-			return "", true, nil
+			return nil, true, nil
 		}
-		return "", false, fmt.Errorf("MQGET: %w", err)
+		return nil, false, fmt.Errorf("MQGET: %w", err)
 	}
-	return string(buf[:msgLen]), false, nil
+	return newMessage(md, buf[:msgLen]), false, nil
 }
 
 func (g *Gateway) InquireQueue(queueName string) (*QueueInfo, error) {
@@ -343,15 +581,10 @@ func (g *Gateway) InquireQueue(queueName string) (*QueueInfo, error) {
 		return nil, fmt.Errorf("queue required")
 	}
 
-	od := ibmmq.NewMQOD()
-	od.ObjectType = ibmmq.MQOT_Q
-	od.ObjectName = queueName
-
-	qObj, err := g.QMgr.Open(od, ibmmq.MQOO_INQUIRE)
+	h, err := g.acquireHandle(queueName, ibmmq.MQOO_INQUIRE)
 	if err != nil {
 		return nil, fmt.Errorf("MQOPEN: %w", err)
 	}
-	defer qObj.Close(0)
 
 	// Selectors define which attributes to return.
 	selectors := []int32{
@@ -368,7 +601,8 @@ func (g *Gateway) InquireQueue(queueName string) (*QueueInfo, error) {
 		ibmmq.MQIA_OPEN_OUTPUT_COUNT,
 	}
 
-	attrs, err := qObj.Inq(selectors)
+	attrs, err := h.obj.Inq(selectors)
+	g.releaseHandle(h, err)
 	if err != nil {
 		return nil, fmt.Errorf("MQINQ: %w", err)
 	}
@@ -427,6 +661,17 @@ func stringAttr(attrs map[int32]interface{}, key int32) string {
 
 func (g *Gateway) BrowseFirst(queueName string, waitMs int, maxBytes int) (string, bool, string, error) {
 	// BrowseFirst opens a browse cursor and returns the first message.
+	msg, empty, browseID, err := g.BrowseFirstMessage(queueName, GetOptions{WaitMs: waitMs, MaxBytes: maxBytes})
+	if err != nil || empty {
+		return "", empty, "", err
+	}
+	return msg.Payload, false, browseID, nil
+}
+
+// BrowseFirstMessage opens a browse cursor on the first message matching opts
+// and returns it with its MQMD fields. No cursor is kept when the queue is empty.
+func (g *Gateway) BrowseFirstMessage(queueName string, opts GetOptions) (*Message, bool, string, error) {
+	maxBytes := opts.MaxBytes
 	if maxBytes <= 0 {
 		maxBytes = 64 * 1024
 	}
@@ -440,89 +685,110 @@ func (g *Gateway) BrowseFirst(queueName string, waitMs int, maxBytes int) (strin
 
 	qObj, err := g.QMgr.Open(od, ibmmq.MQOO_BROWSE)
 	if err != nil {
-		return "", false, "", fmt.Errorf("MQOPEN: %w", err)
+		return nil, false, "", fmt.Errorf("MQOPEN: %w", err)
 	}
 
 	md := ibmmq.NewMQMD()
 	gmo := ibmmq.NewMQGMO()
 	gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_BROWSE_FIRST | ibmmq.MQGMO_CONVERT
-
-	if waitMs > 0 {
-		// Wait for up to waitMs.
-		gmo.Options |= ibmmq.MQGMO_WAIT
-		gmo.WaitInterval = int32(waitMs)
-	} else {
-		// Return immediately if no message is available.
-		gmo.Options |= ibmmq.MQGMO_NO_WAIT
-	}
+	opts.apply(md, gmo)
 
 	buf := make([]byte, maxBytes)
 	msgLen, err := qObj.Get(md, gmo, buf)
 	if err != nil {
 		_ = qObj.Close(0)
 		if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
-			return "", true, "", nil
+			return nil, true, "", nil
 		}
-		return "", false, "", fmt.Errorf("MQGET(BROWSE_FIRST): %w", err)
+		return nil, false, "", fmt.Errorf("MQGET(BROWSE_FIRST): %w", err)
 	}
 
 	browseID, err := newBrowseID()
 	if err != nil {
 		_ = qObj.Close(0)
-		return "", false, "", fmt.Errorf("browse id: %w", err)
+		return nil, false, "", fmt.Errorf("browse id: %w", err)
 	}
 
 	// Store the browse cursor for subsequent BrowseNext calls.
 	g.browseMu.Lock()
 	g.browseSessions[browseID] = &browseSession{
 		qObj:     qObj,
+		queue:    queueName,
 		lastUsed: time.Now(),
 	}
 	g.browseMu.Unlock()
 
-	return string(buf[:msgLen]), false, browseID, nil
+	return newMessage(md, buf[:msgLen]), false, browseID, nil
 }
 
 func (g *Gateway) BrowseNext(browseID string, waitMs int, maxBytes int) (string, bool, error) {
 	// BrowseNext continues an existing browse cursor.
+	msg, empty, err := g.BrowseNextMessage(browseID, GetOptions{WaitMs: waitMs, MaxBytes: maxBytes})
+	if err != nil || empty {
+		return "", empty, err
+	}
+	return msg.Payload, false, nil
+}
+
+// BrowseNextMessage moves an existing browse cursor to the next message
+// matching opts and returns it with its MQMD fields.
+func (g *Gateway) BrowseNextMessage(browseID string, opts GetOptions) (*Message, bool, error) {
 	if browseID == "" {
-		return "", false, fmt.Errorf("browse_id required")
+		return nil, false, fmt.Errorf("browse_id required")
 	}
+	maxBytes := opts.MaxBytes
 	if maxBytes <= 0 {
 		maxBytes = 64 * 1024
 	}
 
 	sess, err := g.getBrowseSession(browseID)
 	if err != nil {
-		return "", false, err
+		return nil, false, err
 	}
 
 	md := ibmmq.NewMQMD()
 	gmo := ibmmq.NewMQGMO()
 	gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_BROWSE_NEXT | ibmmq.MQGMO_CONVERT
-
-	if waitMs > 0 {
-		// Wait for up to waitMs.
-		gmo.Options |= ibmmq.MQGMO_WAIT
-		gmo.WaitInterval = int32(waitMs)
-	} else {
-		// Return immediately if no message is available.
-		gmo.Options |= ibmmq.MQGMO_NO_WAIT
-	}
+	opts.apply(md, gmo)
 
 	buf := make([]byte, maxBytes)
 	msgLen, err := sess.qObj.Get(md, gmo, buf)
 	if err != nil {
 		if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
-			return "", true, nil
+			return nil, true, nil
 		}
-		return "", false, fmt.Errorf("MQGET(BROWSE_NEXT): %w", err)
+		return nil, false, fmt.Errorf("MQGET(BROWSE_NEXT): %w", err)
 	}
 
 	// Refresh idle timer after successful browse.
 	g.touchBrowseSession(browseID)
 
-	return string(buf[:msgLen]), false, nil
+	return newMessage(md, buf[:msgLen]), false, nil
+}
+
+// ErrBrowseNotFound is returned for a browse_id that was never issued, has
+// been closed or expired after browseSessionTTL.
+var ErrBrowseNotFound = errors.New("browse_id not found or expired")
+
+// EndBrowse closes a browse cursor before it expires.
+func (g *Gateway) EndBrowse(browseID string) error {
+	g.browseMu.Lock()
+	sess := g.browseSessions[browseID]
+	delete(g.browseSessions, browseID)
+	g.browseMu.Unlock()
+	if sess == nil {
+		return ErrBrowseNotFound
+	}
+	return sess.qObj.Close(0)
+}
+
+// BrowseQueue returns the queue an active browse cursor was opened on.
+func (g *Gateway) BrowseQueue(browseID string) (string, error) {
+	sess, err := g.getBrowseSession(browseID)
+	if err != nil {
+		return "", err
+	}
+	return sess.queue, nil
 }
 
 func newBrowseID() (string, error) {
@@ -542,7 +808,7 @@ func (g *Gateway) getBrowseSession(browseID string) (*browseSession, error) {
 	defer g.browseMu.Unlock()
 	sess := g.browseSessions[browseID]
 	if sess == nil {
-		return nil, fmt.Errorf("browse_id not found or expired")
+		return nil, ErrBrowseNotFound
 	}
 	// Touch on read to extend the session lifetime.
 	sess.lastUsed = time.Now()
@@ -2,7 +2,16 @@ package mqcore
 
 import (
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
 )
 
 func TestNewBrowseID(t *testing.T) {
@@ -93,3 +102,552 @@ func TestGetbool(t *testing.T) {
 		t.Fatalf("getbool default got %v", got)
 	}
 }
+
+func TestParseAndFormatID(t *testing.T) {
+	// ParseID should pad short ids to 24 bytes and FormatID should round-trip them.
+	id, err := ParseID("0a0b")
+	if err != nil {
+		t.Fatalf("ParseID error: %v", err)
+	}
+	if len(id) != 24 || id[0] != 0x0a || id[1] != 0x0b || id[2] != 0 {
+		t.Fatalf("ParseID got %x", id)
+	}
+	if got := FormatID(id); got != "0a0b"+strings.Repeat("00", 22) {
+		t.Fatalf("FormatID got %q", got)
+	}
+	if got := FormatID(make([]byte, 24)); got != "" {
+		t.Fatalf("FormatID none got %q", got)
+	}
+	if id, err := ParseID(""); err != nil || id != nil {
+		t.Fatalf("ParseID empty got %x, %v", id, err)
+	}
+	if _, err := ParseID("zz"); err == nil {
+		t.Fatalf("ParseID expected error for non-hex input")
+	}
+	if _, err := ParseID(strings.Repeat("00", 25)); err == nil {
+		t.Fatalf("ParseID expected error for oversized id")
+	}
+}
+
+func TestMarkRolledBack(t *testing.T) {
+	// markRolledBack should only overwrite entries that had not already failed.
+	failed := errors.New("boom")
+	results := []BatchResult{
+		{MsgID: []byte{1}, Message: &Message{Payload: "a"}},
+		{Err: failed},
+	}
+	markRolledBack(results)
+	if results[0].Err != ErrRolledBack || results[0].Message != nil {
+		t.Fatalf("expected first entry rolled back, got %+v", results[0])
+	}
+	if results[1].Err != failed {
+		t.Fatalf("expected original error kept, got %v", results[1].Err)
+	}
+}
+
+func TestGetintAndGetduration(t *testing.T) {
+	// getint/getduration should parse valid values and fall back on bad input.
+	t.Setenv("MQCORE_TEST_INT", "42")
+	if got := getint("MQCORE_TEST_INT", 1); got != 42 {
+		t.Fatalf("getint got %d", got)
+	}
+	t.Setenv("MQCORE_TEST_INT", "nope")
+	if got := getint("MQCORE_TEST_INT", 1); got != 1 {
+		t.Fatalf("getint default got %d", got)
+	}
+	t.Setenv("MQCORE_TEST_DUR", "90s")
+	if got := getduration("MQCORE_TEST_DUR", time.Second); got != 90*time.Second {
+		t.Fatalf("getduration got %v", got)
+	}
+	t.Setenv("MQCORE_TEST_DUR", "soon")
+	if got := getduration("MQCORE_TEST_DUR", time.Second); got != time.Second {
+		t.Fatalf("getduration default got %v", got)
+	}
+}
+
+func TestGetsecret(t *testing.T) {
+	// getsecret should prefer key_FILE, strip the trailing newline and reject ambiguity.
+	if got, err := getsecret("MQCORE_TEST_SECRET", "def"); err != nil || got != "def" {
+		t.Fatalf("default got %q, %v", got, err)
+	}
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("MQCORE_TEST_SECRET_FILE", path)
+	if got, err := getsecret("MQCORE_TEST_SECRET", "def"); err != nil || got != "s3cret" {
+		t.Fatalf("file got %q, %v", got, err)
+	}
+	t.Setenv("MQCORE_TEST_SECRET", "inline")
+	if _, err := getsecret("MQCORE_TEST_SECRET", "def"); err == nil {
+		t.Fatalf("expected error when both are set")
+	}
+}
+
+func TestReasonCode(t *testing.T) {
+	// ReasonCode should unwrap MQ errors and return 0 for anything else.
+	wrapped := fmt.Errorf("MQGET: %w", &ibmmq.MQReturn{MQCC: ibmmq.MQCC_FAILED, MQRC: ibmmq.MQRC_NOT_AUTHORIZED})
+	if got := ReasonCode(wrapped); got != ibmmq.MQRC_NOT_AUTHORIZED {
+		t.Fatalf("ReasonCode got %d", got)
+	}
+	if got := ReasonCode(errors.New("plain")); got != 0 {
+		t.Fatalf("ReasonCode(plain) got %d", got)
+	}
+}
+
+func TestIsHandleInvalidating(t *testing.T) {
+	// Only connection/object reason codes should drop a cached handle.
+	wrapped := fmt.Errorf("MQPUT: %w", &ibmmq.MQReturn{MQCC: ibmmq.MQCC_FAILED, MQRC: ibmmq.MQRC_OBJECT_CHANGED})
+	if !isHandleInvalidating(wrapped) {
+		t.Fatalf("expected MQRC_OBJECT_CHANGED to invalidate")
+	}
+	if isHandleInvalidating(&ibmmq.MQReturn{MQCC: ibmmq.MQCC_FAILED, MQRC: ibmmq.MQRC_NO_MSG_AVAILABLE}) {
+		t.Fatalf("expected MQRC_NO_MSG_AVAILABLE to keep the handle")
+	}
+	if isHandleInvalidating(nil) {
+		t.Fatalf("expected nil error to keep the handle")
+	}
+}
+
+func TestDLQFilterMatches(t *testing.T) {
+	// Empty filters match everything; reasons and destination narrow the set.
+	dl := &DeadLetter{Reason: ibmmq.MQRC_Q_FULL, DestQName: "ORDERS.IN"}
+	if !(DLQFilter{}).matches(dl) {
+		t.Fatalf("empty filter should match")
+	}
+	if !(DLQFilter{Reasons: []int32{ibmmq.MQRC_PUT_INHIBITED, ibmmq.MQRC_Q_FULL}}).matches(dl) {
+		t.Fatalf("reason filter should match")
+	}
+	if (DLQFilter{Reasons: []int32{ibmmq.MQRC_PUT_INHIBITED}}).matches(dl) {
+		t.Fatalf("reason filter should not match")
+	}
+	if !(DLQFilter{DestQueue: "orders.in"}).matches(dl) {
+		t.Fatalf("destination filter should match case-insensitively")
+	}
+	if (DLQFilter{DestQueue: "ORDERS.OUT"}).matches(dl) {
+		t.Fatalf("destination filter should not match")
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	msgs := []*ArchivedMessage{
+		{
+			MsgID:       "414d5120514d31202020202020202020a1b2c3d4e5f60001",
+			Format:      "MQSTR",
+			Encoding:    546,
+			Persistence: 1,
+			PutDateTime: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Properties: []Property{
+				mustProperty(t, "orderId", int64(42)),
+				mustProperty(t, "region", "eu"),
+				mustProperty(t, "blob", []byte{0, 1, 2}),
+				mustProperty(t, "flag", true),
+			},
+			Payload: []byte("hello"),
+		},
+		{MsgType: 8, Payload: []byte{}},
+	}
+
+	for _, format := range []ArchiveFormat{ArchiveJSONL, ArchiveTar} {
+		var buf strings.Builder
+		w, err := NewArchiveWriter(format, &buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range msgs {
+			if err := w.WriteMessage(m); err != nil {
+				t.Fatalf("%s: WriteMessage: %v", format, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%s: Close: %v", format, err)
+		}
+
+		r, err := NewArchiveReader(format, strings.NewReader(buf.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, want := range msgs {
+			got, err := r.ReadMessage()
+			if err != nil {
+				t.Fatalf("%s: message %d: %v", format, i, err)
+			}
+			if got.MsgID != want.MsgID || string(got.Payload) != string(want.Payload) ||
+				!got.PutDateTime.Equal(want.PutDateTime) || len(got.Properties) != len(want.Properties) {
+				t.Errorf("%s: message %d = %+v, want %+v", format, i, got, want)
+			}
+			for j, p := range got.Properties {
+				v, err := p.GoValue()
+				wv, _ := want.Properties[j].GoValue()
+				if err != nil || fmt.Sprint(v) != fmt.Sprint(wv) || fmt.Sprintf("%T", v) != fmt.Sprintf("%T", wv) {
+					t.Errorf("%s: property %s = %T(%v), %v; want %T(%v)", format, p.Name, v, v, err, wv, wv)
+				}
+			}
+		}
+		if _, err := r.ReadMessage(); !errors.Is(err, io.EOF) {
+			t.Errorf("%s: after last message: %v, want io.EOF", format, err)
+		}
+	}
+}
+
+func mustProperty(t *testing.T, name string, v any) Property {
+	t.Helper()
+	p, err := newProperty(name, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestParseArchiveFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ArchiveFormat
+		wantErr bool
+	}{
+		{"", ArchiveJSONL, false},
+		{"jsonl", ArchiveJSONL, false},
+		{"TAR", ArchiveTar, false},
+		{"zip", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseArchiveFormat(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseArchiveFormat(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestTransferMessagesValidation(t *testing.T) {
+	// These are rejected before any MQ call, so a zero Gateway is enough.
+	tests := []TransferOptions{
+		{Destination: "Q2"},
+		{Source: "Q1"},
+		{Source: "Q1", Destination: "q1"},
+	}
+	var g Gateway
+	for _, opts := range tests {
+		if _, err := g.TransferMessages(opts); err == nil {
+			t.Errorf("TransferMessages(%q -> %q) succeeded, want error", opts.Source, opts.Destination)
+		}
+	}
+}
+
+func TestIdempotentMsgID(t *testing.T) {
+	id := IdempotentMsgID("alice", "Q1", "order-42")
+	if len(id) != int(ibmmq.MQ_MSG_ID_LENGTH) {
+		t.Fatalf("len = %d, want %d", len(id), ibmmq.MQ_MSG_ID_LENGTH)
+	}
+	if string(id) != string(IdempotentMsgID("alice", "Q1", "order-42")) {
+		t.Fatal("MsgId is not stable for one principal, queue and key")
+	}
+	if string(id) == string(IdempotentMsgID("alice", "Q2", "order-42")) {
+		t.Fatal("MsgId does not depend on the queue")
+	}
+	if string(id) == string(IdempotentMsgID("bob", "Q1", "order-42")) {
+		t.Fatal("MsgId does not depend on the principal")
+	}
+	if idempotencyScope("alice", "Q1", "order-42") == idempotencyScope("bob", "Q1", "order-42") {
+		t.Fatal("two principals share an idempotency key")
+	}
+}
+
+func TestIdempotencyStore(t *testing.T) {
+	s := newIdempotencyStore(2, time.Minute)
+	now := time.Now()
+	digest := putDigest("hello", PutOptions{})
+
+	p, owner, err := s.begin("Q1\x00k1", digest, now)
+	if err != nil || !owner {
+		t.Fatalf("first begin: owner=%v err=%v", owner, err)
+	}
+	// A concurrent retry waits for the first put.
+	dup, owner, err := s.begin("Q1\x00k1", digest, now)
+	if err != nil || owner || dup != p {
+		t.Fatalf("second begin: owner=%v err=%v", owner, err)
+	}
+	s.finish(p, []byte("id-1"), nil, now)
+	<-dup.done
+	if string(dup.msgID) != "id-1" {
+		t.Fatalf("waiter got %q", dup.msgID)
+	}
+
+	// The same key with another payload is a conflict.
+	if _, _, err := s.begin("Q1\x00k1", putDigest("other", PutOptions{}), now); !errors.Is(err, ErrIdempotencyConflict) {
+		t.Fatalf("err = %v, want ErrIdempotencyConflict", err)
+	}
+
+	// A failed put is forgotten so the retry puts again.
+	f, _, _ := s.begin("Q1\x00k2", digest, now)
+	s.finish(f, nil, errors.New("MQPUT failed"), now)
+	if _, owner, _ := s.begin("Q1\x00k2", digest, now); !owner {
+		t.Fatal("retry after a failed put does not own the key")
+	}
+
+	// Expired keys are dropped.
+	later := now.Add(2 * time.Minute)
+	if _, owner, _ := s.begin("Q1\x00k1", digest, later); !owner {
+		t.Fatal("expired key was replayed")
+	}
+}
+
+func TestIdempotencyStoreBound(t *testing.T) {
+	s := newIdempotencyStore(2, time.Hour)
+	now := time.Now()
+	for _, k := range []string{"a", "b", "c"} {
+		p, _, _ := s.begin(k, putDigest(k, PutOptions{}), now)
+		s.finish(p, []byte(k), nil, now)
+	}
+	if len(s.entries) > 2 {
+		t.Fatalf("store holds %d keys, max 2", len(s.entries))
+	}
+	if _, ok := s.entries["a"]; ok {
+		t.Fatal("oldest key was not evicted")
+	}
+}
+
+func TestIdempotencyStoreBoundWithPutInFlight(t *testing.T) {
+	// A put that never finishes must not stop the finished ones behind it
+	// from being evicted.
+	s := newIdempotencyStore(2, time.Hour)
+	now := time.Now()
+	if _, owner, _ := s.begin("slow", putDigest("slow", PutOptions{}), now); !owner {
+		t.Fatal("slow put does not own its key")
+	}
+	for _, k := range []string{"a", "b", "c", "d"} {
+		p, _, _ := s.begin(k, putDigest(k, PutOptions{}), now)
+		s.finish(p, []byte(k), nil, now)
+	}
+	if len(s.entries) > 2 {
+		t.Fatalf("store holds %d keys, max 2", len(s.entries))
+	}
+	if _, ok := s.entries["slow"]; !ok {
+		t.Fatal("put in flight was evicted")
+	}
+}
+
+func TestDueTime(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	got, err := DueTime("", 15*60*1000, now)
+	if err != nil || !got.Equal(now.Add(15*time.Minute)) {
+		t.Fatalf("delay: got %v, %v", got, err)
+	}
+	got, err = DueTime("2026-03-02T02:00:00+01:00", 0, now)
+	if err != nil || !got.Equal(time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC)) {
+		t.Fatalf("deliver_at: got %v, %v", got, err)
+	}
+	for _, tc := range []struct {
+		at    string
+		delay int64
+	}{
+		{"", 0},
+		{"", -1},
+		{"02:00", 0},
+		{"2026-03-02T02:00:00Z", 1000},
+	} {
+		if _, err := DueTime(tc.at, tc.delay, now); err == nil {
+			t.Errorf("DueTime(%q, %d) succeeded", tc.at, tc.delay)
+		}
+	}
+}
+
+func TestQueueSelector(t *testing.T) {
+	if got, want := queueSelector("APP.Q1"), "gwTargetQueue = 'APP.Q1'"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := queueSelector("A'B"), "gwTargetQueue = 'A''B'"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestDueSelector(t *testing.T) {
+	now := time.UnixMilli(1772416800000)
+	if got, want := dueSelector(now, nil), "gwDueTime <= 1772416800000"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	got := dueSelector(now, []string{"FULL.Q", "A'B"})
+	want := "gwDueTime <= 1772416800000 AND gwTargetQueue <> 'FULL.Q' AND gwTargetQueue <> 'A''B'"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestRefusedForGood(t *testing.T) {
+	// Only refusals that waiting cannot cure dead-letter a scheduled message.
+	for rc, want := range map[int32]bool{
+		ibmmq.MQRC_UNKNOWN_OBJECT_NAME: true,
+		ibmmq.MQRC_NOT_AUTHORIZED:      true,
+		ibmmq.MQRC_MSG_TOO_BIG_FOR_Q:   true,
+		ibmmq.MQRC_Q_FULL:              false,
+		ibmmq.MQRC_PUT_INHIBITED:       false,
+		ibmmq.MQRC_CONNECTION_BROKEN:   false,
+	} {
+		err := fmt.Errorf("MQPUT(Q): %w", &ibmmq.MQReturn{MQCC: ibmmq.MQCC_FAILED, MQRC: rc})
+		if got := refusedForGood(err); got != want {
+			t.Errorf("refusedForGood(%s) = %v, want %v", ibmmq.MQItoString("RC", int(rc)), got, want)
+		}
+	}
+	if refusedForGood(errors.New("not an MQ error")) {
+		t.Error("refusedForGood of a non-MQ error")
+	}
+}
+
+func TestBackoutPolicyReached(t *testing.T) {
+	// BOTHRESH 0 (unset) never triggers; otherwise the count must reach it.
+	for _, tc := range []struct {
+		threshold, count int32
+		want             bool
+	}{
+		{0, 0, false},
+		{0, 50, false},
+		{3, 2, false},
+		{3, 3, true},
+		{3, 4, true},
+		{1, 1, true},
+	} {
+		if got := (backoutPolicy{threshold: tc.threshold}).reached(tc.count); got != tc.want {
+			t.Errorf("threshold %d, count %d: reached = %v, want %v", tc.threshold, tc.count, got, tc.want)
+		}
+	}
+}
+
+func TestBackoutPolicyCached(t *testing.T) {
+	// A fresh cached policy is served without an MQINQ; the gateway has no
+	// connection, so reaching MQ would fail the test.
+	g := &Gateway{backoutPolicies: map[string]backoutPolicy{
+		"APP.Q": {threshold: 3, requeueQ: "APP.BACKOUT", loadedAt: time.Now()},
+	}}
+	p, err := g.backoutPolicy("APP.Q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.threshold != 3 || p.requeueQ != "APP.BACKOUT" {
+		t.Fatalf("policy = %+v", p)
+	}
+}
+
+func TestPoisonTarget(t *testing.T) {
+	g := &Gateway{deadLetterQ: "SYSTEM.DEAD.LETTER.QUEUE"}
+
+	// BOQNAME wins and needs no DLH.
+	target, toDLQ, err := g.poisonTarget("APP.Q", backoutPolicy{threshold: 3, requeueQ: "APP.BACKOUT"})
+	if err != nil || target != "APP.BACKOUT" || toDLQ {
+		t.Fatalf("with BOQNAME: %q, %v, %v", target, toDLQ, err)
+	}
+
+	// Without BOQNAME the message goes to DEADQ behind an MQDLH.
+	target, toDLQ, err = g.poisonTarget("APP.Q", backoutPolicy{threshold: 3})
+	if err != nil || target != "SYSTEM.DEAD.LETTER.QUEUE" || !toDLQ {
+		t.Fatalf("without BOQNAME: %q, %v, %v", target, toDLQ, err)
+	}
+}
+
+func TestWithDeadLetterHeader(t *testing.T) {
+	md := ibmmq.NewMQMD()
+	md.Format = ibmmq.MQFMT_STRING
+	md.MsgId = []byte("0123456789abcdef01234567")
+	data := withDeadLetterHeader(md, []byte("hello"), "APP.Q", "QM1")
+
+	// The MQMD now describes the DLH, which chains to the original format.
+	dl, dlh, payload, err := parseDeadLetter(md, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dl.Reason != ibmmq.MQRC_BACKOUT_THRESHOLD_REACHED {
+		t.Fatalf("Reason = %d", dl.Reason)
+	}
+	if dl.DestQName != "APP.Q" || dl.DestQMgrName != "QM1" {
+		t.Fatalf("destination = %s on %s", dl.DestQName, dl.DestQMgrName)
+	}
+	if dl.Format != strings.TrimSpace(ibmmq.MQFMT_STRING) || strings.TrimSpace(dlh.Format) != strings.TrimSpace(ibmmq.MQFMT_STRING) {
+		t.Fatalf("DLH format = %q", dl.Format)
+	}
+	if string(payload) != "hello" {
+		t.Fatalf("payload = %q", payload)
+	}
+}
+
+func TestCountBackout(t *testing.T) {
+	g := &Gateway{}
+	g.countBackout(func(s *BackoutStats) { s.MovedToBackoutQ++ })
+	g.countBackout(func(s *BackoutStats) { s.MovedToDLQ++ })
+	g.countBackout(func(s *BackoutStats) { s.MovedToDLQ++ })
+	g.countBackout(func(s *BackoutStats) { s.Failures++ })
+	if got := g.BackoutStats(); got != (BackoutStats{MovedToBackoutQ: 1, MovedToDLQ: 2, Failures: 1}) {
+		t.Fatalf("stats = %+v", got)
+	}
+}
+
+func TestReplayQMgr(t *testing.T) {
+	g := &Gateway{}
+	g.QMgr.Name = "QM1                                             "
+	for _, tc := range []struct {
+		dest       string
+		overridden bool
+		want       string
+	}{
+		{"", false, ""},
+		{"QM1", false, ""},
+		{"QM2   ", false, "QM2"},
+		// An explicit destination is put locally whatever the DLH says.
+		{"QM2", true, ""},
+	} {
+		if got := g.replayQMgr(tc.dest, tc.overridden); got != tc.want {
+			t.Errorf("replayQMgr(%q, %v) = %q, want %q", tc.dest, tc.overridden, got, tc.want)
+		}
+	}
+}
+
+func TestBrowseQueue(t *testing.T) {
+	// A cursor answers for the queue it was opened on, so callers can check
+	// browse rights before using it.
+	g := &Gateway{browseSessionTTL: time.Minute, browseSessions: map[string]*browseSession{
+		"c1": {queue: "ORDERS", lastUsed: time.Now()},
+	}}
+	if q, err := g.BrowseQueue("c1"); err != nil || q != "ORDERS" {
+		t.Fatalf("BrowseQueue(c1) = %q, %v", q, err)
+	}
+	if _, err := g.BrowseQueue("c2"); !errors.Is(err, ErrBrowseNotFound) {
+		t.Fatalf("unknown cursor: %v", err)
+	}
+	if _, _, err := g.BrowseNextMessage("c2", GetOptions{}); !errors.Is(err, ErrBrowseNotFound) {
+		t.Fatalf("BrowseNextMessage on unknown cursor: %v", err)
+	}
+	if err := g.EndBrowse("c2"); !errors.Is(err, ErrBrowseNotFound) {
+		t.Fatalf("EndBrowse on unknown cursor: %v", err)
+	}
+}
+
+func TestScreenPoisonPut(t *testing.T) {
+	// The move goes through the caller's put, so it joins the caller's unit
+	// of work on whichever connection that is.
+	g := &Gateway{backoutPolicies: map[string]backoutPolicy{
+		"APP.Q": {threshold: 2, requeueQ: "APP.BACKOUT", loadedAt: time.Now()},
+	}}
+	var puts []string
+	put := func(queueName string, md *ibmmq.MQMD, data []byte) error {
+		puts = append(puts, queueName+":"+string(data))
+		return nil
+	}
+
+	md := ibmmq.NewMQMD()
+	md.BackoutCount = 1
+	if moved, err := g.screenPoison("APP.Q", md, []byte("x"), put); moved || err != nil || len(puts) != 0 {
+		t.Fatalf("below threshold: moved %v, %v, puts %v", moved, err, puts)
+	}
+	md.BackoutCount = 2
+	if moved, err := g.screenPoison("APP.Q", md, []byte("x"), put); !moved || err != nil {
+		t.Fatalf("at threshold: moved %v, %v", moved, err)
+	}
+	if len(puts) != 1 || puts[0] != "APP.BACKOUT:x" {
+		t.Fatalf("puts = %v", puts)
+	}
+
+	failing := func(string, *ibmmq.MQMD, []byte) error { return errors.New("MQPUT failed") }
+	if moved, err := g.screenPoison("APP.Q", md, []byte("x"), failing); moved || err == nil {
+		t.Fatalf("failed put: moved %v, %v", moved, err)
+	}
+	if s := g.BackoutStats(); s.MovedToBackoutQ != 1 || s.Failures != 1 {
+		t.Fatalf("stats = %+v", s)
+	}
+}
@@ -0,0 +1,619 @@
+package mqcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// Scheduled messages wait on a staging queue (MQ_SCHEDULE_QUEUE) as persistent
+// messages whose due time and target queue are message properties. The
+// scheduler moves each due message to its target in one unit of work, so a
+// restart neither loses nor duplicates it, and several gateways may share one
+// staging queue.
+const (
+	// scheduleDueProperty is the due time in Unix milliseconds (int64).
+	scheduleDueProperty = "gwDueTime"
+	// scheduleQueueProperty is the target queue name.
+	scheduleQueueProperty = "gwTargetQueue"
+)
+
+// ErrSchedulingDisabled is returned when MQ_SCHEDULE_QUEUE is not set.
+var ErrSchedulingDisabled = errors.New("scheduled delivery is disabled; set MQ_SCHEDULE_QUEUE")
+
+// ErrScheduleNotFound is returned when a scheduled message is not pending,
+// e.g. because it was already delivered or cancelled.
+var ErrScheduleNotFound = errors.New("scheduled message not found")
+
+// ScheduledMessage is a message waiting on the staging queue.
+type ScheduledMessage struct {
+	// ID is the MsgId, which the message keeps when it is delivered.
+	ID []byte
+	// Queue is the target queue.
+	Queue        string
+	DueAt        time.Time
+	ReplyToQueue string
+	Payload      string
+}
+
+// scheduledMessage reads the scheduling properties of a staged message from mh.
+func scheduledMessage(md *ibmmq.MQMD, mh ibmmq.MQMessageHandle, payload []byte) (*ScheduledMessage, error) {
+	impo := ibmmq.NewMQIMPO()
+	pd := ibmmq.NewMQPD()
+	_, due, err := mh.InqMP(impo, pd, scheduleDueProperty)
+	if err != nil {
+		return nil, fmt.Errorf("MQINQMP(%s): %w", scheduleDueProperty, err)
+	}
+	dueMs, ok := due.(int64)
+	if !ok {
+		return nil, fmt.Errorf("property %s has type %T, want int64", scheduleDueProperty, due)
+	}
+	_, queue, err := mh.InqMP(impo, pd, scheduleQueueProperty)
+	if err != nil {
+		return nil, fmt.Errorf("MQINQMP(%s): %w", scheduleQueueProperty, err)
+	}
+	queueName, ok := queue.(string)
+	if !ok || queueName == "" {
+		return nil, fmt.Errorf("property %s is not a queue name", scheduleQueueProperty)
+	}
+	return &ScheduledMessage{
+		ID:           md.MsgId,
+		Queue:        queueName,
+		DueAt:        time.UnixMilli(dueMs),
+		ReplyToQueue: strings.TrimSpace(md.ReplyToQ),
+		Payload:      string(payload),
+	}, nil
+}
+
+// DueTime resolves a request's due time from either an RFC 3339 deliverAt or
+// a delay in milliseconds after now; exactly one must be set.
+func DueTime(deliverAt string, delayMs int64, now time.Time) (time.Time, error) {
+	switch {
+	case deliverAt != "" && delayMs != 0:
+		return time.Time{}, errors.New("set only one of deliver_at and delay_ms")
+	case deliverAt != "":
+		t, err := time.Parse(time.RFC3339, deliverAt)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("deliver_at: %w", err)
+		}
+		return t, nil
+	case delayMs < 0:
+		return time.Time{}, errors.New("delay_ms must not be negative")
+	case delayMs > 0:
+		return now.Add(time.Duration(delayMs) * time.Millisecond), nil
+	default:
+		return time.Time{}, errors.New("deliver_at or delay_ms required")
+	}
+}
+
+// queueSelector selects the staged messages for queueName.
+func queueSelector(queueName string) string {
+	return fmt.Sprintf("%s = %s", scheduleQueueProperty, selectorString(queueName))
+}
+
+// dueSelector selects the staged messages due at now, except those for the
+// queues in skip.
+func dueSelector(now time.Time, skip []string) string {
+	sel := fmt.Sprintf("%s <= %d", scheduleDueProperty, now.UnixMilli())
+	for _, q := range skip {
+		sel += fmt.Sprintf(" AND %s <> %s", scheduleQueueProperty, selectorString(q))
+	}
+	return sel
+}
+
+// selectorString quotes s as a string literal in a message selector.
+func selectorString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// Schedule stages message for delivery to queueName at dueAt and returns its
+// MsgId, which identifies the schedule and is kept on delivery. A dueAt in the
+// past is delivered on the next scheduler pass.
+func (g *Gateway) Schedule(queueName, message string, dueAt time.Time, opts PutOptions) ([]byte, error) {
+	if g.scheduleQueue == "" {
+		return nil, ErrSchedulingDisabled
+	}
+	if strings.EqualFold(queueName, g.scheduleQueue) {
+		return nil, fmt.Errorf("cannot schedule to the staging queue")
+	}
+	// Validate now; delivery must not fail on a payload the caller could fix.
+	if err := g.validate(queueName, message); err != nil {
+		return nil, err
+	}
+	md := ibmmq.NewMQMD()
+	if err := opts.apply(md); err != nil {
+		return nil, err
+	}
+	// The staged copy must survive a queue manager restart.
+	md.Persistence = ibmmq.MQPER_PERSISTENT
+
+	// Opening the target now reports an unknown queue to the caller instead
+	// of dead-lettering the message when it falls due.
+	h, err := g.acquireHandle(queueName, ibmmq.MQOO_OUTPUT)
+	if err != nil {
+		return nil, fmt.Errorf("MQOPEN(%s): %w", queueName, err)
+	}
+	g.releaseHandle(h, nil)
+
+	err = g.withStaging(func(qMgr ibmmq.MQQueueManager) error {
+		mh, err := qMgr.CrtMH(ibmmq.NewMQCMHO())
+		if err != nil {
+			return fmt.Errorf("MQCRTMH: %w", err)
+		}
+		defer mh.DltMH(ibmmq.NewMQDMHO())
+		smpo := ibmmq.NewMQSMPO()
+		pd := ibmmq.NewMQPD()
+		if err := mh.SetMP(smpo, scheduleDueProperty, pd, dueAt.UnixMilli()); err != nil {
+			return fmt.Errorf("MQSETMP(%s): %w", scheduleDueProperty, err)
+		}
+		if err := mh.SetMP(smpo, scheduleQueueProperty, pd, queueName); err != nil {
+			return fmt.Errorf("MQSETMP(%s): %w", scheduleQueueProperty, err)
+		}
+
+		od := ibmmq.NewMQOD()
+		od.ObjectType = ibmmq.MQOT_Q
+		od.ObjectName = g.scheduleQueue
+		qObj, err := qMgr.Open(od, ibmmq.MQOO_OUTPUT|ibmmq.MQOO_FAIL_IF_QUIESCING)
+		if err != nil {
+			return fmt.Errorf("MQOPEN(%s): %w", g.scheduleQueue, err)
+		}
+		defer qObj.Close(0)
+		pmo := ibmmq.NewMQPMO()
+		pmo.Options = ibmmq.MQPMO_NO_SYNCPOINT | ibmmq.MQPMO_FAIL_IF_QUIESCING
+		pmo.OriginalMsgHandle = mh
+		if err := qObj.Put(md, pmo, []byte(message)); err != nil {
+			return fmt.Errorf("MQPUT(%s): %w", g.scheduleQueue, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return md.MsgId, nil
+}
+
+// withStaging runs fn on the staging connection, connecting on first use.
+// Message handles belong to the connection that created them, so the
+// staging calls keep theirs off the shared one; they take turns on it. After
+// an MQ error the connection is dropped and the next call reconnects.
+func (g *Gateway) withStaging(fn func(qMgr ibmmq.MQQueueManager) error) error {
+	g.stagingMu.Lock()
+	defer g.stagingMu.Unlock()
+	if g.staging == nil {
+		qMgr, err := g.connect()
+		if err != nil {
+			return fmt.Errorf("MQCONNX: %w", err)
+		}
+		g.staging = &qMgr
+	}
+	err := fn(*g.staging)
+	if ReasonCode(err) != 0 {
+		_ = g.staging.Disc()
+		g.staging = nil
+	}
+	return err
+}
+
+// ListScheduled browses up to maxMessages pending messages for queueName in
+// staging queue order, which is not necessarily due order.
+func (g *Gateway) ListScheduled(queueName string, maxMessages int, maxBytes int) ([]ScheduledMessage, error) {
+	if g.scheduleQueue == "" {
+		return nil, ErrSchedulingDisabled
+	}
+	if maxMessages <= 0 || maxMessages > MaxBatchSize {
+		maxMessages = MaxBatchSize
+	}
+	if maxBytes <= 0 {
+		maxBytes = 64 * 1024
+	}
+
+	var out []ScheduledMessage
+	err := g.withStaging(func(qMgr ibmmq.MQQueueManager) error {
+		od := ibmmq.NewMQOD()
+		od.ObjectType = ibmmq.MQOT_Q
+		od.ObjectName = g.scheduleQueue
+		od.SelectionString = queueSelector(queueName)
+		qObj, err := qMgr.Open(od, ibmmq.MQOO_BROWSE|ibmmq.MQOO_FAIL_IF_QUIESCING)
+		if err != nil {
+			return fmt.Errorf("MQOPEN(%s): %w", g.scheduleQueue, err)
+		}
+		defer qObj.Close(0)
+
+		mh, err := qMgr.CrtMH(ibmmq.NewMQCMHO())
+		if err != nil {
+			return fmt.Errorf("MQCRTMH: %w", err)
+		}
+		defer mh.DltMH(ibmmq.NewMQDMHO())
+
+		browse := ibmmq.MQGMO_BROWSE_FIRST
+		buf := make([]byte, maxBytes)
+		for len(out) < maxMessages {
+			md := ibmmq.NewMQMD()
+			gmo := ibmmq.NewMQGMO()
+			gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_NO_WAIT | ibmmq.MQGMO_CONVERT |
+				ibmmq.MQGMO_PROPERTIES_IN_HANDLE | browse
+			gmo.MsgHandle = mh
+			browse = ibmmq.MQGMO_BROWSE_NEXT
+
+			msgLen, err := qObj.Get(md, gmo, buf)
+			if err != nil {
+				if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
+					return nil
+				}
+				return fmt.Errorf("MQGET(BROWSE): %w", err)
+			}
+			sm, err := scheduledMessage(md, mh, buf[:msgLen])
+			if err != nil {
+				// Messages the gateway did not stage are left alone and skipped.
+				continue
+			}
+			out = append(out, *sm)
+		}
+		return nil
+	})
+	return out, err
+}
+
+// CancelScheduled removes the pending message id for queueName and returns it.
+// It returns ErrScheduleNotFound when the message was already delivered,
+// cancelled, or is scheduled for another queue.
+func (g *Gateway) CancelScheduled(queueName string, id []byte) (*ScheduledMessage, error) {
+	if g.scheduleQueue == "" {
+		return nil, ErrSchedulingDisabled
+	}
+	if len(id) != int(ibmmq.MQ_MSG_ID_LENGTH) {
+		return nil, fmt.Errorf("schedule_id must be %d bytes", ibmmq.MQ_MSG_ID_LENGTH)
+	}
+
+	var sm *ScheduledMessage
+	err := g.withStaging(func(qMgr ibmmq.MQQueueManager) error {
+		od := ibmmq.NewMQOD()
+		od.ObjectType = ibmmq.MQOT_Q
+		od.ObjectName = g.scheduleQueue
+		od.SelectionString = queueSelector(queueName)
+		qObj, err := qMgr.Open(od, ibmmq.MQOO_INPUT_AS_Q_DEF|ibmmq.MQOO_FAIL_IF_QUIESCING)
+		if err != nil {
+			return fmt.Errorf("MQOPEN(%s): %w", g.scheduleQueue, err)
+		}
+		defer qObj.Close(0)
+
+		mh, err := qMgr.CrtMH(ibmmq.NewMQCMHO())
+		if err != nil {
+			return fmt.Errorf("MQCRTMH: %w", err)
+		}
+		defer mh.DltMH(ibmmq.NewMQDMHO())
+
+		md, payload, err := getStaged(qObj, mh, ibmmq.MQGMO_NO_SYNCPOINT, id)
+		if err != nil {
+			if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
+				return ErrScheduleNotFound
+			}
+			return fmt.Errorf("MQGET(%s): %w", g.scheduleQueue, err)
+		}
+		sm, err = scheduledMessage(md, mh, payload)
+		return err
+	})
+	return sm, err
+}
+
+// getStaged gets the next message (or the one with msgID) from the staging
+// queue with its properties in mh, growing the buffer to fit the message.
+func getStaged(qObj ibmmq.MQObject, mh ibmmq.MQMessageHandle, syncpoint int32, msgID []byte) (*ibmmq.MQMD, []byte, error) {
+	buf := make([]byte, 64*1024)
+	for {
+		md := ibmmq.NewMQMD()
+		md.Version = ibmmq.MQMD_VERSION_2
+		gmo := ibmmq.NewMQGMO()
+		gmo.Version = ibmmq.MQGMO_VERSION_2
+		gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_NO_WAIT | ibmmq.MQGMO_PROPERTIES_IN_HANDLE | syncpoint
+		gmo.MsgHandle = mh
+		gmo.MatchOptions = ibmmq.MQMO_NONE
+		if msgID != nil {
+			gmo.MatchOptions = ibmmq.MQMO_MATCH_MSG_ID
+			md.MsgId = msgID
+		}
+
+		msgLen, err := qObj.Get(md, gmo, buf)
+		if err == nil {
+			return md, buf[:msgLen], nil
+		}
+		// A failed truncated get leaves the message on the queue.
+		if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_TRUNCATED_MSG_FAILED && msgLen > len(buf) {
+			buf = make([]byte, msgLen)
+			continue
+		}
+		return nil, nil, err
+	}
+}
+
+// DeliverDue puts every staged message due at now to its target queue and
+// returns how many were delivered. Each message is moved in its own unit of
+// work on a connection of its own, so delivery neither holds syncMu nor
+// blocks calls on the shared connection. A message whose target does not
+// exist or refuses it for good is dead-lettered with that reason code, so DLQ
+// replay can deliver it later; one whose target is only full or
+// put-inhibited stays staged for the next pass.
+func (g *Gateway) DeliverDue(now time.Time) (int, error) {
+	if g.scheduleQueue == "" {
+		return 0, ErrSchedulingDisabled
+	}
+	qMgr, err := g.connect()
+	if err != nil {
+		return 0, fmt.Errorf("MQCONNX: %w", err)
+	}
+	defer qMgr.Disc()
+	return g.deliverDue(qMgr, now)
+}
+
+// deliveryPass is one DeliverDue pass. Its units of work belong to qMgr,
+// which nothing else uses meanwhile.
+type deliveryPass struct {
+	g    *Gateway
+	qMgr ibmmq.MQQueueManager
+	mh   ibmmq.MQMessageHandle
+	// targets holds the queues opened during the pass.
+	targets map[string]ibmmq.MQObject
+}
+
+// deliverDue runs a DeliverDue pass on qMgr. When a target cannot take a
+// message for now, its messages are left for the next pass and the pass goes
+// on with the other targets.
+func (g *Gateway) deliverDue(qMgr ibmmq.MQQueueManager, now time.Time) (int, error) {
+	mh, err := qMgr.CrtMH(ibmmq.NewMQCMHO())
+	if err != nil {
+		return 0, fmt.Errorf("MQCRTMH: %w", err)
+	}
+	defer mh.DltMH(ibmmq.NewMQDMHO())
+
+	p := &deliveryPass{g: g, qMgr: qMgr, mh: mh, targets: make(map[string]ibmmq.MQObject)}
+	defer func() {
+		for _, t := range p.targets {
+			_ = t.Close(0)
+		}
+	}()
+
+	n := 0
+	var skip []string
+	for {
+		delivered, err := p.deliverFrom(dueSelector(now, skip))
+		n += delivered
+		var blocked *blockedTargetError
+		if !errors.As(err, &blocked) {
+			return n, err
+		}
+		slog.Warn("[mqcore] scheduled delivery deferred",
+			"queue", blocked.queue,
+			"error", blocked.err,
+			"id", "2e9b7a41-c6d8-4f03-9a5e-71b4d0c8f2a6")
+		skip = append(skip, blocked.queue)
+	}
+}
+
+// blockedTargetError reports a target that refused a due message for now,
+// e.g. because it is full or put-inhibited.
+type blockedTargetError struct {
+	queue string
+	err   error
+}
+
+func (e *blockedTargetError) Error() string { return e.err.Error() }
+func (e *blockedTargetError) Unwrap() error { return e.err }
+
+// deliverFrom delivers the due messages that selector picks until none is
+// left or one fails, and returns how many it delivered.
+func (p *deliveryPass) deliverFrom(selector string) (int, error) {
+	od := ibmmq.NewMQOD()
+	od.ObjectType = ibmmq.MQOT_Q
+	od.ObjectName = p.g.scheduleQueue
+	od.SelectionString = selector
+	qObj, err := p.qMgr.Open(od, ibmmq.MQOO_INPUT_AS_Q_DEF|ibmmq.MQOO_FAIL_IF_QUIESCING)
+	if err != nil {
+		return 0, fmt.Errorf("MQOPEN(%s): %w", p.g.scheduleQueue, err)
+	}
+	defer qObj.Close(0)
+
+	n := 0
+	for {
+		delivered, err := p.deliverOne(qObj)
+		if err != nil || !delivered {
+			return n, err
+		}
+		n++
+	}
+}
+
+// deliverOne moves the next due message and reports whether there was one.
+func (p *deliveryPass) deliverOne(qObj ibmmq.MQObject) (bool, error) {
+	g := p.g
+	md, payload, err := getStaged(qObj, p.mh, ibmmq.MQGMO_SYNCPOINT, nil)
+	if err != nil {
+		if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
+			return false, nil
+		}
+		return false, fmt.Errorf("MQGET(%s): %w", g.scheduleQueue, err)
+	}
+
+	// A poison message keeps its scheduling properties, so replaying it to
+	// the staging queue schedules it again.
+	moved, err := g.screenPoison(g.scheduleQueue, md, payload, p.put)
+	if err != nil {
+		_ = p.qMgr.Back()
+		return false, err
+	}
+	if !moved {
+		if err := p.putScheduled(md, payload); err != nil {
+			_ = p.qMgr.Back()
+			return false, err
+		}
+	}
+	if err := p.qMgr.Cmit(); err != nil {
+		return false, fmt.Errorf("MQCMIT: %w", err)
+	}
+	return true, nil
+}
+
+// put puts data to queueName inside the pass's unit of work with the
+// properties in mh, opening the queue on first use.
+func (p *deliveryPass) put(queueName string, md *ibmmq.MQMD, data []byte) error {
+	tObj, ok := p.targets[queueName]
+	if !ok {
+		od := ibmmq.NewMQOD()
+		od.ObjectType = ibmmq.MQOT_Q
+		od.ObjectName = queueName
+		var err error
+		tObj, err = p.qMgr.Open(od, ibmmq.MQOO_OUTPUT|ibmmq.MQOO_FAIL_IF_QUIESCING)
+		if err != nil {
+			return fmt.Errorf("MQOPEN(%s): %w", queueName, err)
+		}
+		p.targets[queueName] = tObj
+	}
+	pmo := ibmmq.NewMQPMO()
+	pmo.Options = ibmmq.MQPMO_SYNCPOINT | ibmmq.MQPMO_FAIL_IF_QUIESCING
+	pmo.OriginalMsgHandle = p.mh
+	if err := tObj.Put(md, pmo, data); err != nil {
+		// The handle may be stale, e.g. after the queue was deleted.
+		_ = tObj.Close(0)
+		delete(p.targets, queueName)
+		return fmt.Errorf("MQPUT(%s): %w", queueName, err)
+	}
+	return nil
+}
+
+// putScheduled puts a got staged message to its target inside the current
+// unit of work, or to the dead-letter queue when the target rejects it for
+// good. A target that refuses it for now yields a *blockedTargetError, so the
+// caller backs out and the message waits for the next pass.
+func (p *deliveryPass) putScheduled(md *ibmmq.MQMD, payload []byte) error {
+	sm, err := scheduledMessage(md, p.mh, payload)
+	if err != nil {
+		return p.deadLetter(md, payload, "", ReasonCode(err), err)
+	}
+	// The target sees the message as if it had been put now, without the
+	// scheduling properties and with its queue's default persistence.
+	dmpo := ibmmq.NewMQDMPO()
+	for _, name := range []string{scheduleDueProperty, scheduleQueueProperty} {
+		if err := p.mh.DltMP(dmpo, name); err != nil {
+			return fmt.Errorf("MQDLTMP(%s): %w", name, err)
+		}
+	}
+	md.Persistence = ibmmq.MQPER_PERSISTENCE_AS_Q_DEF
+
+	if err := p.put(sm.Queue, md, payload); err != nil {
+		if !refusedForGood(err) {
+			return &blockedTargetError{queue: sm.Queue, err: err}
+		}
+		return p.deadLetter(md, payload, sm.Queue, ReasonCode(err), err)
+	}
+	return nil
+}
+
+// refusedForGood reports whether err means a target will not take a message
+// however long delivery waits, as opposed to a full or put-inhibited queue
+// or a connection problem.
+func refusedForGood(err error) bool {
+	switch ReasonCode(err) {
+	case ibmmq.MQRC_UNKNOWN_OBJECT_NAME,
+		ibmmq.MQRC_UNKNOWN_ALIAS_BASE_Q,
+		ibmmq.MQRC_UNKNOWN_REMOTE_Q_MGR,
+		ibmmq.MQRC_Q_DELETED,
+		ibmmq.MQRC_NOT_AUTHORIZED,
+		ibmmq.MQRC_MSG_TOO_BIG_FOR_Q,
+		ibmmq.MQRC_MSG_TOO_BIG_FOR_Q_MGR,
+		ibmmq.MQRC_PERSISTENT_NOT_ALLOWED:
+		return true
+	}
+	return false
+}
+
+// deadLetter puts an undeliverable staged message to the dead-letter queue
+// behind an MQDLH naming its target, inside the current unit of work. It
+// returns cause when the message cannot be dead-lettered either, so the caller
+// backs out and the message is retried on the next pass.
+func (p *deliveryPass) deadLetter(md *ibmmq.MQMD, payload []byte, target string, reason int32, cause error) error {
+	if reason == 0 {
+		// Only MQ errors say anything about the target; retry the rest.
+		return cause
+	}
+	dlq, err := p.g.deadLetterQueue()
+	if err != nil || dlq == "" {
+		return cause
+	}
+	msgID := md.MsgId
+	// NewMQDLH copies format/encoding from md and rewrites md to describe the DLH.
+	dlh := ibmmq.NewMQDLH(md)
+	dlh.Reason = reason
+	dlh.DestQName = target
+	dlh.DestQMgrName = p.g.QMgr.Name
+
+	if err := p.put(dlq, md, append(dlh.Bytes(), payload...)); err != nil {
+		return cause
+	}
+	slog.Warn("[mqcore] scheduled message dead-lettered",
+		"queue", target,
+		"dead_letter_queue", dlq,
+		"reason", reason,
+		"error", cause,
+		"msg_id", FormatID(msgID),
+		"id", "d51b320c-20a8-4be4-b7ef-dd68297b9c4f")
+	return nil
+}
+
+// RunScheduler delivers due messages every MQ_SCHEDULE_INTERVAL until ctx is
+// done. It returns at once when scheduling is disabled. The scheduler keeps
+// one connection of its own across passes and replaces it after a failed one.
+func (g *Gateway) RunScheduler(ctx context.Context) {
+	if g.scheduleQueue == "" {
+		return
+	}
+	slog.Info("[mqcore] scheduler started",
+		"staging_queue", g.scheduleQueue,
+		"interval", g.scheduleInterval,
+		"id", "43c19270-f559-40f4-809d-240b733770c3")
+
+	var qMgr *ibmmq.MQQueueManager
+	defer func() {
+		if qMgr != nil {
+			_ = qMgr.Disc()
+		}
+	}()
+	ticker := time.NewTicker(g.scheduleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			var n int
+			var err error
+			if qMgr == nil {
+				var conn ibmmq.MQQueueManager
+				if conn, err = g.connect(); err == nil {
+					qMgr = &conn
+				} else {
+					err = fmt.Errorf("MQCONNX: %w", err)
+				}
+			}
+			if qMgr != nil {
+				if n, err = g.deliverDue(*qMgr, now); err != nil {
+					_ = qMgr.Disc()
+					qMgr = nil
+				}
+			}
+			if n > 0 {
+				slog.Info("[mqcore] scheduled messages delivered",
+					"count", n,
+					"id", "6b1f92e9-1e4b-4d2c-92fc-ef31b58fa81a")
+			}
+			if err != nil {
+				slog.Error("[mqcore] scheduled delivery failed",
+					"error", err,
+					"id", "7f6d6a73-4ea6-42f1-b153-d7e515ce2209")
+			}
+		}
+	}
+}
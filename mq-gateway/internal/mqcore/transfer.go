@@ -0,0 +1,193 @@
+package mqcore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// TransferOptions controls TransferMessages.
+type TransferOptions struct {
+	Source      string
+	Destination string
+	// Copy browses the source and leaves its messages in place. Otherwise each
+	// message is got and put in the same unit of work, so a move never loses
+	// or duplicates a message.
+	Copy bool
+	// MsgID and CorrelID, when set, only transfer messages with that id.
+	MsgID    []byte
+	CorrelID []byte
+	// Selector is an SQL92 message selector on message properties.
+	Selector string
+	// BatchSize is how many messages are committed per unit of work (default 100).
+	BatchSize int
+	// MaxMessages caps the transfer; 0 transfers every matching message.
+	MaxMessages int
+	// MaxBytes is the largest message accepted (default 4 MiB).
+	MaxBytes int
+	// Progress, when set, is called after each committed batch.
+	Progress func(TransferResult)
+}
+
+// TransferResult reports a move or copy.
+type TransferResult struct {
+	// Transferred counts committed messages only.
+	Transferred int
+	// ContextPreserved reports whether the original identity and origin
+	// context was kept, which needs setall authority on the destination.
+	// Without it the destination messages get the gateway's default context.
+	ContextPreserved bool
+}
+
+// TransferMessages moves (or with opts.Copy, copies) messages from one queue
+// to another without data conversion, keeping their MsgId and properties. On
+// error the current batch is backed out and the result counts the batches
+// already committed. The transfer runs on a connection of its own, so its
+// units of work, however many, never hold up the gateway's shared one.
+func (g *Gateway) TransferMessages(opts TransferOptions) (TransferResult, error) {
+	var res TransferResult
+	if opts.Source == "" || opts.Destination == "" {
+		return res, fmt.Errorf("source and destination queues required")
+	}
+	if strings.EqualFold(opts.Source, opts.Destination) {
+		return res, fmt.Errorf("source and destination must differ")
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = exportCommitEvery
+	}
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = 4 << 20
+	}
+
+	qMgr, err := g.connect()
+	if err != nil {
+		return res, fmt.Errorf("MQCONNX: %w", err)
+	}
+	defer qMgr.Disc()
+
+	od := ibmmq.NewMQOD()
+	od.ObjectType = ibmmq.MQOT_Q
+	od.ObjectName = opts.Source
+	od.SelectionString = opts.Selector
+	openOpts := ibmmq.MQOO_INPUT_AS_Q_DEF | ibmmq.MQOO_FAIL_IF_QUIESCING
+	if opts.Copy {
+		openOpts = ibmmq.MQOO_BROWSE | ibmmq.MQOO_FAIL_IF_QUIESCING
+	}
+	src, err := qMgr.Open(od, openOpts)
+	if err != nil {
+		return res, fmt.Errorf("MQOPEN(%s): %w", opts.Source, err)
+	}
+	defer src.Close(0)
+
+	dst, preserved, err := openTransferTarget(qMgr, opts.Destination)
+	if err != nil {
+		return res, err
+	}
+	defer dst.Close(0)
+	res.ContextPreserved = preserved
+
+	mh, err := qMgr.CrtMH(ibmmq.NewMQCMHO())
+	if err != nil {
+		return res, fmt.Errorf("MQCRTMH: %w", err)
+	}
+	defer mh.DltMH(ibmmq.NewMQDMHO())
+
+	pending := 0
+	commit := func() error {
+		if err := qMgr.Cmit(); err != nil {
+			return fmt.Errorf("MQCMIT: %w", err)
+		}
+		res.Transferred += pending
+		pending = 0
+		if opts.Progress != nil {
+			opts.Progress(res)
+		}
+		return nil
+	}
+
+	browse := ibmmq.MQGMO_BROWSE_FIRST
+	buf := make([]byte, maxBytes)
+	for opts.MaxMessages <= 0 || res.Transferred+pending < opts.MaxMessages {
+		md := ibmmq.NewMQMD()
+		md.Version = ibmmq.MQMD_VERSION_2
+		gmo := ibmmq.NewMQGMO()
+		gmo.Version = ibmmq.MQGMO_VERSION_2
+		gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_NO_WAIT | ibmmq.MQGMO_PROPERTIES_IN_HANDLE
+		if opts.Copy {
+			gmo.Options |= browse
+			browse = ibmmq.MQGMO_BROWSE_NEXT
+		} else {
+			gmo.Options |= ibmmq.MQGMO_SYNCPOINT
+		}
+		gmo.MsgHandle = mh
+		gmo.MatchOptions = ibmmq.MQMO_NONE
+		if opts.MsgID != nil {
+			gmo.MatchOptions |= ibmmq.MQMO_MATCH_MSG_ID
+			md.MsgId = opts.MsgID
+		}
+		if opts.CorrelID != nil {
+			gmo.MatchOptions |= ibmmq.MQMO_MATCH_CORREL_ID
+			md.CorrelId = opts.CorrelID
+		}
+
+		msgLen, err := src.Get(md, gmo, buf)
+		if err != nil {
+			if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
+				break
+			}
+			_ = qMgr.Back()
+			return res, fmt.Errorf("MQGET(%s): %w", opts.Source, err)
+		}
+
+		// The MQMD is put back as got; without SET_ALL_CONTEXT the queue
+		// manager ignores its context fields.
+		pmo := ibmmq.NewMQPMO()
+		pmo.Options = ibmmq.MQPMO_SYNCPOINT | ibmmq.MQPMO_FAIL_IF_QUIESCING
+		if preserved {
+			pmo.Options |= ibmmq.MQPMO_SET_ALL_CONTEXT
+		}
+		pmo.OriginalMsgHandle = mh
+		if err := dst.Put(md, pmo, buf[:msgLen]); err != nil {
+			_ = qMgr.Back()
+			return res, fmt.Errorf("MQPUT(%s): %w", opts.Destination, err)
+		}
+
+		if pending++; pending == batchSize {
+			if err := commit(); err != nil {
+				return res, err
+			}
+		}
+	}
+	if pending > 0 {
+		if err := commit(); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// openTransferTarget opens queueName for output, asking for MQOO_SET_ALL_CONTEXT
+// first and falling back to default context when the gateway's user lacks it.
+func openTransferTarget(qMgr ibmmq.MQQueueManager, queueName string) (ibmmq.MQObject, bool, error) {
+	open := func(opts int32) (ibmmq.MQObject, error) {
+		od := ibmmq.NewMQOD()
+		od.ObjectType = ibmmq.MQOT_Q
+		od.ObjectName = queueName
+		return qMgr.Open(od, opts)
+	}
+	obj, err := open(ibmmq.MQOO_OUTPUT | ibmmq.MQOO_FAIL_IF_QUIESCING | ibmmq.MQOO_SET_ALL_CONTEXT)
+	if err == nil {
+		return obj, true, nil
+	}
+	if mqret, ok := err.(*ibmmq.MQReturn); !ok || mqret.MQRC != ibmmq.MQRC_NOT_AUTHORIZED {
+		return obj, false, fmt.Errorf("MQOPEN(%s): %w", queueName, err)
+	}
+	obj, err = open(ibmmq.MQOO_OUTPUT | ibmmq.MQOO_FAIL_IF_QUIESCING)
+	if err != nil {
+		return obj, false, fmt.Errorf("MQOPEN(%s): %w", queueName, err)
+	}
+	return obj, false, nil
+}
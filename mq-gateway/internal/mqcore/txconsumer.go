@@ -0,0 +1,279 @@
+package mqcore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// TxConsumer gets messages under syncpoint on a connection of its own, so the
+// caller can keep a unit of work open while it hands the message to a slow
+// system without holding syncMu on the shared connection. Each TxConsumer
+// must be used by one goroutine at a time.
+type TxConsumer struct {
+	g        *Gateway
+	qMgr     ibmmq.MQQueueManager
+	qObj     ibmmq.MQObject
+	mh       ibmmq.MQMessageHandle
+	queue    string
+	maxBytes int
+	// targets caches output handles opened by Forward and DeadLetter.
+	targets map[string]ibmmq.MQObject
+	// md and payload describe the message in the open unit of work; md is
+	// nil when there is none.
+	md      *ibmmq.MQMD
+	payload []byte
+}
+
+// OpenTxConsumer connects to the queue manager and opens queueName for input.
+func (g *Gateway) OpenTxConsumer(queueName string, maxBytes int) (*TxConsumer, error) {
+	if maxBytes <= 0 {
+		maxBytes = 4 << 20
+	}
+	qMgr, err := g.connect()
+	if err != nil {
+		return nil, fmt.Errorf("MQCONNX: %w", err)
+	}
+	od := ibmmq.NewMQOD()
+	od.ObjectType = ibmmq.MQOT_Q
+	od.ObjectName = queueName
+	qObj, err := qMgr.Open(od, ibmmq.MQOO_INPUT_AS_Q_DEF|ibmmq.MQOO_FAIL_IF_QUIESCING)
+	if err != nil {
+		_ = qMgr.Disc()
+		return nil, fmt.Errorf("MQOPEN(%s): %w", queueName, err)
+	}
+	mh, err := qMgr.CrtMH(ibmmq.NewMQCMHO())
+	if err != nil {
+		_ = qObj.Close(0)
+		_ = qMgr.Disc()
+		return nil, fmt.Errorf("MQCRTMH: %w", err)
+	}
+	return &TxConsumer{g: g, qMgr: qMgr, qObj: qObj, mh: mh, queue: queueName, maxBytes: maxBytes, targets: make(map[string]ibmmq.MQObject)}, nil
+}
+
+// Next gets the next message under syncpoint, waiting up to waitMs, and
+// returns nil when none arrived. The payload is converted to the gateway's
+// CCSID. The previous message must have been committed, backed out or moved.
+func (c *TxConsumer) Next(waitMs int) (*ArchivedMessage, error) {
+	if c.md != nil {
+		return nil, fmt.Errorf("previous message not committed or backed out")
+	}
+	md := ibmmq.NewMQMD()
+	md.Version = ibmmq.MQMD_VERSION_2
+	gmo := ibmmq.NewMQGMO()
+	gmo.Version = ibmmq.MQGMO_VERSION_4
+	gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_SYNCPOINT | ibmmq.MQGMO_CONVERT | ibmmq.MQGMO_PROPERTIES_IN_HANDLE
+	gmo.MsgHandle = c.mh
+	gmo.MatchOptions = ibmmq.MQMO_NONE
+	GetOptions{WaitMs: waitMs}.apply(md, gmo)
+
+	buf := make([]byte, c.maxBytes)
+	msgLen, err := c.qObj.Get(md, gmo, buf)
+	if err != nil {
+		if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
+			return nil, nil
+		}
+		// A truncated message stays on the queue; nothing else was got.
+		return nil, fmt.Errorf("MQGET(%s): %w", c.queue, err)
+	}
+	m, err := archivedMessage(md, c.mh, buf[:msgLen])
+	if err != nil {
+		_ = c.qMgr.Back()
+		return nil, err
+	}
+	c.md, c.payload = md, buf[:msgLen]
+	return m, nil
+}
+
+// Commit removes the current message from the queue.
+func (c *TxConsumer) Commit() error {
+	c.md, c.payload = nil, nil
+	if err := c.qMgr.Cmit(); err != nil {
+		return fmt.Errorf("MQCMIT: %w", err)
+	}
+	return nil
+}
+
+// Backout returns the current message to the queue and increments its
+// BackoutCount.
+func (c *TxConsumer) Backout() error {
+	c.md, c.payload = nil, nil
+	if err := c.qMgr.Back(); err != nil {
+		return fmt.Errorf("MQBACK: %w", err)
+	}
+	return nil
+}
+
+// MoveToBackout puts the current message to queueName and commits, so it is
+// removed from the source in the same unit of work. With queueName empty it
+// uses the source queue's BOQNAME, or else the dead-letter queue behind an
+// MQDLH. It returns the queue the message went to; on error the message is
+// backed out.
+func (c *TxConsumer) MoveToBackout(queueName string) (string, error) {
+	if c.md == nil {
+		return "", fmt.Errorf("no message to move")
+	}
+	target := queueName
+	if target == "" {
+		policy, err := c.g.backoutPolicy(c.queue)
+		if err != nil {
+			_ = c.Backout()
+			return "", err
+		}
+		target = policy.requeueQ
+	}
+	return c.moveTo(target, ibmmq.MQRC_BACKOUT_THRESHOLD_REACHED)
+}
+
+// DeadLetter is MoveToBackout for a message nothing wants: it goes to
+// queueName, or with queueName empty straight to the dead-letter queue behind
+// an MQDLH whose reason is ReasonNoRoute.
+func (c *TxConsumer) DeadLetter(queueName string) (string, error) {
+	if c.md == nil {
+		return "", fmt.Errorf("no message to dead-letter")
+	}
+	return c.moveTo(queueName, ReasonNoRoute)
+}
+
+// ReasonNoRoute is the MQDLH reason of messages dead-lettered because no
+// routing rule matched them; it is the first application-defined feedback code.
+const ReasonNoRoute = ibmmq.MQFB_APPL_FIRST
+
+// moveTo puts the current message to target, or to the dead-letter queue with
+// dlqReason when target is empty, and commits.
+func (c *TxConsumer) moveTo(target string, dlqReason int32) (string, error) {
+	md, data := c.md, c.payload
+	if target == "" {
+		dlq, err := c.g.deadLetterQueue()
+		if err != nil {
+			_ = c.Backout()
+			return "", err
+		}
+		if dlq == "" {
+			_ = c.Backout()
+			return "", fmt.Errorf("%s: no backout queue given and the queue manager has no DEADQ", c.queue)
+		}
+		target = dlq
+		// NewMQDLH copies format/encoding from md and rewrites md to describe the DLH.
+		dlh := ibmmq.NewMQDLH(md)
+		dlh.Reason = dlqReason
+		dlh.DestQName = c.queue
+		dlh.DestQMgrName = strings.TrimSpace(c.qMgr.Name)
+		data = append(dlh.Bytes(), data...)
+	}
+
+	if err := c.put(target, md, data); err != nil {
+		_ = c.Backout()
+		return target, err
+	}
+	return target, c.Commit()
+}
+
+// Transform edits a message as it is forwarded. Zero fields leave the
+// message unchanged.
+type Transform struct {
+	// SetProperties adds or replaces string properties.
+	SetProperties map[string]string `json:"set_properties,omitempty"`
+	// RemoveProperties deletes properties; missing ones are ignored.
+	RemoveProperties []string `json:"remove_properties,omitempty"`
+	Format           string   `json:"format,omitempty"`
+	Priority         *int32   `json:"priority,omitempty"`
+	Persistence      *int32   `json:"persistence,omitempty"`
+	// Expiry is in tenths of a second; -1 is unlimited.
+	Expiry       *int32 `json:"expiry,omitempty"`
+	ReplyToQueue string `json:"reply_to_queue,omitempty"`
+}
+
+// Forward applies t to the current message and puts it to every queue in
+// targets inside the open unit of work; the caller commits, or backs out to
+// undo every put together with the get. The MsgId and payload are kept.
+func (c *TxConsumer) Forward(t Transform, targets ...string) error {
+	if c.md == nil {
+		return fmt.Errorf("no message to forward")
+	}
+	dmpo := ibmmq.NewMQDMPO()
+	for _, name := range t.RemoveProperties {
+		if err := c.mh.DltMP(dmpo, name); err != nil {
+			if mqret, ok := err.(*ibmmq.MQReturn); !ok || mqret.MQRC != ibmmq.MQRC_PROPERTY_NOT_AVAILABLE {
+				return fmt.Errorf("MQDLTMP(%s): %w", name, err)
+			}
+		}
+	}
+	smpo := ibmmq.NewMQSMPO()
+	pd := ibmmq.NewMQPD()
+	for name, v := range t.SetProperties {
+		if err := c.mh.SetMP(smpo, name, pd, v); err != nil {
+			return fmt.Errorf("MQSETMP(%s): %w", name, err)
+		}
+	}
+
+	md := *c.md
+	if t.Format != "" {
+		md.Format = t.Format
+	}
+	if t.Priority != nil {
+		md.Priority = *t.Priority
+	}
+	if t.Persistence != nil {
+		md.Persistence = *t.Persistence
+	}
+	if t.Expiry != nil {
+		md.Expiry = *t.Expiry
+	}
+	if t.ReplyToQueue != "" {
+		md.ReplyToQ = t.ReplyToQueue
+		md.MsgType = ibmmq.MQMT_REQUEST
+	}
+	for _, target := range targets {
+		// MQPUT updates the descriptor, so each target gets a fresh copy.
+		tmd := md
+		if err := c.put(target, &tmd, c.payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// put puts data to queueName under syncpoint with the current message's
+// properties, opening the queue on first use.
+func (c *TxConsumer) put(queueName string, md *ibmmq.MQMD, data []byte) error {
+	tObj, ok := c.targets[queueName]
+	if !ok {
+		od := ibmmq.NewMQOD()
+		od.ObjectType = ibmmq.MQOT_Q
+		od.ObjectName = queueName
+		var err error
+		tObj, err = c.qMgr.Open(od, ibmmq.MQOO_OUTPUT|ibmmq.MQOO_FAIL_IF_QUIESCING)
+		if err != nil {
+			return fmt.Errorf("MQOPEN(%s): %w", queueName, err)
+		}
+		c.targets[queueName] = tObj
+	}
+	pmo := ibmmq.NewMQPMO()
+	pmo.Options = ibmmq.MQPMO_SYNCPOINT | ibmmq.MQPMO_FAIL_IF_QUIESCING
+	pmo.OriginalMsgHandle = c.mh
+	if err := tObj.Put(md, pmo, data); err != nil {
+		// The handle may be stale, e.g. after the queue was deleted.
+		_ = tObj.Close(0)
+		delete(c.targets, queueName)
+		return fmt.Errorf("MQPUT(%s): %w", queueName, err)
+	}
+	return nil
+}
+
+// Close backs out any open unit of work and disconnects.
+func (c *TxConsumer) Close() error {
+	if c.md != nil {
+		_ = c.Backout()
+	}
+	for _, tObj := range c.targets {
+		_ = tObj.Close(0)
+	}
+	_ = c.mh.DltMH(ibmmq.NewMQDMHO())
+	_ = c.qObj.Close(0)
+	if err := c.qMgr.Disc(); err != nil {
+		return fmt.Errorf("MQDISC: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package mqcore
+
+// PayloadValidator checks a payload before it is put to a queue; a non-nil
+// error rejects the put and is returned to the caller unchanged.
+type PayloadValidator interface {
+	Validate(queue string, payload []byte) error
+}
+
+// SetValidator installs v for Put, PutMessage, PutIdempotent, PutBatch,
+// PutGroup and Schedule. Import, DLQ replay and scheduled delivery move
+// messages that were already accepted, so they are not checked. Call it
+// before serving requests.
+func (g *Gateway) SetValidator(v PayloadValidator) {
+	g.validator = v
+}
+
+func (g *Gateway) validate(queueName, message string) error {
+	if g.validator == nil {
+		return nil
+	}
+	return g.validator.Validate(queueName, []byte(message))
+}
@@ -0,0 +1,305 @@
+// Package ratelimit applies token-bucket limits per principal, queue and
+// operation, and caps how many long-polling gets may wait at once.
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+)
+
+// sweepInterval limits how often idle, full buckets are dropped.
+const sweepInterval = time.Minute
+
+// Rule limits requests matching Principals, Queues and Operations (glob
+// patterns, as in the authorization policy) to Rate per second with bursts
+// of up to Burst.
+type Rule struct {
+	Principals []string         `json:"principals"`
+	Queues     []string         `json:"queues"`
+	Operations []auth.Operation `json:"operations"`
+	Rate       float64          `json:"rate"`
+	Burst      int              `json:"burst"`
+	// Per lists the dimensions that get their own bucket: "principal",
+	// "queue" and/or "operation". Empty shares one bucket across all
+	// matching requests, e.g. to protect a queue from every client combined.
+	Per []string `json:"per"`
+}
+
+// LongPollConfig caps concurrent gets and browses that wait at least ThresholdMs.
+type LongPollConfig struct {
+	ThresholdMs     int `json:"threshold_ms"`
+	MaxConcurrent   int `json:"max_concurrent"`
+	MaxPerPrincipal int `json:"max_per_principal"`
+}
+
+// Config is the rate-limit file format.
+type Config struct {
+	Rules    []Rule         `json:"rules"`
+	LongPoll LongPollConfig `json:"long_poll"`
+}
+
+// Stats counts rejections since start.
+type Stats struct {
+	Throttled        uint64 `json:"throttled"`
+	LongPollRejected uint64 `json:"long_poll_rejected"`
+	ActiveLongPolls  int    `json:"active_long_polls"`
+}
+
+// Error is returned for a rejected request.
+type Error struct {
+	Reason string
+	// RetryAfter is when the request would next be admitted.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s; retry after %s", e.Reason, e.RetryAfter.Round(time.Millisecond))
+}
+
+// RetryAfterSeconds rounds RetryAfter up to whole seconds (at least 1), as
+// used by the HTTP Retry-After header.
+func (e *Error) RetryAfterSeconds() int {
+	return max(1, int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	rate   float64
+	burst  float64
+}
+
+// refill adds the tokens earned since the last call.
+func (b *bucket) refill(now time.Time) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// Limiter enforces a Config. A nil *Limiter admits everything.
+type Limiter struct {
+	cfg Config
+
+	mu          sync.Mutex
+	buckets     map[string]*bucket
+	sweptAt     time.Time
+	longPolls   int
+	longPollsBy map[string]int
+	stats       Stats
+}
+
+// New validates cfg and fills in defaults.
+func New(cfg Config) (*Limiter, error) {
+	for i := range cfg.Rules {
+		r := &cfg.Rules[i]
+		if len(r.Principals) == 0 || len(r.Queues) == 0 || len(r.Operations) == 0 {
+			return nil, fmt.Errorf("rule %d needs principals, queues and operations", i)
+		}
+		if r.Rate <= 0 {
+			return nil, fmt.Errorf("rule %d: rate must be positive", i)
+		}
+		if r.Burst <= 0 {
+			r.Burst = max(1, int(math.Ceil(r.Rate)))
+		}
+		for _, p := range r.Per {
+			switch p {
+			case "principal", "queue", "operation":
+			default:
+				return nil, fmt.Errorf("rule %d: unknown per dimension %q", i, p)
+			}
+		}
+	}
+	lp := &cfg.LongPoll
+	if (lp.MaxConcurrent > 0 || lp.MaxPerPrincipal > 0) && lp.ThresholdMs <= 0 {
+		lp.ThresholdMs = 1000
+	}
+	return &Limiter{
+		cfg:         cfg,
+		buckets:     make(map[string]*bucket),
+		longPollsBy: make(map[string]int),
+	}, nil
+}
+
+// Load reads a JSON Config from path.
+func Load(path string) (*Limiter, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	l, err := New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return l, nil
+}
+
+// FromEnv loads the file named by RATE_LIMIT_FILE, or returns nil when unset.
+func FromEnv() (*Limiter, error) {
+	path := os.Getenv("RATE_LIMIT_FILE")
+	if path == "" {
+		return nil, nil
+	}
+	return Load(path)
+}
+
+func principalName(ctx context.Context) string {
+	if p, ok := auth.FromContext(ctx); ok && p.Name != "" {
+		return p.Name
+	}
+	return "anonymous"
+}
+
+func matchAny(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if auth.MatchGlob(p, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matches(principal string, op auth.Operation, queue string) bool {
+	if !matchAny(r.Principals, principal) || !matchAny(r.Queues, queue) {
+		return false
+	}
+	for _, o := range r.Operations {
+		if o == op || o == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// bucketKey identifies the bucket rule i uses for this request.
+func (r *Rule) bucketKey(i int, principal string, op auth.Operation, queue string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d", i)
+	for _, p := range r.Per {
+		switch p {
+		case "principal":
+			b.WriteString("|p=" + principal)
+		case "queue":
+			b.WriteString("|q=" + queue)
+		case "operation":
+			b.WriteString("|o=" + string(op))
+		}
+	}
+	return b.String()
+}
+
+// Allow takes one token from every bucket that applies to the caller in ctx.
+// Either all buckets admit the request or none is charged.
+func (l *Limiter) Allow(ctx context.Context, op auth.Operation, queue string) *Error {
+	if l == nil || len(l.cfg.Rules) == 0 {
+		return nil
+	}
+	principal := principalName(ctx)
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	var matched []*bucket
+	var wait time.Duration
+	for i := range l.cfg.Rules {
+		r := &l.cfg.Rules[i]
+		if !r.matches(principal, op, queue) {
+			continue
+		}
+		key := r.bucketKey(i, principal, op, queue)
+		b := l.buckets[key]
+		if b == nil {
+			b = &bucket{tokens: float64(r.Burst), last: now, rate: r.Rate, burst: float64(r.Burst)}
+			l.buckets[key] = b
+		}
+		b.refill(now)
+		if b.tokens < 1 {
+			wait = max(wait, time.Duration((1-b.tokens)/b.rate*float64(time.Second)))
+		}
+		matched = append(matched, b)
+	}
+	if wait > 0 {
+		l.stats.Throttled++
+		return &Error{Reason: fmt.Sprintf("rate limit exceeded for %s on %s %s", principal, op, queue), RetryAfter: wait}
+	}
+	for _, b := range matched {
+		b.tokens--
+	}
+	return nil
+}
+
+// sweep drops buckets that have refilled completely; a new bucket starts
+// full, so forgetting them changes nothing.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.sweptAt) < sweepInterval {
+		return
+	}
+	l.sweptAt = now
+	for key, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= b.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// AcquireLongPoll reserves a slot for a get or browse waiting waitMs. Short
+// waits are not counted. The returned release must be called when the wait
+// ends; it is never nil.
+func (l *Limiter) AcquireLongPoll(ctx context.Context, waitMs int) (func(), *Error) {
+	noop := func() {}
+	if l == nil || l.cfg.LongPoll.ThresholdMs <= 0 || waitMs < l.cfg.LongPoll.ThresholdMs {
+		return noop, nil
+	}
+	principal := principalName(ctx)
+	lp := l.cfg.LongPoll
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if lp.MaxConcurrent > 0 && l.longPolls >= lp.MaxConcurrent {
+		l.stats.LongPollRejected++
+		return noop, &Error{Reason: "too many concurrent long polls", RetryAfter: time.Second}
+	}
+	if lp.MaxPerPrincipal > 0 && l.longPollsBy[principal] >= lp.MaxPerPrincipal {
+		l.stats.LongPollRejected++
+		return noop, &Error{Reason: fmt.Sprintf("too many concurrent long polls for %s", principal), RetryAfter: time.Second}
+	}
+	l.longPolls++
+	l.longPollsBy[principal]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.longPolls--
+			if l.longPollsBy[principal]--; l.longPollsBy[principal] <= 0 {
+				delete(l.longPollsBy, principal)
+			}
+		})
+	}, nil
+}
+
+// Stats returns a snapshot of the rejection counters.
+func (l *Limiter) Stats() Stats {
+	if l == nil {
+		return Stats{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s := l.stats
+	s.ActiveLongPolls = l.longPolls
+	return s
+}
@@ -0,0 +1,145 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+)
+
+func as(name string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{Name: name})
+}
+
+func TestAllowPerPrincipal(t *testing.T) {
+	// A near-zero rate means no tokens come back during the test.
+	l, err := New(Config{Rules: []Rule{{
+		Principals: []string{"*"},
+		Queues:     []string{"ORDERS.*"},
+		Operations: []auth.Operation{auth.OpPut},
+		Rate:       0.001,
+		Burst:      2,
+		Per:        []string{"principal"},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := l.Allow(as("alice"), auth.OpPut, "ORDERS.IN"); err != nil {
+			t.Fatalf("put %d: %v", i, err)
+		}
+	}
+	lerr := l.Allow(as("alice"), auth.OpPut, "ORDERS.OUT")
+	if lerr == nil {
+		t.Fatal("third put should be throttled")
+	}
+	if lerr.RetryAfter <= 0 || lerr.RetryAfterSeconds() < 1 {
+		t.Fatalf("RetryAfter = %v", lerr.RetryAfter)
+	}
+
+	// Other principals, operations and unmatched queues are unaffected.
+	if err := l.Allow(as("bob"), auth.OpPut, "ORDERS.IN"); err != nil {
+		t.Fatalf("bob: %v", err)
+	}
+	if err := l.Allow(as("alice"), auth.OpGet, "ORDERS.IN"); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if err := l.Allow(as("alice"), auth.OpPut, "PAYMENTS.IN"); err != nil {
+		t.Fatalf("other queue: %v", err)
+	}
+	if got := l.Stats().Throttled; got != 1 {
+		t.Fatalf("Throttled = %d, want 1", got)
+	}
+}
+
+func TestAllowChargesAllOrNothing(t *testing.T) {
+	// A shared queue bucket plus a roomier per-principal bucket.
+	l, err := New(Config{Rules: []Rule{
+		{Principals: []string{"*"}, Queues: []string{"Q"}, Operations: []auth.Operation{"*"}, Rate: 0.001, Burst: 1},
+		{Principals: []string{"*"}, Queues: []string{"*"}, Operations: []auth.Operation{"*"}, Rate: 0.001, Burst: 2, Per: []string{"principal"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Allow(as("alice"), auth.OpGet, "Q"); err != nil {
+		t.Fatal(err)
+	}
+	// Rejected by the shared Q bucket, so alice's own bucket must not be charged.
+	if err := l.Allow(as("bob"), auth.OpGet, "Q"); err == nil {
+		t.Fatal("bob should hit the shared queue limit")
+	}
+	if err := l.Allow(as("alice"), auth.OpGet, "Q"); err == nil {
+		t.Fatal("alice should hit the shared queue limit")
+	}
+	if err := l.Allow(as("alice"), auth.OpGet, "OTHER"); err != nil {
+		t.Fatalf("alice's bucket was charged for a rejected request: %v", err)
+	}
+}
+
+func TestAcquireLongPoll(t *testing.T) {
+	l, err := New(Config{LongPoll: LongPollConfig{MaxConcurrent: 2, MaxPerPrincipal: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Short waits are never counted.
+	release, lerr := l.AcquireLongPoll(as("alice"), 10)
+	if lerr != nil {
+		t.Fatal(lerr)
+	}
+	release()
+
+	relA, lerr := l.AcquireLongPoll(as("alice"), 30000)
+	if lerr != nil {
+		t.Fatal(lerr)
+	}
+	if _, lerr := l.AcquireLongPoll(as("alice"), 30000); lerr == nil {
+		t.Fatal("second long poll for alice should be rejected")
+	}
+	relB, lerr := l.AcquireLongPoll(as("bob"), 30000)
+	if lerr != nil {
+		t.Fatal(lerr)
+	}
+	if _, lerr := l.AcquireLongPoll(as("carol"), 30000); lerr == nil {
+		t.Fatal("third concurrent long poll should be rejected")
+	}
+	if got := l.Stats().ActiveLongPolls; got != 2 {
+		t.Fatalf("ActiveLongPolls = %d, want 2", got)
+	}
+
+	relA()
+	relA() // release is idempotent
+	relB()
+	if got := l.Stats(); got.ActiveLongPolls != 0 || got.LongPollRejected != 2 {
+		t.Fatalf("stats = %+v", got)
+	}
+	if _, lerr := l.AcquireLongPoll(as("alice"), 30000); lerr != nil {
+		t.Fatalf("after release: %v", lerr)
+	}
+}
+
+func TestNilLimiterAdmitsEverything(t *testing.T) {
+	var l *Limiter
+	if err := l.Allow(context.Background(), auth.OpPut, "Q"); err != nil {
+		t.Fatal(err)
+	}
+	release, err := l.AcquireLongPoll(context.Background(), 60000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
+
+func TestNewRejectsBadRules(t *testing.T) {
+	bad := []Rule{
+		{Principals: []string{"*"}, Queues: []string{"*"}, Operations: []auth.Operation{"*"}},
+		{Principals: []string{"*"}, Queues: []string{"*"}, Operations: []auth.Operation{"*"}, Rate: 1, Per: []string{"tenant"}},
+		{Queues: []string{"*"}, Operations: []auth.Operation{"*"}, Rate: 1},
+	}
+	for i, r := range bad {
+		if _, err := New(Config{Rules: []Rule{r}}); err == nil {
+			t.Errorf("rule %d: expected error", i)
+		}
+	}
+}
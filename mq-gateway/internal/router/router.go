@@ -0,0 +1,565 @@
+// Package router forwards messages between queues by content. Each input
+// consumes one queue under syncpoint, picks the first rule whose conditions
+// hold for the message's properties, MQMD fields or JSON payload, and puts the
+// message to the rule's targets in the same unit of work as the get. Messages
+// no rule matches are dead-lettered. The rules file is reloaded when it
+// changes, without dropping messages in flight.
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/audit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/worker"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+// reloadCheck limits how often the rules file is stat'ed for changes.
+const reloadCheck = 5 * time.Second
+
+// Rule forwards the messages its conditions match.
+type Rule struct {
+	// Name identifies the rule in stats, logs and audit records (default
+	// rule-<n>, counting from 1).
+	Name string `json:"name"`
+	// When lists conditions that must all hold; empty matches every message.
+	When []Condition `json:"when,omitempty"`
+	// Targets receive a copy of the message each.
+	Targets   []string         `json:"targets"`
+	Transform mqcore.Transform `json:"transform,omitempty"`
+}
+
+// Input routes the messages of Queue by its Rules, in order.
+type Input struct {
+	// Name identifies the input in stats, logs and audit records.
+	Name  string `json:"name"`
+	Queue string `json:"queue"`
+	Rules []Rule `json:"rules"`
+	// UnmatchedQueue receives messages no rule matches; empty sends them to
+	// the dead-letter queue with reason mqcore.ReasonNoRoute.
+	UnmatchedQueue string `json:"unmatched_queue,omitempty"`
+	// Parallelism is the number of workers, each with its own MQ connection
+	// (default 1). Above 1, messages may reach the targets out of order.
+	Parallelism int `json:"parallelism,omitempty"`
+	// MaxAttempts is how many failed forwards a message gets before it is
+	// moved to BackoutQueue (default 5).
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// BackoutQueue defaults to the queue's BOQNAME, or else the dead-letter queue.
+	BackoutQueue string `json:"backout_queue,omitempty"`
+	// After a failure the worker waits BackoffMs, doubled per attempt up to
+	// MaxBackoffMs (defaults 1000 and 60000).
+	BackoffMs    int `json:"backoff_ms,omitempty"`
+	MaxBackoffMs int `json:"max_backoff_ms,omitempty"`
+	// MaxMsgBytes is the largest message accepted (default 4 MiB).
+	MaxMsgBytes int `json:"max_msg_bytes,omitempty"`
+}
+
+// Config is the rules file format.
+type Config struct {
+	Inputs []Input `json:"inputs"`
+}
+
+// Consumer is a syncpoint consumer on a connection of its own, normally an
+// *mqcore.TxConsumer.
+type Consumer interface {
+	worker.Consumer
+	Forward(t mqcore.Transform, targets ...string) error
+	Commit() error
+	Backout() error
+	DeadLetter(queueName string) (string, error)
+	MoveToBackout(queueName string) (string, error)
+}
+
+// OpenFunc opens a Consumer on queue for one worker.
+type OpenFunc func(queue string, maxBytes int) (Consumer, error)
+
+// Stats counts what the router did since it started; counters survive
+// reloads for inputs and rules that keep their names.
+type Stats struct {
+	Reloads        uint64       `json:"reloads"`
+	ReloadFailures uint64       `json:"reload_failures"`
+	Inputs         []InputStats `json:"inputs,omitempty"`
+}
+
+// InputStats counts the messages of one input.
+type InputStats struct {
+	Name     string `json:"name"`
+	Queue    string `json:"queue"`
+	Received uint64 `json:"received"`
+	// Unmatched messages went to the unmatched or dead-letter queue.
+	Unmatched uint64 `json:"unmatched"`
+	// BackedOut messages were moved off the queue after MaxAttempts.
+	BackedOut uint64      `json:"backed_out"`
+	Failures  uint64      `json:"failures"`
+	Rules     []RuleStats `json:"rules,omitempty"`
+}
+
+// RuleStats counts the messages one rule matched and forwarded.
+type RuleStats struct {
+	Name      string `json:"name"`
+	Matched   uint64 `json:"matched"`
+	Forwarded uint64 `json:"forwarded"`
+	Failures  uint64 `json:"failures"`
+}
+
+// Router runs the workers of every input. A nil *Router runs nothing.
+type Router struct {
+	open  OpenFunc
+	audit *audit.Logger
+	// path is the rules file, empty when the config was given to New.
+	path string
+
+	mu      sync.Mutex
+	cfg     Config
+	modTime time.Time
+	stats   Stats
+	inputs  map[string]*InputStats
+	rules   map[[2]string]*RuleStats
+}
+
+// validate checks cfg and fills in defaults.
+func validate(cfg *Config) error {
+	names := make(map[string]bool)
+	for i := range cfg.Inputs {
+		in := &cfg.Inputs[i]
+		if in.Queue == "" {
+			return fmt.Errorf("input %d needs a queue", i)
+		}
+		if in.Name == "" {
+			in.Name = in.Queue
+		}
+		if names[in.Name] {
+			return fmt.Errorf("input %d: duplicate name %q", i, in.Name)
+		}
+		names[in.Name] = true
+		if in.Parallelism < 0 || in.MaxAttempts < 0 || in.BackoffMs < 0 || in.MaxBackoffMs < 0 || in.MaxMsgBytes < 0 {
+			return fmt.Errorf("input %q: negative setting", in.Name)
+		}
+		if in.Parallelism == 0 {
+			in.Parallelism = 1
+		}
+		if in.MaxAttempts == 0 {
+			in.MaxAttempts = 5
+		}
+		if in.BackoffMs == 0 {
+			in.BackoffMs = 1000
+		}
+		if in.MaxBackoffMs == 0 {
+			in.MaxBackoffMs = 60000
+		}
+		in.MaxBackoffMs = max(in.MaxBackoffMs, in.BackoffMs)
+
+		ruleNames := make(map[string]bool)
+		for j := range in.Rules {
+			r := &in.Rules[j]
+			if r.Name == "" {
+				r.Name = fmt.Sprintf("rule-%d", j+1)
+			}
+			if ruleNames[r.Name] {
+				return fmt.Errorf("input %q: duplicate rule name %q", in.Name, r.Name)
+			}
+			ruleNames[r.Name] = true
+			if len(r.Targets) == 0 {
+				return fmt.Errorf("input %q rule %q: no targets", in.Name, r.Name)
+			}
+			for _, t := range r.Targets {
+				if t == "" || t == in.Queue {
+					return fmt.Errorf("input %q rule %q: target must be another queue", in.Name, r.Name)
+				}
+			}
+			if p := r.Transform.Priority; p != nil && (*p < 0 || *p > 9) {
+				return fmt.Errorf("input %q rule %q: priority must be 0-9", in.Name, r.Name)
+			}
+			if p := r.Transform.Persistence; p != nil && (*p < 0 || *p > 2) {
+				return fmt.Errorf("input %q rule %q: persistence must be 0-2", in.Name, r.Name)
+			}
+			for k := range r.When {
+				if err := r.When[k].compile(); err != nil {
+					return fmt.Errorf("input %q rule %q: %w", in.Name, r.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// New validates cfg, fills in defaults and returns a Router whose workers
+// open their consumers with open. Messages are audited to auditLog when it is
+// not nil.
+func New(cfg Config, open OpenFunc, auditLog *audit.Logger) (*Router, error) {
+	if err := validate(&cfg); err != nil {
+		return nil, err
+	}
+	r := &Router{
+		open:   open,
+		audit:  auditLog,
+		inputs: make(map[string]*InputStats),
+		rules:  make(map[[2]string]*RuleStats),
+	}
+	r.setConfig(cfg)
+	return r, nil
+}
+
+// readConfig reads and validates the rules file at path.
+func readConfig(path string) (Config, error) {
+	var cfg Config
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := validate(&cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Load reads a JSON Config from path; Run reloads it when it changes.
+func Load(path string, open OpenFunc, auditLog *audit.Logger) (*Router, error) {
+	st, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := New(cfg, open, auditLog)
+	if err != nil {
+		return nil, err
+	}
+	r.path, r.modTime = path, st.ModTime()
+	return r, nil
+}
+
+// FromEnv loads the file named by ROUTER_FILE, or returns nil when unset.
+func FromEnv(open OpenFunc, auditLog *audit.Logger) (*Router, error) {
+	path := os.Getenv("ROUTER_FILE")
+	if path == "" {
+		return nil, nil
+	}
+	return Load(path, open, auditLog)
+}
+
+// setConfig installs cfg and creates counters for its new inputs and rules.
+func (r *Router) setConfig(cfg Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cfg = cfg
+	for _, in := range cfg.Inputs {
+		if r.inputs[in.Name] == nil {
+			r.inputs[in.Name] = &InputStats{Name: in.Name}
+		}
+		r.inputs[in.Name].Queue = in.Queue
+		for _, rule := range in.Rules {
+			key := [2]string{in.Name, rule.Name}
+			if r.rules[key] == nil {
+				r.rules[key] = &RuleStats{Name: rule.Name}
+			}
+		}
+	}
+}
+
+// maybeReload reloads the rules file when its modification time changed and
+// reports whether a new config was installed. An invalid file is logged and
+// the running config kept.
+func (r *Router) maybeReload() bool {
+	if r.path == "" {
+		return false
+	}
+	st, err := os.Stat(r.path)
+	r.mu.Lock()
+	changed := err == nil && !st.ModTime().Equal(r.modTime)
+	if changed {
+		r.modTime = st.ModTime()
+	}
+	r.mu.Unlock()
+	if !changed {
+		return false
+	}
+
+	cfg, err := readConfig(r.path)
+	if err != nil {
+		r.count(func() { r.stats.ReloadFailures++ })
+		slog.Error("[router] rules reload failed, keeping the running rules",
+			"file", r.path,
+			"error", err,
+			"id", "8f334ce2-d5ab-4702-b8cc-e8bc4ea817a6")
+		return false
+	}
+	r.setConfig(cfg)
+	r.count(func() { r.stats.Reloads++ })
+	slog.Info("[router] rules reloaded",
+		"file", r.path,
+		"inputs", len(cfg.Inputs),
+		"id", "2c3259a7-116a-4ada-8eb1-cfb981a54e79")
+	return true
+}
+
+// count updates counters under the stats lock.
+func (r *Router) count(update func()) {
+	r.mu.Lock()
+	update()
+	r.mu.Unlock()
+}
+
+// Stats returns a snapshot of the counters of the configured inputs and rules.
+func (r *Router) Stats() Stats {
+	if r == nil {
+		return Stats{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := Stats{Reloads: r.stats.Reloads, ReloadFailures: r.stats.ReloadFailures}
+	for _, in := range r.cfg.Inputs {
+		is := *r.inputs[in.Name]
+		is.Rules = nil
+		for _, rule := range in.Rules {
+			is.Rules = append(is.Rules, *r.rules[[2]string{in.Name, rule.Name}])
+		}
+		s.Inputs = append(s.Inputs, is)
+	}
+	return s
+}
+
+// running is an input whose workers are started; cur is swapped on reloads
+// that leave the workers' queue and size settings alone.
+type running struct {
+	cur    atomic.Pointer[Input]
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func (x *running) stop() {
+	x.cancel()
+	x.wg.Wait()
+}
+
+// sameWorkers reports whether a and b can be served by the same workers.
+func sameWorkers(a, b *Input) bool {
+	return a.Queue == b.Queue && a.Parallelism == b.Parallelism && a.MaxMsgBytes == b.MaxMsgBytes
+}
+
+// Run starts Parallelism workers per input, applies reloaded rules as they
+// come, and blocks until ctx is done and every worker has finished its
+// message and disconnected.
+func (r *Router) Run(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	inputs := make(map[string]*running)
+	defer func() {
+		for _, x := range inputs {
+			x.stop()
+		}
+	}()
+	r.apply(ctx, inputs)
+
+	ticker := time.NewTicker(reloadCheck)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if r.maybeReload() {
+				r.apply(ctx, inputs)
+			}
+		}
+	}
+}
+
+// apply brings the running inputs in line with the current config: rules
+// changes are picked up by the next message, while a changed queue or worker
+// count restarts the input's workers.
+func (r *Router) apply(ctx context.Context, inputs map[string]*running) {
+	r.mu.Lock()
+	cfg := r.cfg
+	r.mu.Unlock()
+
+	wanted := make(map[string]bool)
+	for i := range cfg.Inputs {
+		in := &cfg.Inputs[i]
+		wanted[in.Name] = true
+		if x := inputs[in.Name]; x != nil {
+			if sameWorkers(x.cur.Load(), in) {
+				x.cur.Store(in)
+				continue
+			}
+			x.stop()
+		}
+		inputs[in.Name] = r.start(ctx, in)
+	}
+	for name, x := range inputs {
+		if !wanted[name] {
+			x.stop()
+			delete(inputs, name)
+			slog.Info("[router] input stopped",
+				"input", name,
+				"id", "af050e63-e6ea-4962-bd18-784679e2298b")
+		}
+	}
+}
+
+// start runs the workers of in.
+func (r *Router) start(ctx context.Context, in *Input) *running {
+	x := &running{}
+	x.cur.Store(in)
+	ctx, x.cancel = context.WithCancel(ctx)
+	slog.Info("[router] input started",
+		"input", in.Name,
+		"queue", in.Queue,
+		"rules", len(in.Rules),
+		"parallelism", in.Parallelism,
+		"id", "a3871562-77e2-4d08-a0a7-d768504a756c")
+	for w := 0; w < in.Parallelism; w++ {
+		x.wg.Add(1)
+		go func() {
+			defer x.wg.Done()
+			r.worker(ctx, x)
+		}()
+	}
+	return x
+}
+
+// worker routes messages of x until ctx is done, reconnecting with backoff
+// when MQ fails. Every call reads the input afresh, so reloads take effect
+// from the next message.
+func (r *Router) worker(ctx context.Context, x *running) {
+	l := worker.Loop[Consumer]{
+		Open: func() (Consumer, error) {
+			in := x.cur.Load()
+			return r.open(in.Queue, in.MaxMsgBytes)
+		},
+		Handle: func(ctx context.Context, c Consumer, m *mqcore.ArchivedMessage) time.Duration {
+			return r.route(ctx, c, x.cur.Load(), m)
+		},
+		Backoff: func(attempt int) time.Duration {
+			in := x.cur.Load()
+			return worker.Backoff(in.BackoffMs, in.MaxBackoffMs, attempt)
+		},
+		OpenFailed: func(err error) {
+			in := x.cur.Load()
+			slog.Error("[router] cannot open queue",
+				"input", in.Name,
+				"queue", in.Queue,
+				"error", err,
+				"id", "5168877f-5b30-4323-8d91-776f91215af0")
+		},
+		GetFailed: func(err error) {
+			in := x.cur.Load()
+			slog.Error("[router] get failed",
+				"input", in.Name,
+				"queue", in.Queue,
+				"error", err,
+				"id", "f46b1be0-8068-4098-88d4-5bd354e3cae6")
+			r.count(func() { r.inputs[in.Name].Failures++ })
+		},
+	}
+	l.Run(ctx)
+}
+
+// route handles one message got by c and returns how long the worker should
+// wait before the next one.
+func (r *Router) route(ctx context.Context, c Consumer, in *Input, m *mqcore.ArchivedMessage) time.Duration {
+	r.mu.Lock()
+	is := r.inputs[in.Name]
+	is.Received++
+	r.mu.Unlock()
+	rec := audit.Record{
+		Operation: audit.OpRoute,
+		Transport: "router",
+		Queue:     in.Queue,
+		MsgID:     m.MsgID,
+		CorrelID:  m.CorrelID,
+	}.WithPayload(string(m.Payload))
+
+	if int(m.BackoutCount) >= in.MaxAttempts {
+		target, err := c.MoveToBackout(in.BackoutQueue)
+		if err != nil {
+			slog.Error("[router] cannot move message to backout queue",
+				"input", in.Name,
+				"target", target,
+				"msg_id", m.MsgID,
+				"error", err,
+				"id", "d500458a-08ca-470f-bb29-6f69462d6788")
+			r.count(func() { is.Failures++ })
+			r.record(ctx, rec, err)
+			return worker.Backoff(in.BackoffMs, in.MaxBackoffMs, int(m.BackoutCount))
+		}
+		slog.Warn("[router] message moved to backout queue",
+			"input", in.Name,
+			"target", target,
+			"msg_id", m.MsgID,
+			"attempts", m.BackoutCount,
+			"id", "b1c14830-08c1-468c-98c8-64772118669f")
+		r.count(func() { is.BackedOut++ })
+		rec.Outcome = audit.OutcomeBackedOut
+		r.record(ctx, rec, nil)
+		return 0
+	}
+
+	rule := in.match(m)
+	if rule == nil {
+		target, err := c.DeadLetter(in.UnmatchedQueue)
+		if err != nil {
+			slog.Error("[router] cannot dead-letter unmatched message",
+				"input", in.Name,
+				"target", target,
+				"msg_id", m.MsgID,
+				"error", err,
+				"id", "3c5d85ab-3ae6-4839-967a-b63d85610e19")
+			r.count(func() { is.Failures++ })
+			r.record(ctx, rec, err)
+			return worker.Backoff(in.BackoffMs, in.MaxBackoffMs, int(m.BackoutCount)+1)
+		}
+		r.count(func() { is.Unmatched++ })
+		rec.Outcome = audit.OutcomeUnmatched
+		r.record(ctx, rec, nil)
+		return 0
+	}
+
+	rec.Target = rule.Name
+	r.mu.Lock()
+	rs := r.rules[[2]string{in.Name, rule.Name}]
+	rs.Matched++
+	r.mu.Unlock()
+	err := c.Forward(rule.Transform, rule.Targets...)
+	if err == nil {
+		// A failed commit backs out, so the message is routed again.
+		err = c.Commit()
+	} else if berr := c.Backout(); berr != nil {
+		slog.Error("[router] backout failed",
+			"input", in.Name,
+			"error", berr,
+			"id", "a04e5582-7174-434e-9f6a-6c38f09cca88")
+	}
+	r.record(ctx, rec, err)
+	if err != nil {
+		slog.Warn("[router] forward failed",
+			"input", in.Name,
+			"rule", rule.Name,
+			"msg_id", m.MsgID,
+			"attempt", m.BackoutCount+1,
+			"error", err,
+			"id", "d199b39f-31ef-4e5b-be10-0c74d8e82b24")
+		r.count(func() { rs.Failures++ })
+		return worker.Backoff(in.BackoffMs, in.MaxBackoffMs, int(m.BackoutCount)+1)
+	}
+	r.count(func() { rs.Forwarded++ })
+	return 0
+}
+
+// record writes an audit record; a non-nil err marks it failed.
+func (r *Router) record(ctx context.Context, rec audit.Record, err error) {
+	worker.Record(ctx, r.audit, rec, err)
+}
@@ -0,0 +1,236 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+// fakeConsumer records what the router did with the current message.
+type fakeConsumer struct {
+	forwarded    []string
+	transform    mqcore.Transform
+	forwardErr   error
+	committed    int
+	backedOut    int
+	deadLettered []string
+	movedTo      []string
+}
+
+func (c *fakeConsumer) Next(int) (*mqcore.ArchivedMessage, error) { return nil, nil }
+func (c *fakeConsumer) Commit() error                             { c.committed++; return nil }
+func (c *fakeConsumer) Backout() error                            { c.backedOut++; return nil }
+func (c *fakeConsumer) Close() error                              { return nil }
+func (c *fakeConsumer) Forward(t mqcore.Transform, targets ...string) error {
+	if c.forwardErr != nil {
+		return c.forwardErr
+	}
+	c.transform = t
+	c.forwarded = append(c.forwarded, targets...)
+	return nil
+}
+func (c *fakeConsumer) DeadLetter(q string) (string, error) {
+	c.deadLettered = append(c.deadLettered, q)
+	return q, nil
+}
+func (c *fakeConsumer) MoveToBackout(q string) (string, error) {
+	c.movedTo = append(c.movedTo, q)
+	return q, nil
+}
+
+func stringProperty(name, v string) mqcore.Property {
+	return mqcore.Property{Name: name, Type: "string", Value: []byte(`"` + v + `"`)}
+}
+
+func TestConditions(t *testing.T) {
+	m := &mqcore.ArchivedMessage{
+		Format:   "MQSTR",
+		Priority: 7,
+		Properties: []mqcore.Property{
+			stringProperty("Region", "EU"),
+			{Name: "Amount", Type: "int32", Value: []byte("1500")},
+		},
+		Payload: []byte(`{"order":{"type":"express","lines":[{"sku":"A-1"}],"total":99.5}}`),
+	}
+	for _, tc := range []struct {
+		c    Condition
+		want bool
+	}{
+		{Condition{Field: "property:Region", Value: "EU"}, true},
+		{Condition{Field: "property:Region", Op: "ne", Value: "EU"}, false},
+		{Condition{Field: "property:Region", Op: "in", Values: []string{"US", "EU"}}, true},
+		{Condition{Field: "property:Amount", Op: "gt", Value: "1000"}, true},
+		{Condition{Field: "property:Missing", Op: "ne", Value: "x"}, false},
+		{Condition{Field: "property:Missing", Op: "absent"}, true},
+		{Condition{Field: "mqmd:format", Value: "MQSTR"}, true},
+		{Condition{Field: "mqmd:priority", Op: "ge", Value: "7"}, true},
+		{Condition{Field: "mqmd:reply_to_q", Op: "exists"}, false},
+		{Condition{Field: "json:order.type", Op: "prefix", Value: "exp"}, true},
+		{Condition{Field: "json:order.lines.0.sku", Op: "regex", Value: `^A-\d+$`}, true},
+		{Condition{Field: "json:order.lines.1.sku", Op: "exists"}, false},
+		{Condition{Field: "json:order.total", Op: "lt", Value: "100"}, true},
+		{Condition{Field: "json:order.type", Op: "gt", Value: "1"}, false},
+	} {
+		c := tc.c
+		if err := c.compile(); err != nil {
+			t.Fatalf("%+v: %v", tc.c, err)
+		}
+		if got := c.matches(&message{m: m}); got != tc.want {
+			t.Errorf("%s %s %q%v = %v, want %v", c.Field, c.Op, c.Value, c.Values, got, tc.want)
+		}
+	}
+
+	// A payload that is not JSON has no json: fields.
+	c := Condition{Field: "json:order", Op: "absent"}
+	_ = c.compile()
+	if !c.matches(&message{m: &mqcore.ArchivedMessage{Payload: []byte("<order/>")}}) {
+		t.Error("json field found in an XML payload")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Config{Inputs: []Input{{Queue: "IN", Rules: []Rule{{Targets: []string{"OUT"}}}}}}
+	if err := validate(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	in := cfg.Inputs[0]
+	if in.Name != "IN" || in.Rules[0].Name != "rule-1" || in.Parallelism != 1 || in.MaxAttempts != 5 {
+		t.Fatalf("defaults not applied: %+v", in)
+	}
+
+	nine := int32(10)
+	for _, bad := range []Config{
+		{Inputs: []Input{{}}},
+		{Inputs: []Input{{Queue: "IN", Rules: []Rule{{}}}}},
+		{Inputs: []Input{{Queue: "IN", Rules: []Rule{{Targets: []string{"IN"}}}}}},
+		{Inputs: []Input{{Queue: "IN"}, {Queue: "IN"}}},
+		{Inputs: []Input{{Queue: "IN", Rules: []Rule{{Name: "a", Targets: []string{"X"}}, {Name: "a", Targets: []string{"Y"}}}}}},
+		{Inputs: []Input{{Queue: "IN", Rules: []Rule{{Targets: []string{"X"}, When: []Condition{{Field: "region"}}}}}}},
+		{Inputs: []Input{{Queue: "IN", Rules: []Rule{{Targets: []string{"X"}, When: []Condition{{Field: "mqmd:colour"}}}}}}},
+		{Inputs: []Input{{Queue: "IN", Rules: []Rule{{Targets: []string{"X"}, When: []Condition{{Field: "json:a", Op: "gt", Value: "many"}}}}}}},
+		{Inputs: []Input{{Queue: "IN", Rules: []Rule{{Targets: []string{"X"}, When: []Condition{{Field: "json:a", Op: "regex", Value: "("}}}}}}},
+		{Inputs: []Input{{Queue: "IN", Rules: []Rule{{Targets: []string{"X"}, Transform: mqcore.Transform{Priority: &nine}}}}}},
+	} {
+		if err := validate(&bad); err == nil {
+			t.Errorf("validate(%+v) succeeded", bad)
+		}
+	}
+}
+
+func newRouter(t *testing.T) *Router {
+	t.Helper()
+	r, err := New(Config{Inputs: []Input{{
+		Name:         "orders",
+		Queue:        "ORDERS.IN",
+		MaxAttempts:  3,
+		BackoffMs:    100,
+		BackoutQueue: "ORDERS.BACKOUT",
+		Rules: []Rule{
+			{
+				Name:      "eu",
+				When:      []Condition{{Field: "property:Region", Value: "EU"}},
+				Targets:   []string{"ORDERS.EU", "ORDERS.AUDIT"},
+				Transform: mqcore.Transform{SetProperties: map[string]string{"RoutedBy": "eu"}},
+			},
+			{Name: "express", When: []Condition{{Field: "json:type", Value: "express"}}, Targets: []string{"ORDERS.FAST"}},
+		},
+	}}}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRoute(t *testing.T) {
+	r := newRouter(t)
+	in := &r.cfg.Inputs[0]
+	ctx := context.Background()
+
+	// The first matching rule wins, and its targets share one unit of work.
+	c := &fakeConsumer{}
+	m := &mqcore.ArchivedMessage{Properties: []mqcore.Property{stringProperty("Region", "EU")}, Payload: []byte(`{"type":"express"}`)}
+	if wait := r.route(ctx, c, in, m); wait != 0 {
+		t.Fatalf("wait = %v after success", wait)
+	}
+	if len(c.forwarded) != 2 || c.forwarded[0] != "ORDERS.EU" || c.forwarded[1] != "ORDERS.AUDIT" || c.committed != 1 {
+		t.Fatalf("forwarded %v, committed %d", c.forwarded, c.committed)
+	}
+	if c.transform.SetProperties["RoutedBy"] != "eu" {
+		t.Fatalf("transform not applied: %+v", c.transform)
+	}
+
+	c = &fakeConsumer{}
+	r.route(ctx, c, in, &mqcore.ArchivedMessage{Payload: []byte(`{"type":"standard"}`)})
+	if len(c.deadLettered) != 1 || c.deadLettered[0] != "" || len(c.forwarded) != 0 {
+		t.Fatalf("unmatched message: dead-lettered %v, forwarded %v", c.deadLettered, c.forwarded)
+	}
+
+	c = &fakeConsumer{forwardErr: errors.New("MQPUT(ORDERS.FAST): MQRC_Q_FULL")}
+	wait := r.route(ctx, c, in, &mqcore.ArchivedMessage{BackoutCount: 1, Payload: []byte(`{"type":"express"}`)})
+	if c.backedOut != 1 || c.committed != 0 || wait != 200*time.Millisecond {
+		t.Fatalf("failed forward: backed out %d, committed %d, wait %v", c.backedOut, c.committed, wait)
+	}
+
+	c = &fakeConsumer{}
+	r.route(ctx, c, in, &mqcore.ArchivedMessage{BackoutCount: 3, Payload: []byte(`{"type":"express"}`)})
+	if len(c.movedTo) != 1 || c.movedTo[0] != "ORDERS.BACKOUT" || len(c.forwarded) != 0 {
+		t.Fatalf("poison message: moved to %v, forwarded %v", c.movedTo, c.forwarded)
+	}
+
+	s := r.Stats().Inputs[0]
+	if s.Received != 4 || s.Unmatched != 1 || s.BackedOut != 1 {
+		t.Fatalf("input stats %+v", s)
+	}
+	if eu, fast := s.Rules[0], s.Rules[1]; eu.Matched != 1 || eu.Forwarded != 1 || fast.Matched != 1 || fast.Failures != 1 {
+		t.Fatalf("rule stats %+v", s.Rules)
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	write := func(content string, mtime time.Time) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	write(`{"inputs":[{"queue":"IN","rules":[{"name":"all","targets":["A"]}]}]}`, now.Add(-time.Hour))
+	r, err := Load(path, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.route(context.Background(), &fakeConsumer{}, &r.cfg.Inputs[0], &mqcore.ArchivedMessage{})
+
+	if r.maybeReload() {
+		t.Fatal("reloaded an unchanged file")
+	}
+
+	write(`{"inputs":[{"queue":"IN","rules":[{"name":"all","targets":["B"]}]}]}`, now.Add(-time.Minute))
+	if !r.maybeReload() {
+		t.Fatal("changed file not reloaded")
+	}
+	if got := r.cfg.Inputs[0].Rules[0].Targets[0]; got != "B" {
+		t.Fatalf("target after reload = %s", got)
+	}
+
+	// A broken file keeps the running rules.
+	write(`{"inputs":[{"queue":"IN","rules":[{"name":"all"}]}]}`, now)
+	if r.maybeReload() {
+		t.Fatal("invalid file installed")
+	}
+	s := r.Stats()
+	if s.Reloads != 1 || s.ReloadFailures != 1 || s.Inputs[0].Rules[0].Forwarded != 1 {
+		t.Fatalf("stats after reloads %+v", s)
+	}
+	if got := r.cfg.Inputs[0].Rules[0].Targets[0]; got != "B" {
+		t.Fatalf("target after failed reload = %s", got)
+	}
+}
@@ -0,0 +1,252 @@
+package router
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+// Condition tests one field of a message.
+type Condition struct {
+	// Field is "property:<name>", "mqmd:<field>" with a field named as in
+	// the archive format (format, priority, reply_to_q, ...), or
+	// "json:<path>" with a dot-separated path into a JSON payload, where
+	// array elements are numbered from 0 (json:order.lines.0.sku).
+	Field string `json:"field"`
+	// Op is eq (default), ne, in, prefix, regex, gt, ge, lt, le, exists or
+	// absent. Only absent matches a missing field; gt, ge, lt and le compare
+	// numbers and fail on anything else.
+	Op     string   `json:"op,omitempty"`
+	Value  string   `json:"value,omitempty"`
+	Values []string `json:"values,omitempty"`
+
+	kind, name string
+	re         *regexp.Regexp
+	num        float64
+}
+
+// mqmdFields reads the MQMD fields conditions may test.
+var mqmdFields = map[string]func(m *mqcore.ArchivedMessage) string{
+	"msg_id":             func(m *mqcore.ArchivedMessage) string { return m.MsgID },
+	"correl_id":          func(m *mqcore.ArchivedMessage) string { return m.CorrelID },
+	"group_id":           func(m *mqcore.ArchivedMessage) string { return m.GroupID },
+	"format":             func(m *mqcore.ArchivedMessage) string { return m.Format },
+	"encoding":           func(m *mqcore.ArchivedMessage) string { return itoa(m.Encoding) },
+	"ccsid":              func(m *mqcore.ArchivedMessage) string { return itoa(m.CodedCharSetID) },
+	"msg_type":           func(m *mqcore.ArchivedMessage) string { return itoa(m.MsgType) },
+	"persistence":        func(m *mqcore.ArchivedMessage) string { return itoa(m.Persistence) },
+	"priority":           func(m *mqcore.ArchivedMessage) string { return itoa(m.Priority) },
+	"expiry":             func(m *mqcore.ArchivedMessage) string { return itoa(m.Expiry) },
+	"feedback":           func(m *mqcore.ArchivedMessage) string { return itoa(m.Feedback) },
+	"reply_to_q":         func(m *mqcore.ArchivedMessage) string { return m.ReplyToQ },
+	"reply_to_qmgr":      func(m *mqcore.ArchivedMessage) string { return m.ReplyToQMgr },
+	"backout_count":      func(m *mqcore.ArchivedMessage) string { return itoa(m.BackoutCount) },
+	"user_identifier":    func(m *mqcore.ArchivedMessage) string { return m.UserIdentifier },
+	"appl_identity_data": func(m *mqcore.ArchivedMessage) string { return m.ApplIdentityData },
+	"put_appl_name":      func(m *mqcore.ArchivedMessage) string { return m.PutApplName },
+	"appl_origin_data":   func(m *mqcore.ArchivedMessage) string { return m.ApplOriginData },
+}
+
+func itoa(n int32) string { return strconv.Itoa(int(n)) }
+
+// compile checks c and prepares it for matching.
+func (c *Condition) compile() error {
+	kind, name, ok := strings.Cut(c.Field, ":")
+	if !ok || name == "" {
+		return fmt.Errorf("field %q: want property:, mqmd: or json: followed by a name", c.Field)
+	}
+	switch kind {
+	case "property", "json":
+	case "mqmd":
+		if mqmdFields[name] == nil {
+			return fmt.Errorf("field %q: unknown MQMD field", c.Field)
+		}
+	default:
+		return fmt.Errorf("field %q: want property:, mqmd: or json: followed by a name", c.Field)
+	}
+	c.kind, c.name = kind, name
+
+	if c.Op == "" {
+		c.Op = "eq"
+	}
+	switch c.Op {
+	case "eq", "ne", "prefix", "exists", "absent":
+	case "in":
+		if len(c.Values) == 0 {
+			return fmt.Errorf("field %q: op in needs values", c.Field)
+		}
+	case "regex":
+		re, err := regexp.Compile(c.Value)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", c.Field, err)
+		}
+		c.re = re
+	case "gt", "ge", "lt", "le":
+		n, err := strconv.ParseFloat(c.Value, 64)
+		if err != nil {
+			return fmt.Errorf("field %q: op %s needs a numeric value", c.Field, c.Op)
+		}
+		c.num = n
+	default:
+		return fmt.Errorf("field %q: unknown op %q", c.Field, c.Op)
+	}
+	return nil
+}
+
+// message is a got message as conditions see it; properties and the JSON
+// payload are decoded on first use.
+type message struct {
+	m         *mqcore.ArchivedMessage
+	props     map[string]string
+	doc       any
+	docParsed bool
+}
+
+// value returns the field c tests and whether the message has it.
+func (msg *message) value(c *Condition) (string, bool) {
+	switch c.kind {
+	case "mqmd":
+		v := mqmdFields[c.name](msg.m)
+		return v, v != ""
+	case "property":
+		if msg.props == nil {
+			msg.props = make(map[string]string, len(msg.m.Properties))
+			for _, p := range msg.m.Properties {
+				msg.props[p.Name] = propertyString(p)
+			}
+		}
+		v, ok := msg.props[c.name]
+		return v, ok
+	default:
+		if !msg.docParsed {
+			msg.docParsed = true
+			dec := json.NewDecoder(bytes.NewReader(msg.m.Payload))
+			dec.UseNumber()
+			if dec.Decode(&msg.doc) != nil {
+				msg.doc = nil
+			}
+		}
+		return jsonPath(msg.doc, c.name)
+	}
+}
+
+// propertyString renders a property value as it would be written in a rule.
+func propertyString(p mqcore.Property) string {
+	v, err := p.GoValue()
+	if err != nil || v == nil {
+		return ""
+	}
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// jsonPath walks doc along the dot-separated path.
+func jsonPath(doc any, path string) (string, bool) {
+	cur := doc
+	for _, key := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]any:
+			v, ok := node[key]
+			if !ok {
+				return "", false
+			}
+			cur = v
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return "", false
+			}
+			cur = node[i]
+		default:
+			return "", false
+		}
+	}
+	switch v := cur.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		raw, _ := json.Marshal(v)
+		return string(raw), true
+	}
+}
+
+// matches reports whether msg satisfies c.
+func (c *Condition) matches(msg *message) bool {
+	v, ok := msg.value(c)
+	if c.Op == "absent" {
+		return !ok
+	}
+	if !ok {
+		return false
+	}
+	switch c.Op {
+	case "eq":
+		return v == c.Value
+	case "ne":
+		return v != c.Value
+	case "in":
+		for _, want := range c.Values {
+			if v == want {
+				return true
+			}
+		}
+		return false
+	case "prefix":
+		return strings.HasPrefix(v, c.Value)
+	case "regex":
+		return c.re.MatchString(v)
+	case "exists":
+		return true
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return false
+	}
+	switch c.Op {
+	case "gt":
+		return n > c.num
+	case "ge":
+		return n >= c.num
+	case "lt":
+		return n < c.num
+	default:
+		return n <= c.num
+	}
+}
+
+// match returns the first rule of in whose conditions all hold, or nil.
+func (in *Input) match(m *mqcore.ArchivedMessage) *Rule {
+	msg := &message{m: m}
+	for i := range in.Rules {
+		r := &in.Rules[i]
+		ok := true
+		for j := range r.When {
+			if !r.When[j].matches(msg) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return r
+		}
+	}
+	return nil
+}
@@ -0,0 +1,609 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// jsonSchema is a compiled JSON Schema. The validation keywords of draft
+// 2020-12 are supported except for dynamic references, conditional
+// (if/then/else, dependent*) and unevaluated* keywords; format is an
+// annotation only, as the draft specifies. Unknown keywords are ignored.
+type jsonSchema struct {
+	// always is set for the boolean schemas true and false.
+	always *bool
+
+	types    []string
+	enum     []any
+	constVal any
+	hasConst bool
+
+	properties        map[string]*jsonSchema
+	patternProperties map[*regexp.Regexp]*jsonSchema
+	additional        *jsonSchema
+	required          []string
+	minProperties     *int
+	maxProperties     *int
+
+	items       *jsonSchema
+	prefixItems []*jsonSchema
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	allOf []*jsonSchema
+	anyOf []*jsonSchema
+	oneOf []*jsonSchema
+	not   *jsonSchema
+
+	// ref is resolved after the whole document is compiled.
+	ref    string
+	target *jsonSchema
+}
+
+// jsonCompiler compiles one schema document; cache holds every subschema by
+// JSON pointer so $ref can reach it, recursively if need be.
+type jsonCompiler struct {
+	root  any
+	cache map[string]*jsonSchema
+	refs  []*jsonSchema
+}
+
+// compileJSONSchema parses and compiles a JSON Schema document.
+func compileJSONSchema(raw []byte) (*jsonSchema, error) {
+	doc, err := decodeJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	c := &jsonCompiler{root: doc, cache: make(map[string]*jsonSchema)}
+	s, err := c.compile(doc, "")
+	if err != nil {
+		return nil, err
+	}
+	// Resolving a reference may compile a subschema with references of its own.
+	for i := 0; i < len(c.refs); i++ {
+		if c.refs[i].target, err = c.resolve(c.refs[i].ref); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// decodeJSON decodes one JSON value, keeping numbers exact.
+func decodeJSON(raw []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return v, nil
+}
+
+// resolve returns the subschema a local $ref ("#", "#/$defs/x") points to.
+func (c *jsonCompiler) resolve(ref string) (*jsonSchema, error) {
+	ptr, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, fmt.Errorf("$ref %q: only references within the document are supported", ref)
+	}
+	if s, ok := c.cache[ptr]; ok {
+		return s, nil
+	}
+	node := c.root
+	if ptr != "" {
+		for _, tok := range strings.Split(strings.TrimPrefix(ptr, "/"), "/") {
+			tok = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+			switch n := node.(type) {
+			case map[string]any:
+				node, ok = n[tok]
+			case []any:
+				i, err := strconv.Atoi(tok)
+				ok = err == nil && i >= 0 && i < len(n)
+				if ok {
+					node = n[i]
+				}
+			default:
+				ok = false
+			}
+			if !ok {
+				return nil, fmt.Errorf("$ref %q: no such location", ref)
+			}
+		}
+	}
+	return c.compile(node, ptr)
+}
+
+func (c *jsonCompiler) compile(node any, ptr string) (*jsonSchema, error) {
+	if s, ok := c.cache[ptr]; ok {
+		return s, nil
+	}
+	s := &jsonSchema{}
+	c.cache[ptr] = s
+	if b, ok := node.(bool); ok {
+		s.always = &b
+		return s, nil
+	}
+	m, ok := node.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: a schema must be an object or a boolean", pointerOrRoot(ptr))
+	}
+	fail := func(key, format string, args ...any) error {
+		return fmt.Errorf("%s/%s: %s", ptr, key, fmt.Sprintf(format, args...))
+	}
+	sub := func(key string, v any) (*jsonSchema, error) {
+		return c.compile(v, ptr+"/"+escapePointer(key))
+	}
+	subList := func(key string) ([]*jsonSchema, error) {
+		list, ok := m[key].([]any)
+		if !ok || len(list) == 0 {
+			return nil, fail(key, "want a non-empty array of schemas")
+		}
+		out := make([]*jsonSchema, len(list))
+		for i, v := range list {
+			var err error
+			if out[i], err = c.compile(v, fmt.Sprintf("%s/%s/%d", ptr, key, i)); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	count := func(key string) (*int, error) {
+		n, ok := m[key].(json.Number)
+		i, err := strconv.Atoi(string(n))
+		if !ok || err != nil || i < 0 {
+			return nil, fail(key, "want a non-negative integer")
+		}
+		return &i, nil
+	}
+	number := func(key string) (*float64, error) {
+		n, ok := m[key].(json.Number)
+		f, err := n.Float64()
+		if !ok || err != nil {
+			return nil, fail(key, "want a number")
+		}
+		return &f, nil
+	}
+
+	var err error
+	for key, v := range m {
+		switch key {
+		case "$ref":
+			ref, ok := v.(string)
+			if !ok {
+				return nil, fail(key, "want a string")
+			}
+			s.ref = ref
+			c.refs = append(c.refs, s)
+		case "type":
+			switch t := v.(type) {
+			case string:
+				s.types = []string{t}
+			case []any:
+				for _, e := range t {
+					name, ok := e.(string)
+					if !ok {
+						return nil, fail(key, "want a type name or an array of them")
+					}
+					s.types = append(s.types, name)
+				}
+			default:
+				return nil, fail(key, "want a type name or an array of them")
+			}
+			for _, t := range s.types {
+				switch t {
+				case "null", "boolean", "object", "array", "number", "integer", "string":
+				default:
+					return nil, fail(key, "unknown type %q", t)
+				}
+			}
+		case "enum":
+			list, ok := v.([]any)
+			if !ok {
+				return nil, fail(key, "want an array")
+			}
+			s.enum = list
+		case "const":
+			s.constVal, s.hasConst = v, true
+		case "properties", "patternProperties", "$defs", "definitions":
+			props, ok := v.(map[string]any)
+			if !ok {
+				return nil, fail(key, "want an object")
+			}
+			for name, pv := range props {
+				ps, err := c.compile(pv, ptr+"/"+key+"/"+escapePointer(name))
+				if err != nil {
+					return nil, err
+				}
+				switch key {
+				case "properties":
+					if s.properties == nil {
+						s.properties = make(map[string]*jsonSchema)
+					}
+					s.properties[name] = ps
+				case "patternProperties":
+					re, err := regexp.Compile(name)
+					if err != nil {
+						return nil, fail(key, "%v", err)
+					}
+					if s.patternProperties == nil {
+						s.patternProperties = make(map[*regexp.Regexp]*jsonSchema)
+					}
+					s.patternProperties[re] = ps
+				}
+			}
+		case "additionalProperties":
+			s.additional, err = sub(key, v)
+		case "required":
+			list, ok := v.([]any)
+			if !ok {
+				return nil, fail(key, "want an array of names")
+			}
+			for _, e := range list {
+				name, ok := e.(string)
+				if !ok {
+					return nil, fail(key, "want an array of names")
+				}
+				s.required = append(s.required, name)
+			}
+		case "minProperties":
+			s.minProperties, err = count(key)
+		case "maxProperties":
+			s.maxProperties, err = count(key)
+		case "items":
+			if _, ok := v.([]any); ok {
+				// Draft 4-7 tuple form.
+				s.prefixItems, err = subList(key)
+			} else {
+				s.items, err = sub(key, v)
+			}
+		case "prefixItems":
+			s.prefixItems, err = subList(key)
+		case "minItems":
+			s.minItems, err = count(key)
+		case "maxItems":
+			s.maxItems, err = count(key)
+		case "uniqueItems":
+			s.uniqueItems, _ = v.(bool)
+		case "minLength":
+			s.minLength, err = count(key)
+		case "maxLength":
+			s.maxLength, err = count(key)
+		case "pattern":
+			p, ok := v.(string)
+			if !ok {
+				return nil, fail(key, "want a string")
+			}
+			if s.pattern, err = regexp.Compile(p); err != nil {
+				return nil, fail(key, "%v", err)
+			}
+		case "minimum":
+			s.minimum, err = number(key)
+		case "maximum":
+			s.maximum, err = number(key)
+		case "exclusiveMinimum":
+			// Draft 4 used a boolean modifying minimum; see below.
+			if _, ok := v.(bool); !ok {
+				s.exclusiveMinimum, err = number(key)
+			}
+		case "exclusiveMaximum":
+			if _, ok := v.(bool); !ok {
+				s.exclusiveMaximum, err = number(key)
+			}
+		case "multipleOf":
+			if s.multipleOf, err = number(key); err == nil && *s.multipleOf <= 0 {
+				err = fail(key, "want a positive number")
+			}
+		case "allOf":
+			s.allOf, err = subList(key)
+		case "anyOf":
+			s.anyOf, err = subList(key)
+		case "oneOf":
+			s.oneOf, err = subList(key)
+		case "not":
+			s.not, err = sub(key, v)
+		case "if", "then", "else", "dependentSchemas", "dependentRequired", "dependencies",
+			"unevaluatedProperties", "unevaluatedItems", "$dynamicRef", "$recursiveRef":
+			return nil, fail(key, "keyword not supported")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Draft 4 boolean exclusives turn minimum/maximum into exclusive bounds.
+	if b, ok := m["exclusiveMinimum"].(bool); ok && b && s.minimum != nil {
+		s.exclusiveMinimum, s.minimum = s.minimum, nil
+	}
+	if b, ok := m["exclusiveMaximum"].(bool); ok && b && s.maximum != nil {
+		s.exclusiveMaximum, s.maximum = s.maximum, nil
+	}
+	return s, nil
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func pointerOrRoot(ptr string) string {
+	if ptr == "" {
+		return "/"
+	}
+	return ptr
+}
+
+// validate appends the violations of v, found at the JSON pointer path, to out.
+func (s *jsonSchema) validate(v any, path string, out *violations) {
+	if out.full() {
+		return
+	}
+	if s.always != nil {
+		if !*s.always {
+			out.add(path, "no value is allowed here")
+		}
+		return
+	}
+	if s.target != nil {
+		s.target.validate(v, path, out)
+	}
+
+	if len(s.types) > 0 && !slices.ContainsFunc(s.types, func(t string) bool { return hasType(v, t) }) {
+		out.add(path, fmt.Sprintf("is %s, want %s", typeName(v), strings.Join(s.types, " or ")))
+		return
+	}
+	if s.enum != nil && !slices.ContainsFunc(s.enum, func(e any) bool { return jsonEqual(e, v) }) {
+		out.add(path, "is not one of the allowed values")
+	}
+	if s.hasConst && !jsonEqual(s.constVal, v) {
+		out.add(path, "does not equal the required constant")
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		s.validateObject(v, path, out)
+	case []any:
+		s.validateArray(v, path, out)
+	case string:
+		n := utf8.RuneCountInString(v)
+		if s.minLength != nil && n < *s.minLength {
+			out.add(path, fmt.Sprintf("is shorter than %d characters", *s.minLength))
+		}
+		if s.maxLength != nil && n > *s.maxLength {
+			out.add(path, fmt.Sprintf("is longer than %d characters", *s.maxLength))
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			out.add(path, fmt.Sprintf("does not match pattern %q", s.pattern.String()))
+		}
+	case json.Number:
+		s.validateNumber(v, path, out)
+	}
+
+	for _, sub := range s.allOf {
+		sub.validate(v, path, out)
+	}
+	if s.anyOf != nil {
+		matched := false
+		for _, sub := range s.anyOf {
+			if sub.accepts(v) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			out.add(path, "matches none of the anyOf schemas")
+		}
+	}
+	if s.oneOf != nil {
+		n := 0
+		for _, sub := range s.oneOf {
+			if sub.accepts(v) {
+				n++
+			}
+		}
+		if n != 1 {
+			out.add(path, fmt.Sprintf("matches %d of the oneOf schemas, want exactly 1", n))
+		}
+	}
+	if s.not != nil && s.not.accepts(v) {
+		out.add(path, "matches a schema it must not match")
+	}
+}
+
+// accepts reports whether v is valid against s, without collecting details.
+func (s *jsonSchema) accepts(v any) bool {
+	var out violations
+	out.limit = 1
+	s.validate(v, "", &out)
+	return len(out.list) == 0
+}
+
+func (s *jsonSchema) validateObject(v map[string]any, path string, out *violations) {
+	for _, name := range s.required {
+		if _, ok := v[name]; !ok {
+			out.add(path+"/"+escapePointer(name), "is required")
+		}
+	}
+	if s.minProperties != nil && len(v) < *s.minProperties {
+		out.add(path, fmt.Sprintf("has fewer than %d properties", *s.minProperties))
+	}
+	if s.maxProperties != nil && len(v) > *s.maxProperties {
+		out.add(path, fmt.Sprintf("has more than %d properties", *s.maxProperties))
+	}
+	// Sorted so the report is stable.
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := path + "/" + escapePointer(name)
+		known := false
+		if ps, ok := s.properties[name]; ok {
+			known = true
+			ps.validate(v[name], p, out)
+		}
+		for re, ps := range s.patternProperties {
+			if re.MatchString(name) {
+				known = true
+				ps.validate(v[name], p, out)
+			}
+		}
+		if !known && s.additional != nil {
+			if s.additional.always != nil && !*s.additional.always {
+				out.add(p, "is not an allowed property")
+				continue
+			}
+			s.additional.validate(v[name], p, out)
+		}
+	}
+}
+
+func (s *jsonSchema) validateArray(v []any, path string, out *violations) {
+	if s.minItems != nil && len(v) < *s.minItems {
+		out.add(path, fmt.Sprintf("has fewer than %d items", *s.minItems))
+	}
+	if s.maxItems != nil && len(v) > *s.maxItems {
+		out.add(path, fmt.Sprintf("has more than %d items", *s.maxItems))
+	}
+	for i, e := range v {
+		p := path + "/" + strconv.Itoa(i)
+		switch {
+		case i < len(s.prefixItems):
+			s.prefixItems[i].validate(e, p, out)
+		case s.items != nil:
+			s.items.validate(e, p, out)
+		}
+	}
+	if s.uniqueItems {
+		for i := 1; i < len(v); i++ {
+			for j := 0; j < i; j++ {
+				if jsonEqual(v[i], v[j]) {
+					out.add(path+"/"+strconv.Itoa(i), fmt.Sprintf("duplicates item %d", j))
+					break
+				}
+			}
+		}
+	}
+}
+
+func (s *jsonSchema) validateNumber(v json.Number, path string, out *violations) {
+	f, err := v.Float64()
+	if err != nil {
+		out.add(path, "is not a representable number")
+		return
+	}
+	num := func(x float64) string { return strconv.FormatFloat(x, 'g', -1, 64) }
+	if s.minimum != nil && f < *s.minimum {
+		out.add(path, "is less than "+num(*s.minimum))
+	}
+	if s.maximum != nil && f > *s.maximum {
+		out.add(path, "is greater than "+num(*s.maximum))
+	}
+	if s.exclusiveMinimum != nil && f <= *s.exclusiveMinimum {
+		out.add(path, "must be greater than "+num(*s.exclusiveMinimum))
+	}
+	if s.exclusiveMaximum != nil && f >= *s.exclusiveMaximum {
+		out.add(path, "must be less than "+num(*s.exclusiveMaximum))
+	}
+	if s.multipleOf != nil {
+		q := f / *s.multipleOf
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			out.add(path, "is not a multiple of "+num(*s.multipleOf))
+		}
+	}
+}
+
+func hasType(v any, t string) bool {
+	switch t {
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		if _, err := n.Int64(); err == nil {
+			return true
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	case "number":
+		_, ok := v.(json.Number)
+		return ok
+	}
+	return typeName(v) == t
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// jsonEqual compares decoded JSON values, numbers by value (1 equals 1.0).
+func jsonEqual(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number:
+		bn, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		af, aerr := a.Float64()
+		bf, berr := bn.Float64()
+		return aerr == nil && berr == nil && af == bf
+	case []any:
+		bl, ok := b.([]any)
+		if !ok || len(a) != len(bl) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], bl[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		bm, ok := b.(map[string]any)
+		if !ok || len(a) != len(bm) {
+			return false
+		}
+		for k, av := range a {
+			bv, ok := bm[k]
+			if !ok || !jsonEqual(av, bv) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
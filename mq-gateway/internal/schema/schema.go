@@ -0,0 +1,233 @@
+// Package schema validates message payloads against JSON Schema and XML
+// Schema documents bound to queues, so malformed payloads are rejected
+// before they reach MQ and the consumers behind it.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
+)
+
+// maxViolations caps how many violations one payload reports.
+const maxViolations = 20
+
+// Mode decides what happens to a payload that fails validation.
+type Mode string
+
+const (
+	// ModeEnforce rejects the put.
+	ModeEnforce Mode = "enforce"
+	// ModeWarn logs the violations and puts the message anyway.
+	ModeWarn Mode = "warn"
+)
+
+// Binding applies the schema file Schema, relative to the schema directory,
+// to queues matching Queue (a glob pattern, as in the authorization policy).
+// Files ending in .xsd are XML Schemas; any other file is a JSON Schema.
+type Binding struct {
+	Queue  string `json:"queue"`
+	Schema string `json:"schema"`
+	// Mode overrides the file-wide default.
+	Mode Mode `json:"mode"`
+}
+
+// Config is the bindings.json file format. The first binding matching a
+// queue applies; queues matching none are not validated.
+type Config struct {
+	// Mode is the default for bindings without one; empty is enforce.
+	Mode     Mode      `json:"mode"`
+	Bindings []Binding `json:"bindings"`
+}
+
+// Violation is one way a payload fails its schema. Path is a JSON pointer
+// into a JSON payload or an element path such as /order/line[2]/@sku into
+// an XML payload.
+type Violation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned for a payload rejected by its queue's schema.
+type ValidationError struct {
+	Queue      string
+	Schema     string
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "payload for %s violates schema %s:", e.Queue, e.Schema)
+	for _, v := range e.Violations {
+		fmt.Fprintf(&b, "\n  %s: %s", v.Path, v.Message)
+	}
+	return b.String()
+}
+
+// violations collects up to limit violations; zero is unlimited.
+type violations struct {
+	list  []Violation
+	limit int
+}
+
+func (v *violations) add(path, msg string) {
+	if !v.full() {
+		v.list = append(v.list, Violation{Path: path, Message: msg})
+	}
+}
+
+func (v *violations) full() bool {
+	return v.limit > 0 && len(v.list) >= v.limit
+}
+
+// validator checks one payload; both schema languages implement it.
+type validator func(payload []byte, out *violations)
+
+type binding struct {
+	Binding
+	validate validator
+}
+
+// Registry validates payloads by queue. A nil *Registry validates nothing.
+type Registry struct {
+	bindings []binding
+}
+
+// New compiles the schemas cfg binds, reading them from dir.
+func New(dir string, cfg Config) (*Registry, error) {
+	switch cfg.Mode {
+	case "":
+		cfg.Mode = ModeEnforce
+	case ModeEnforce, ModeWarn:
+	default:
+		return nil, fmt.Errorf("unknown mode %q", cfg.Mode)
+	}
+	// Files bound to several patterns are compiled once.
+	compiled := make(map[string]validator)
+	r := &Registry{}
+	for i, b := range cfg.Bindings {
+		if b.Queue == "" || b.Schema == "" {
+			return nil, fmt.Errorf("binding %d needs a queue and a schema", i)
+		}
+		switch b.Mode {
+		case "":
+			b.Mode = cfg.Mode
+		case ModeEnforce, ModeWarn:
+		default:
+			return nil, fmt.Errorf("binding %d: unknown mode %q", i, b.Mode)
+		}
+		if !filepath.IsLocal(b.Schema) {
+			return nil, fmt.Errorf("binding %d: schema %q is outside the schema directory", i, b.Schema)
+		}
+		v, ok := compiled[b.Schema]
+		if !ok {
+			var err error
+			if v, err = compileFile(filepath.Join(dir, b.Schema)); err != nil {
+				return nil, fmt.Errorf("binding %d: %w", i, err)
+			}
+			compiled[b.Schema] = v
+		}
+		r.bindings = append(r.bindings, binding{Binding: b, validate: v})
+	}
+	return r, nil
+}
+
+// compileFile compiles a schema file by its extension.
+func compileFile(path string) (validator, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".xsd") {
+		s, err := compileXSD(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return func(payload []byte, out *violations) {
+			doc, err := parseXML(payload)
+			if err != nil {
+				out.add("/", "payload is not well-formed XML: "+err.Error())
+				return
+			}
+			s.validate(doc, out)
+		}, nil
+	}
+	s, err := compileJSONSchema(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return func(payload []byte, out *violations) {
+		v, err := decodeJSON(payload)
+		if err != nil {
+			out.add("/", "payload is not valid JSON: "+err.Error())
+			return
+		}
+		s.validate(v, "", out)
+		for i := range out.list {
+			out.list[i].Path = pointerOrRoot(out.list[i].Path)
+		}
+	}, nil
+}
+
+// Load reads dir/bindings.json and the schemas it binds.
+func Load(dir string) (*Registry, error) {
+	path := filepath.Join(dir, "bindings.json")
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	r, err := New(dir, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// FromEnv loads the directory named by SCHEMA_DIR, or returns nil when unset.
+func FromEnv() (*Registry, error) {
+	dir := os.Getenv("SCHEMA_DIR")
+	if dir == "" {
+		return nil, nil
+	}
+	return Load(dir)
+}
+
+// Validate checks payload against the schema bound to queue. It returns a
+// *ValidationError when an enforced schema rejects it; warn-mode
+// violations are logged instead.
+func (r *Registry) Validate(queue string, payload []byte) error {
+	if r == nil {
+		return nil
+	}
+	for _, b := range r.bindings {
+		if !auth.MatchGlob(b.Queue, queue) {
+			continue
+		}
+		out := violations{limit: maxViolations}
+		b.validate(payload, &out)
+		if len(out.list) == 0 {
+			return nil
+		}
+		err := &ValidationError{Queue: queue, Schema: b.Schema, Violations: out.list}
+		if b.Mode == ModeWarn {
+			slog.Warn("[schema] payload violates schema, put anyway",
+				"queue", queue,
+				"schema", b.Schema,
+				"violations", len(out.list),
+				"error", err.Error(),
+				"id", "c55469ce-30d0-4328-908f-ad62691ab1cc")
+			return nil
+		}
+		return err
+	}
+	return nil
+}
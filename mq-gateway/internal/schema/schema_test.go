@@ -0,0 +1,246 @@
+package schema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const orderJSONSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["id", "lines"],
+  "additionalProperties": false,
+  "properties": {
+    "id": {"type": "string", "pattern": "^ORD-[0-9]+$"},
+    "priority": {"enum": ["low", "high"]},
+    "lines": {"type": "array", "minItems": 1, "items": {"$ref": "#/$defs/line"}}
+  },
+  "$defs": {
+    "line": {
+      "type": "object",
+      "required": ["sku", "qty"],
+      "properties": {
+        "sku": {"type": "string", "minLength": 1},
+        "qty": {"type": "integer", "minimum": 1}
+      }
+    }
+  }
+}`
+
+const orderXSD = `<?xml version="1.0"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns:tns="urn:orders" targetNamespace="urn:orders"
+           elementFormDefault="qualified">
+  <xs:element name="order" type="tns:Order"/>
+  <xs:complexType name="Order">
+    <xs:sequence>
+      <xs:element name="customer" type="xs:string"/>
+      <xs:element name="line" type="tns:Line" maxOccurs="unbounded"/>
+      <xs:choice minOccurs="0">
+        <xs:element name="pickup" type="xs:boolean"/>
+        <xs:element name="address" type="xs:string"/>
+      </xs:choice>
+    </xs:sequence>
+    <xs:attribute name="id" type="tns:OrderId" use="required"/>
+    <xs:attribute name="created" type="xs:dateTime"/>
+  </xs:complexType>
+  <xs:complexType name="Line">
+    <xs:sequence>
+      <xs:element name="qty" type="xs:positiveInteger"/>
+      <xs:element name="price">
+        <xs:simpleType>
+          <xs:restriction base="xs:decimal">
+            <xs:minExclusive value="0"/>
+            <xs:maxInclusive value="10000"/>
+          </xs:restriction>
+        </xs:simpleType>
+      </xs:element>
+    </xs:sequence>
+    <xs:attribute name="sku" type="xs:string" use="required"/>
+  </xs:complexType>
+  <xs:simpleType name="OrderId">
+    <xs:restriction base="xs:string">
+      <xs:pattern value="ORD-[0-9]+"/>
+      <xs:maxLength value="12"/>
+    </xs:restriction>
+  </xs:simpleType>
+</xs:schema>`
+
+func check(t *testing.T, v validator, payload string, want ...string) {
+	t.Helper()
+	var out violations
+	v([]byte(payload), &out)
+	var got []string
+	for _, x := range out.list {
+		got = append(got, x.Path+": "+x.Message)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("violations of %s:\n%s\nwant %d", payload, strings.Join(got, "\n"), len(want))
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(got[i], w) {
+			t.Errorf("violation %d = %q, want prefix %q", i, got[i], w)
+		}
+	}
+}
+
+func compileString(t *testing.T, name, content string) validator {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	v, err := compileFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestJSONSchema(t *testing.T) {
+	v := compileString(t, "order.schema.json", orderJSONSchema)
+	check(t, v, `{"id":"ORD-1","lines":[{"sku":"A","qty":2}]}`)
+	check(t, v, `{"id":"X-1","lines":[],"extra":1,"priority":"urgent"}`,
+		"/extra: is not an allowed property",
+		"/id: does not match pattern",
+		"/lines: has fewer than 1 items",
+		"/priority: is not one of the allowed values")
+	check(t, v, `{"id":"ORD-1","lines":[{"sku":"","qty":1.5},{"qty":0}]}`,
+		"/lines/0/qty: is number, want integer",
+		"/lines/0/sku: is shorter than 1 characters",
+		"/lines/1/sku: is required",
+		"/lines/1/qty: is less than 1")
+	check(t, v, `[1,2]`, "/: is array, want object")
+	check(t, v, `{"id":`, "/: payload is not valid JSON")
+
+	// Composition, and big integers kept exact.
+	v = compileString(t, "s.json", `{"oneOf":[{"type":"integer","maximum":9007199254740993},{"type":"string"}],"not":{"const":"no"}}`)
+	check(t, v, `9007199254740993`)
+	check(t, v, `"yes"`)
+	check(t, v, `"no"`, "/: matches a schema it must not match")
+	check(t, v, `true`, "/: matches 0 of the oneOf schemas")
+
+	for _, bad := range []string{`[]`, `{"type":"colour"}`, `{"pattern":"("}`, `{"$ref":"#/$defs/missing"}`, `{"$ref":"other.json"}`} {
+		path := filepath.Join(t.TempDir(), "bad.json")
+		_ = os.WriteFile(path, []byte(bad), 0o600)
+		if _, err := compileFile(path); err == nil {
+			t.Errorf("schema %s compiled", bad)
+		}
+	}
+}
+
+func TestXSD(t *testing.T) {
+	v := compileString(t, "order.xsd", orderXSD)
+	check(t, v, `<order xmlns="urn:orders" id="ORD-7" created="2026-10-19T08:30:00.5Z">
+		<customer>ACME</customer>
+		<line sku="A-1"><qty>2</qty><price>9.95</price></line>
+		<line sku="B-2"><qty>1</qty><price>100</price></line>
+		<pickup>true</pickup>
+	</order>`)
+	check(t, v, `<o:order xmlns:o="urn:orders" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" id="ORD-7">
+		<o:customer>ACME</o:customer><o:line sku="A"><o:qty>1</o:qty><o:price>1</o:price></o:line></o:order>`)
+
+	check(t, v, `<order xmlns="urn:orders" id="BAD" colour="red">
+		<customer>ACME</customer>
+		<line><qty>0</qty><price>0</price></line>
+		<line sku="B"><qty>x</qty><price>1</price></line>
+	</order>`,
+		"/order/@id: \"BAD\" does not match pattern",
+		"/order/@colour: attribute is not allowed",
+		"/order/line[1]/@sku: is required",
+		"/order/line[1]/qty: 0 is out of range for xs:positiveInteger",
+		"/order/line[1]/price: 0 must be greater than 0",
+		"/order/line[2]/qty: \"x\" is not an integer")
+	check(t, v, `<order xmlns="urn:orders" id="ORD-1"><line sku="A"><qty>1</qty><price>1</price></line></order>`,
+		"/order/line: unexpected element <line>, want <customer>")
+	check(t, v, `<order xmlns="urn:orders" id="ORD-1"><customer>A</customer></order>`,
+		"/order: missing element <line>")
+	check(t, v, `<order xmlns="urn:orders" id="ORD-1"><customer>A</customer><line sku="A"><qty>1</qty><price>1</price></line><pickup>1</pickup><address>x</address></order>`,
+		"/order/address: unexpected element <address>")
+	check(t, v, `<order xmlns="urn:orders" id="ORD-1">text<customer>A</customer><line sku="A"><qty>1</qty><price>1</price></line></order>`,
+		"/order: text content is not allowed here")
+	check(t, v, `<order id="ORD-1"/>`, "/order: root element <order> is not declared by the schema")
+	check(t, v, `<order xmlns="urn:orders"><customer>`, "/: payload is not well-formed XML")
+
+	for _, bad := range []string{
+		`<schema/>`,
+		`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:include schemaLocation="x.xsd"/></xs:schema>`,
+		`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:element name="a" type="xs:gibberish"/></xs:schema>`,
+		`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:element name="a" type="Missing"/></xs:schema>`,
+	} {
+		if _, err := compileXSD([]byte(bad)); err == nil {
+			t.Errorf("schema %s compiled", bad)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"order.schema.json": orderJSONSchema,
+		"order.xsd":         orderXSD,
+		"bindings.json": `{"bindings":[
+			{"queue":"ORDERS.XML.*","schema":"order.xsd"},
+			{"queue":"ORDERS.TRIAL","schema":"order.schema.json","mode":"warn"},
+			{"queue":"ORDERS.*","schema":"order.schema.json"}
+		]}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("SCHEMA_DIR", dir)
+	r, err := FromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Validate("ORDERS.EU", []byte(`{"id":"ORD-1","lines":[{"sku":"A","qty":1}]}`)); err != nil {
+		t.Fatal(err)
+	}
+	err = r.Validate("ORDERS.EU", []byte(`{"lines":[]}`))
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Schema != "order.schema.json" || len(verr.Violations) != 2 {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if !strings.Contains(err.Error(), "/id: is required") {
+		t.Fatalf("error text %q", err)
+	}
+
+	// The first matching binding wins.
+	if err := r.Validate("ORDERS.XML.EU", []byte(`{"id":"ORD-1","lines":[{"sku":"A","qty":1}]}`)); err == nil {
+		t.Fatal("JSON accepted by the XSD binding")
+	}
+	// Warn mode logs and lets the put through; unbound queues are not checked.
+	if err := r.Validate("ORDERS.TRIAL", []byte(`{}`)); err != nil {
+		t.Fatalf("warn mode: %v", err)
+	}
+	if err := r.Validate("INVOICES", []byte(`not json`)); err != nil {
+		t.Fatalf("unbound queue: %v", err)
+	}
+	var nilRegistry *Registry
+	if err := nilRegistry.Validate("ORDERS.EU", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Too many violations are cut off.
+	many := `{"id":"ORD-1","lines":[` + strings.Repeat(`{},`, 30) + `{}]}`
+	if errors.As(r.Validate("ORDERS.EU", []byte(many)), &verr); len(verr.Violations) != maxViolations {
+		t.Fatalf("%d violations reported", len(verr.Violations))
+	}
+
+	for _, cfg := range []Config{
+		{Bindings: []Binding{{Queue: "Q"}}},
+		{Bindings: []Binding{{Queue: "Q", Schema: "../etc/passwd"}}},
+		{Bindings: []Binding{{Queue: "Q", Schema: "missing.json"}}},
+		{Bindings: []Binding{{Queue: "Q", Schema: "order.xsd", Mode: "audit"}}},
+		{Mode: "strict"},
+	} {
+		if _, err := New(dir, cfg); err == nil {
+			t.Errorf("New(%+v) succeeded", cfg)
+		}
+	}
+}
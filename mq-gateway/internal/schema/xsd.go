@@ -0,0 +1,1148 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	xsdNS = "http://www.w3.org/2001/XMLSchema"
+	xsiNS = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+// xmlNode is a parsed XML element, of a schema document or of a payload.
+type xmlNode struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*xmlNode
+	text     string
+	// ns maps the prefixes in scope to namespaces, for QName attribute
+	// values such as type="tns:OrderType".
+	ns map[string]string
+}
+
+func (n *xmlNode) attr(local string) (string, bool) {
+	for _, a := range n.attrs {
+		if a.Name.Space == "" && a.Name.Local == local {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// qname resolves a QName attribute value in n's scope; an unprefixed name
+// is in the default namespace.
+func (n *xmlNode) qname(v string) (xml.Name, error) {
+	prefix, local, ok := strings.Cut(v, ":")
+	if !ok {
+		return xml.Name{Space: n.ns[""], Local: v}, nil
+	}
+	space, known := n.ns[prefix]
+	if !known {
+		return xml.Name{}, fmt.Errorf("undeclared namespace prefix %q in %q", prefix, v)
+	}
+	return xml.Name{Space: space, Local: local}, nil
+}
+
+// parseXML reads a document into a tree of elements.
+func parseXML(raw []byte) (*xmlNode, error) {
+	dec := xml.NewDecoder(bytes.NewReader(raw))
+	var root *xmlNode
+	var stack []*xmlNode
+	var open []xml.Name // raw names, to match end tags
+	for {
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{ns: map[string]string{"xml": "http://www.w3.org/XML/1998/namespace"}}
+			if len(stack) > 0 {
+				n.ns = stack[len(stack)-1].ns
+			}
+			// Copy the parent's scope before this element's declarations.
+			scoped := false
+			for _, a := range t.Attr {
+				if a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns") {
+					if !scoped {
+						ns := make(map[string]string, len(n.ns)+1)
+						for k, v := range n.ns {
+							ns[k] = v
+						}
+						n.ns, scoped = ns, true
+					}
+					if a.Name.Space == "xmlns" {
+						n.ns[a.Name.Local] = a.Value
+					} else {
+						n.ns[""] = a.Value
+					}
+				}
+			}
+			if n.name, err = n.resolve(t.Name, true); err != nil {
+				return nil, err
+			}
+			for _, a := range t.Attr {
+				if a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns") {
+					continue
+				}
+				if a.Name, err = n.resolve(a.Name, false); err != nil {
+					return nil, err
+				}
+				n.attrs = append(n.attrs, a)
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			} else if root != nil {
+				return nil, errors.New("more than one root element")
+			} else {
+				root = n
+			}
+			stack = append(stack, n)
+			open = append(open, t.Name)
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("unexpected end tag </%s>", t.Name.Local)
+			}
+			if t.Name != open[len(open)-1] {
+				return nil, fmt.Errorf("end tag </%s> does not match <%s>", t.Name.Local, open[len(open)-1].Local)
+			}
+			stack, open = stack[:len(stack)-1], open[:len(open)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			} else if len(bytes.TrimSpace(t)) > 0 {
+				return nil, errors.New("text outside the root element")
+			}
+		case xml.Directive:
+			if bytes.HasPrefix(bytes.TrimSpace(t), []byte("DOCTYPE")) {
+				return nil, errors.New("DOCTYPE is not allowed")
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("no root element")
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("element <%s> is not closed", stack[len(stack)-1].name.Local)
+	}
+	return root, nil
+}
+
+// resolve maps a raw prefixed name to its namespace. Unprefixed attributes
+// are in no namespace.
+func (n *xmlNode) resolve(raw xml.Name, element bool) (xml.Name, error) {
+	if raw.Space == "" {
+		if element {
+			return xml.Name{Space: n.ns[""], Local: raw.Local}, nil
+		}
+		return raw, nil
+	}
+	space, ok := n.ns[raw.Space]
+	if !ok {
+		return xml.Name{}, fmt.Errorf("undeclared namespace prefix %q", raw.Space)
+	}
+	return xml.Name{Space: space, Local: raw.Local}, nil
+}
+
+// xsdSchema is a compiled XML Schema. Global and local element, complex and
+// simple type declarations are supported with sequence, choice, all and any
+// particles, attributes, simpleContent, complexContent extension, and the
+// usual restriction facets. Includes, imports, named groups and attribute
+// groups, substitution groups, identity constraints, lists and unions are not.
+type xsdSchema struct {
+	targetNS  string
+	qualified bool
+	elements  map[xml.Name]*elemDecl
+	types     map[xml.Name]*xsdType
+}
+
+type elemDecl struct {
+	name     xml.Name
+	typ      *xsdType
+	nillable bool
+}
+
+type xsdType struct {
+	// anyType accepts any content and attributes.
+	anyType bool
+	// simple is set for simple types; attributes and elements are not allowed.
+	simple *simpleType
+	// text is the type of a complex type's simpleContent.
+	text    *simpleType
+	mixed   bool
+	content *particle
+	attrs   []*attrDecl
+	anyAttr bool
+}
+
+type attrDecl struct {
+	name     string
+	typ      *simpleType
+	required bool
+	fixed    *string
+}
+
+type particleKind int
+
+const (
+	pElement particleKind = iota
+	pSequence
+	pChoice
+	pAll
+	pAny
+)
+
+type particle struct {
+	kind     particleKind
+	elem     *elemDecl
+	children []*particle
+	min, max int // max < 0 is unbounded
+	// anyNS is the namespace constraint of an xs:any.
+	anyNS string
+}
+
+// simpleType is a builtin type narrowed by facets; base holds the facets of
+// the type it restricts.
+type simpleType struct {
+	builtin  string
+	base     *simpleType
+	enum     []string
+	patterns []*regexp.Regexp
+	length   *int
+	minLen   *int
+	maxLen   *int
+	minIncl  *big.Rat
+	maxIncl  *big.Rat
+	minExcl  *big.Rat
+	maxExcl  *big.Rat
+}
+
+// xsdCompiler compiles named types on first use, so they may be declared in
+// any order and refer to each other.
+type xsdCompiler struct {
+	s         *xsdSchema
+	root      *xmlNode
+	typeNodes map[xml.Name]*xmlNode
+	simple    map[xml.Name]*simpleType
+	state     map[xml.Name]int
+}
+
+const (
+	compiling = 1
+	compiled  = 2
+)
+
+// compileXSD parses and compiles an XML Schema document.
+func compileXSD(raw []byte) (*xsdSchema, error) {
+	root, err := parseXML(raw)
+	if err != nil {
+		return nil, err
+	}
+	if root.name != (xml.Name{Space: xsdNS, Local: "schema"}) {
+		return nil, errors.New("root element is not xs:schema")
+	}
+	s := &xsdSchema{
+		elements: make(map[xml.Name]*elemDecl),
+		types:    make(map[xml.Name]*xsdType),
+	}
+	s.targetNS, _ = root.attr("targetNamespace")
+	efd, _ := root.attr("elementFormDefault")
+	s.qualified = efd == "qualified"
+	c := &xsdCompiler{
+		s:         s,
+		root:      root,
+		typeNodes: make(map[xml.Name]*xmlNode),
+		simple:    make(map[xml.Name]*simpleType),
+		state:     make(map[xml.Name]int),
+	}
+
+	var elems []*xmlNode
+	for _, n := range root.children {
+		if n.name.Space != xsdNS {
+			continue
+		}
+		name, _ := n.attr("name")
+		qn := xml.Name{Space: s.targetNS, Local: name}
+		switch n.name.Local {
+		case "element":
+			if name == "" {
+				return nil, errors.New("global xs:element without a name")
+			}
+			s.elements[qn] = &elemDecl{name: qn}
+			elems = append(elems, n)
+		case "complexType", "simpleType":
+			if name == "" {
+				return nil, fmt.Errorf("global xs:%s without a name", n.name.Local)
+			}
+			c.typeNodes[qn] = n
+			if n.name.Local == "complexType" {
+				s.types[qn] = &xsdType{}
+			} else {
+				c.simple[qn] = &simpleType{}
+			}
+		case "annotation", "notation":
+		default:
+			return nil, fmt.Errorf("xs:%s is not supported", n.name.Local)
+		}
+	}
+	for qn := range c.typeNodes {
+		if _, err := c.namedType(qn); err != nil {
+			return nil, err
+		}
+	}
+	for _, n := range elems {
+		name, _ := n.attr("name")
+		if err := c.fillElement(s.elements[xml.Name{Space: s.targetNS, Local: name}], n); err != nil {
+			return nil, err
+		}
+	}
+	if len(s.elements) == 0 {
+		return nil, errors.New("schema declares no global element")
+	}
+	return s, nil
+}
+
+// namedType returns the type called qn, compiling it first if needed.
+func (c *xsdCompiler) namedType(qn xml.Name) (*xsdType, error) {
+	if qn.Space == xsdNS {
+		if qn.Local == "anyType" {
+			return &xsdType{anyType: true}, nil
+		}
+		st, err := c.namedSimple(qn)
+		if err != nil {
+			return nil, err
+		}
+		return &xsdType{simple: st}, nil
+	}
+	if st, ok := c.simple[qn]; ok {
+		if _, err := c.namedSimple(qn); err != nil {
+			return nil, err
+		}
+		return &xsdType{simple: st}, nil
+	}
+	t, ok := c.s.types[qn]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q", qn.Local)
+	}
+	if c.state[qn] == 0 {
+		c.state[qn] = compiling
+		if err := c.fillComplex(t, c.typeNodes[qn]); err != nil {
+			return nil, fmt.Errorf("type %q: %w", qn.Local, err)
+		}
+		c.state[qn] = compiled
+	}
+	return t, nil
+}
+
+// namedSimple returns the simple type called qn.
+func (c *xsdCompiler) namedSimple(qn xml.Name) (*simpleType, error) {
+	if qn.Space == xsdNS {
+		if !builtinKnown(qn.Local) {
+			return nil, fmt.Errorf("builtin type xs:%s is not supported", qn.Local)
+		}
+		return &simpleType{builtin: qn.Local}, nil
+	}
+	st, ok := c.simple[qn]
+	if !ok {
+		return nil, fmt.Errorf("unknown simple type %q", qn.Local)
+	}
+	switch c.state[qn] {
+	case compiling:
+		return nil, fmt.Errorf("simple type %q derives from itself", qn.Local)
+	case 0:
+		c.state[qn] = compiling
+		if err := c.fillSimple(st, c.typeNodes[qn]); err != nil {
+			return nil, fmt.Errorf("type %q: %w", qn.Local, err)
+		}
+		c.state[qn] = compiled
+	}
+	return st, nil
+}
+
+// typeOf compiles the type of an element or attribute declaration: its type
+// attribute or its anonymous type child. Without either, def is used.
+func (c *xsdCompiler) typeOf(n *xmlNode, def *xsdType) (*xsdType, error) {
+	if tn, ok := n.attr("type"); ok {
+		qn, err := n.qname(tn)
+		if err != nil {
+			return nil, err
+		}
+		return c.namedType(qn)
+	}
+	for _, ch := range xsdChildren(n) {
+		switch ch.name.Local {
+		case "complexType":
+			t := &xsdType{}
+			return t, c.fillComplex(t, ch)
+		case "simpleType":
+			st := &simpleType{}
+			return &xsdType{simple: st}, c.fillSimple(st, ch)
+		}
+	}
+	return def, nil
+}
+
+// xsdChildren returns the XML Schema children of n, without annotations.
+func xsdChildren(n *xmlNode) []*xmlNode {
+	var out []*xmlNode
+	for _, ch := range n.children {
+		if ch.name.Space == xsdNS && ch.name.Local != "annotation" {
+			out = append(out, ch)
+		}
+	}
+	return out
+}
+
+func (c *xsdCompiler) fillElement(d *elemDecl, n *xmlNode) error {
+	var err error
+	if d.typ, err = c.typeOf(n, &xsdType{anyType: true}); err != nil {
+		return fmt.Errorf("element %q: %w", d.name.Local, err)
+	}
+	nillable, _ := n.attr("nillable")
+	d.nillable = nillable == "true"
+	return nil
+}
+
+func (c *xsdCompiler) fillComplex(t *xsdType, n *xmlNode) error {
+	mixed, _ := n.attr("mixed")
+	t.mixed = mixed == "true"
+	for _, ch := range xsdChildren(n) {
+		switch ch.name.Local {
+		case "sequence", "choice", "all":
+			p, err := c.particle(ch)
+			if err != nil {
+				return err
+			}
+			t.content = p
+		case "attribute", "anyAttribute":
+			if err := c.addAttr(t, ch); err != nil {
+				return err
+			}
+		case "simpleContent":
+			if err := c.fillSimpleContent(t, ch); err != nil {
+				return err
+			}
+		case "complexContent":
+			if err := c.fillComplexContent(t, ch); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("xs:%s is not supported in a complex type", ch.name.Local)
+		}
+	}
+	return nil
+}
+
+func (c *xsdCompiler) addAttr(t *xsdType, n *xmlNode) error {
+	if n.name.Local == "anyAttribute" {
+		t.anyAttr = true
+		return nil
+	}
+	name, _ := n.attr("name")
+	if name == "" {
+		return errors.New("xs:attribute without a name (attribute references are not supported)")
+	}
+	at, err := c.typeOf(n, &xsdType{simple: &simpleType{builtin: "anySimpleType"}})
+	if err != nil {
+		return fmt.Errorf("attribute %q: %w", name, err)
+	}
+	if at.simple == nil {
+		return fmt.Errorf("attribute %q: type is not simple", name)
+	}
+	use, _ := n.attr("use")
+	if use == "prohibited" {
+		return nil
+	}
+	a := &attrDecl{name: name, typ: at.simple, required: use == "required"}
+	if f, ok := n.attr("fixed"); ok {
+		a.fixed = &f
+	}
+	t.attrs = append(t.attrs, a)
+	return nil
+}
+
+func (c *xsdCompiler) fillSimpleContent(t *xsdType, n *xmlNode) error {
+	for _, ch := range xsdChildren(n) {
+		base, _ := ch.attr("base")
+		qn, err := ch.qname(base)
+		if err != nil {
+			return err
+		}
+		bt, err := c.namedType(qn)
+		if err != nil {
+			return err
+		}
+		switch {
+		case bt.simple != nil:
+			t.text = bt.simple
+		case bt.text != nil:
+			t.text = bt.text
+			t.attrs = append(t.attrs, bt.attrs...)
+			t.anyAttr = bt.anyAttr
+		default:
+			return fmt.Errorf("simpleContent base %q has no simple content", base)
+		}
+		switch ch.name.Local {
+		case "extension":
+		case "restriction":
+			st := &simpleType{base: t.text}
+			if err := c.facets(st, ch); err != nil {
+				return err
+			}
+			t.text = st
+		default:
+			return fmt.Errorf("xs:%s is not supported in simpleContent", ch.name.Local)
+		}
+		for _, a := range xsdChildren(ch) {
+			if a.name.Local == "attribute" || a.name.Local == "anyAttribute" {
+				if err := c.addAttr(t, a); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (c *xsdCompiler) fillComplexContent(t *xsdType, n *xmlNode) error {
+	for _, ch := range xsdChildren(n) {
+		if ch.name.Local != "extension" {
+			return fmt.Errorf("xs:%s is not supported in complexContent", ch.name.Local)
+		}
+		base, _ := ch.attr("base")
+		qn, err := ch.qname(base)
+		if err != nil {
+			return err
+		}
+		if c.state[qn] == compiling {
+			return fmt.Errorf("type %q extends itself", base)
+		}
+		bt, err := c.namedType(qn)
+		if err != nil {
+			return err
+		}
+		if bt.anyType {
+			bt = &xsdType{}
+		}
+		t.attrs = append(t.attrs, bt.attrs...)
+		t.anyAttr = t.anyAttr || bt.anyAttr
+		t.content = bt.content
+		for _, g := range xsdChildren(ch) {
+			switch g.name.Local {
+			case "sequence", "choice", "all":
+				p, err := c.particle(g)
+				if err != nil {
+					return err
+				}
+				// The extension's particle follows the base's.
+				if t.content == nil {
+					t.content = p
+				} else {
+					t.content = &particle{kind: pSequence, min: 1, max: 1, children: []*particle{t.content, p}}
+				}
+			case "attribute", "anyAttribute":
+				if err := c.addAttr(t, g); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("xs:%s is not supported in an extension", g.name.Local)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *xsdCompiler) particle(n *xmlNode) (*particle, error) {
+	p := &particle{min: 1, max: 1}
+	if v, ok := n.attr("minOccurs"); ok {
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 0 {
+			return nil, fmt.Errorf("invalid minOccurs %q", v)
+		}
+		p.min = i
+	}
+	if v, ok := n.attr("maxOccurs"); ok {
+		if v == "unbounded" {
+			p.max = -1
+		} else {
+			i, err := strconv.Atoi(v)
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("invalid maxOccurs %q", v)
+			}
+			p.max = i
+		}
+	}
+	switch n.name.Local {
+	case "element":
+		p.kind = pElement
+		if ref, ok := n.attr("ref"); ok {
+			qn, err := n.qname(ref)
+			if err != nil {
+				return nil, err
+			}
+			if p.elem = c.s.elements[qn]; p.elem == nil {
+				return nil, fmt.Errorf("element ref %q is not a global element", ref)
+			}
+			return p, nil
+		}
+		name, _ := n.attr("name")
+		if name == "" {
+			return nil, errors.New("xs:element without a name or ref")
+		}
+		d := &elemDecl{name: xml.Name{Local: name}}
+		form, _ := n.attr("form")
+		if form == "qualified" || (form == "" && c.s.qualified) {
+			d.name.Space = c.s.targetNS
+		}
+		p.elem = d
+		return p, c.fillElement(d, n)
+	case "any":
+		p.kind = pAny
+		p.anyNS, _ = n.attr("namespace")
+		return p, nil
+	case "sequence", "choice", "all":
+		p.kind = map[string]particleKind{"sequence": pSequence, "choice": pChoice, "all": pAll}[n.name.Local]
+		for _, ch := range xsdChildren(n) {
+			cp, err := c.particle(ch)
+			if err != nil {
+				return nil, err
+			}
+			if p.kind == pAll && (cp.kind != pElement || cp.max > 1) {
+				return nil, errors.New("xs:all may only hold elements occurring at most once")
+			}
+			p.children = append(p.children, cp)
+		}
+		return p, nil
+	}
+	return nil, fmt.Errorf("xs:%s is not supported in a content model", n.name.Local)
+}
+
+func (c *xsdCompiler) fillSimple(st *simpleType, n *xmlNode) error {
+	kids := xsdChildren(n)
+	if len(kids) != 1 || kids[0].name.Local != "restriction" {
+		return errors.New("only xs:restriction is supported in a simple type")
+	}
+	r := kids[0]
+	if base, ok := r.attr("base"); ok {
+		qn, err := r.qname(base)
+		if err != nil {
+			return err
+		}
+		if st.base, err = c.namedSimple(qn); err != nil {
+			return err
+		}
+	} else {
+		for _, ch := range xsdChildren(r) {
+			if ch.name.Local == "simpleType" {
+				st.base = &simpleType{}
+				if err := c.fillSimple(st.base, ch); err != nil {
+					return err
+				}
+			}
+		}
+		if st.base == nil {
+			return errors.New("xs:restriction without a base")
+		}
+	}
+	return c.facets(st, r)
+}
+
+// facets reads the constraining facets of a restriction into st.
+func (c *xsdCompiler) facets(st *simpleType, r *xmlNode) error {
+	for _, f := range xsdChildren(r) {
+		v, _ := f.attr("value")
+		count := func() (*int, error) {
+			i, err := strconv.Atoi(v)
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("xs:%s: invalid value %q", f.name.Local, v)
+			}
+			return &i, nil
+		}
+		rat := func() (*big.Rat, error) {
+			x, ok := new(big.Rat).SetString(strings.TrimSpace(v))
+			if !ok {
+				return nil, fmt.Errorf("xs:%s: only numeric bounds are supported, got %q", f.name.Local, v)
+			}
+			return x, nil
+		}
+		var err error
+		switch f.name.Local {
+		case "enumeration":
+			st.enum = append(st.enum, v)
+		case "pattern":
+			// XSD patterns match the whole value.
+			re, rerr := regexp.Compile("^(?:" + v + ")$")
+			if rerr != nil {
+				return fmt.Errorf("xs:pattern %q: %w", v, rerr)
+			}
+			st.patterns = append(st.patterns, re)
+		case "length":
+			st.length, err = count()
+		case "minLength":
+			st.minLen, err = count()
+		case "maxLength":
+			st.maxLen, err = count()
+		case "minInclusive":
+			st.minIncl, err = rat()
+		case "maxInclusive":
+			st.maxIncl, err = rat()
+		case "minExclusive":
+			st.minExcl, err = rat()
+		case "maxExclusive":
+			st.maxExcl, err = rat()
+		case "whiteSpace", "totalDigits", "fractionDigits", "simpleType":
+		default:
+			return fmt.Errorf("facet xs:%s is not supported", f.name.Local)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// root returns the builtin type st is ultimately derived from.
+func (st *simpleType) root() string {
+	for st.base != nil {
+		st = st.base
+	}
+	return st.builtin
+}
+
+// check returns why v is not a valid value of st, or "".
+func (st *simpleType) check(v string) string {
+	builtin := st.root()
+	if builtin != "string" && builtin != "normalizedString" && builtin != "anySimpleType" {
+		v = strings.Join(strings.Fields(v), " ")
+	}
+	return st.checkValue(v, builtin)
+}
+
+func (st *simpleType) checkValue(v, builtin string) string {
+	if st.base != nil {
+		if msg := st.base.checkValue(v, builtin); msg != "" {
+			return msg
+		}
+	} else if msg := checkBuiltin(st.builtin, v); msg != "" {
+		return msg
+	}
+	if len(st.enum) > 0 {
+		found := false
+		for _, e := range st.enum {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("%q is not one of %s", v, strings.Join(st.enum, ", "))
+		}
+	}
+	for _, re := range st.patterns {
+		if !re.MatchString(v) {
+			return fmt.Sprintf("%q does not match pattern %s", v, strings.TrimSuffix(strings.TrimPrefix(re.String(), "^(?:"), ")$"))
+		}
+	}
+	n := len([]rune(v))
+	if builtin == "hexBinary" {
+		n = len(v) / 2
+	}
+	if st.length != nil && n != *st.length {
+		return fmt.Sprintf("length is %d, want %d", n, *st.length)
+	}
+	if st.minLen != nil && n < *st.minLen {
+		return fmt.Sprintf("is shorter than %d", *st.minLen)
+	}
+	if st.maxLen != nil && n > *st.maxLen {
+		return fmt.Sprintf("is longer than %d", *st.maxLen)
+	}
+	if st.minIncl != nil || st.maxIncl != nil || st.minExcl != nil || st.maxExcl != nil {
+		x, ok := new(big.Rat).SetString(v)
+		if !ok {
+			return fmt.Sprintf("%q is not a number", v)
+		}
+		switch {
+		case st.minIncl != nil && x.Cmp(st.minIncl) < 0:
+			return fmt.Sprintf("%s is less than %s", v, st.minIncl.RatString())
+		case st.maxIncl != nil && x.Cmp(st.maxIncl) > 0:
+			return fmt.Sprintf("%s is greater than %s", v, st.maxIncl.RatString())
+		case st.minExcl != nil && x.Cmp(st.minExcl) <= 0:
+			return fmt.Sprintf("%s must be greater than %s", v, st.minExcl.RatString())
+		case st.maxExcl != nil && x.Cmp(st.maxExcl) >= 0:
+			return fmt.Sprintf("%s must be less than %s", v, st.maxExcl.RatString())
+		}
+	}
+	return ""
+}
+
+var (
+	decimalRE  = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+	integerRE  = regexp.MustCompile(`^[+-]?\d+$`)
+	timezoneRE = regexp.MustCompile(`(Z|[+-]\d{2}:\d{2})$`)
+)
+
+// integerRanges bounds the builtin integer types; a nil bound is unbounded.
+var integerRanges = map[string][2]*big.Int{
+	"integer":            {nil, nil},
+	"long":               {big.NewInt(-1 << 63), big.NewInt(1<<63 - 1)},
+	"int":                {big.NewInt(-1 << 31), big.NewInt(1<<31 - 1)},
+	"short":              {big.NewInt(-1 << 15), big.NewInt(1<<15 - 1)},
+	"byte":               {big.NewInt(-1 << 7), big.NewInt(1<<7 - 1)},
+	"nonNegativeInteger": {big.NewInt(0), nil},
+	"positiveInteger":    {big.NewInt(1), nil},
+	"nonPositiveInteger": {nil, big.NewInt(0)},
+	"negativeInteger":    {nil, big.NewInt(-1)},
+	"unsignedLong":       {big.NewInt(0), new(big.Int).SetUint64(1<<64 - 1)},
+	"unsignedInt":        {big.NewInt(0), big.NewInt(1<<32 - 1)},
+	"unsignedShort":      {big.NewInt(0), big.NewInt(1<<16 - 1)},
+	"unsignedByte":       {big.NewInt(0), big.NewInt(1<<8 - 1)},
+}
+
+// stringTypes are the builtin types checked only by their facets.
+var stringTypes = map[string]bool{
+	"string": true, "normalizedString": true, "token": true, "anySimpleType": true,
+	"anyURI": true, "QName": true, "NCName": true, "Name": true, "ID": true,
+	"IDREF": true, "language": true, "NMTOKEN": true,
+	"gYear": true, "gYearMonth": true, "gMonth": true, "gMonthDay": true, "gDay": true,
+	"duration": true,
+}
+
+func builtinKnown(name string) bool {
+	if stringTypes[name] {
+		return true
+	}
+	if _, ok := integerRanges[name]; ok {
+		return true
+	}
+	switch name {
+	case "boolean", "decimal", "float", "double", "date", "dateTime", "time", "base64Binary", "hexBinary":
+		return true
+	}
+	return false
+}
+
+// checkBuiltin returns why v is not in the lexical space of the builtin type.
+func checkBuiltin(name, v string) string {
+	if stringTypes[name] {
+		return ""
+	}
+	if bounds, ok := integerRanges[name]; ok {
+		x, ok := new(big.Int).SetString(strings.TrimPrefix(v, "+"), 10)
+		if !integerRE.MatchString(v) || !ok {
+			return fmt.Sprintf("%q is not an integer", v)
+		}
+		if (bounds[0] != nil && x.Cmp(bounds[0]) < 0) || (bounds[1] != nil && x.Cmp(bounds[1]) > 0) {
+			return fmt.Sprintf("%s is out of range for xs:%s", v, name)
+		}
+		return ""
+	}
+	bad := false
+	switch name {
+	case "boolean":
+		bad = v != "true" && v != "false" && v != "1" && v != "0"
+	case "decimal":
+		bad = !decimalRE.MatchString(v)
+	case "float", "double":
+		if v != "INF" && v != "-INF" && v != "NaN" {
+			_, err := strconv.ParseFloat(v, 64)
+			bad = err != nil || strings.ContainsAny(v, "xXpP_")
+		}
+	case "date", "dateTime", "time":
+		layout := map[string]string{"date": "2006-01-02", "dateTime": "2006-01-02T15:04:05", "time": "15:04:05"}[name]
+		base := timezoneRE.ReplaceAllString(v, "")
+		if name != "date" {
+			// Fractional seconds are optional.
+			if i := strings.IndexByte(base, '.'); i >= 0 {
+				if i == len(base)-1 || strings.Trim(base[i+1:], "0123456789") != "" {
+					bad = true
+				}
+				base = base[:i]
+			}
+		}
+		if _, err := time.Parse(layout, base); err != nil {
+			bad = true
+		}
+	case "base64Binary":
+		_, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(v), ""))
+		bad = err != nil
+	case "hexBinary":
+		_, err := hex.DecodeString(v)
+		bad = err != nil
+	}
+	if bad {
+		return fmt.Sprintf("%q is not a valid xs:%s", v, name)
+	}
+	return ""
+}
+
+// validate appends the violations of the document doc to out.
+func (s *xsdSchema) validate(doc *xmlNode, out *violations) {
+	path := "/" + doc.name.Local
+	d, ok := s.elements[doc.name]
+	if !ok {
+		msg := fmt.Sprintf("root element <%s> is not declared by the schema", doc.name.Local)
+		if doc.name.Space != s.targetNS {
+			msg += fmt.Sprintf(" (namespace %q, schema namespace %q)", doc.name.Space, s.targetNS)
+		}
+		out.add(path, msg)
+		return
+	}
+	d.validate(doc, path, out)
+}
+
+func (d *elemDecl) validate(n *xmlNode, path string, out *violations) {
+	if out.full() {
+		return
+	}
+	t := d.typ
+	if t.anyType {
+		return
+	}
+	for _, a := range n.attrs {
+		if a.Name.Space == xsiNS && a.Name.Local == "nil" && a.Value == "true" {
+			if !d.nillable {
+				out.add(path, "element is not nillable")
+			} else if len(n.children) > 0 || strings.TrimSpace(n.text) != "" {
+				out.add(path, "nil element must be empty")
+			}
+			return
+		}
+	}
+	t.checkAttrs(n, path, out)
+
+	if st := t.simple; st != nil || t.text != nil {
+		if st == nil {
+			st = t.text
+		}
+		if len(n.children) > 0 {
+			out.add(path+"/"+n.children[0].name.Local, "element content is not allowed here")
+			return
+		}
+		if msg := st.check(n.text); msg != "" {
+			out.add(path, msg)
+		}
+		return
+	}
+
+	if !t.mixed && strings.TrimSpace(n.text) != "" {
+		out.add(path, "text content is not allowed here")
+	}
+	if t.content == nil {
+		if len(n.children) > 0 {
+			out.add(path+"/"+n.children[0].name.Local, "element content is not allowed here")
+		}
+		return
+	}
+	assigned := make([]*elemDecl, len(n.children))
+	m := &matcher{kids: n.children, assigned: assigned}
+	pos, ok := m.match(t.content, 0)
+	switch {
+	case !ok && m.failPos >= len(n.children):
+		out.add(path, fmt.Sprintf("missing element %s", m.expected))
+	case !ok:
+		out.add(childPath(path, n.children, m.failPos), fmt.Sprintf("unexpected element <%s>, want %s", n.children[m.failPos].name.Local, m.expected))
+	case pos < len(n.children):
+		out.add(childPath(path, n.children, pos), fmt.Sprintf("unexpected element <%s>", n.children[pos].name.Local))
+	}
+	if !ok {
+		return
+	}
+	for i := 0; i < pos; i++ {
+		if assigned[i] != nil {
+			assigned[i].validate(n.children[i], childPath(path, n.children, i), out)
+		}
+	}
+}
+
+func (t *xsdType) checkAttrs(n *xmlNode, path string, out *violations) {
+	seen := make(map[string]bool)
+	for _, a := range n.attrs {
+		if a.Name.Space == xsiNS || a.Name.Space == "http://www.w3.org/XML/1998/namespace" {
+			continue
+		}
+		var decl *attrDecl
+		if a.Name.Space == "" {
+			for _, ad := range t.attrs {
+				if ad.name == a.Name.Local {
+					decl = ad
+				}
+			}
+		}
+		ap := path + "/@" + a.Name.Local
+		if decl == nil {
+			if !t.anyAttr {
+				out.add(ap, "attribute is not allowed")
+			}
+			continue
+		}
+		seen[decl.name] = true
+		if msg := decl.typ.check(a.Value); msg != "" {
+			out.add(ap, msg)
+		} else if decl.fixed != nil && a.Value != *decl.fixed {
+			out.add(ap, fmt.Sprintf("must be %q", *decl.fixed))
+		}
+	}
+	for _, ad := range t.attrs {
+		if ad.required && !seen[ad.name] {
+			out.add(path+"/@"+ad.name, "is required")
+		}
+	}
+}
+
+// childPath names the i-th child of the element at path, with a 1-based
+// index when it has siblings of the same name.
+func childPath(path string, kids []*xmlNode, i int) string {
+	name := kids[i].name
+	idx, total := 0, 0
+	for j, k := range kids {
+		if k.name == name {
+			total++
+			if j <= i {
+				idx++
+			}
+		}
+	}
+	if total > 1 {
+		return fmt.Sprintf("%s/%s[%d]", path, name.Local, idx)
+	}
+	return path + "/" + name.Local
+}
+
+// matcher matches child elements against a content model. XML Schema
+// requires content models to be deterministic, so a greedy match suffices.
+type matcher struct {
+	kids     []*xmlNode
+	assigned []*elemDecl
+	// failPos and expected describe the furthest failure.
+	failPos  int
+	expected string
+}
+
+func (m *matcher) fail(pos int, expected string) {
+	if pos >= m.failPos {
+		m.failPos, m.expected = pos, expected
+	}
+}
+
+// match matches p at pos and returns the position after it.
+func (m *matcher) match(p *particle, pos int) (int, bool) {
+	n := 0
+	for p.max < 0 || n < p.max {
+		next, ok := m.matchOnce(p, pos)
+		if !ok || next == pos {
+			if !ok && n < p.min {
+				return pos, false
+			}
+			break
+		}
+		pos = next
+		n++
+	}
+	return pos, true
+}
+
+// matchOnce matches one occurrence of p at pos.
+func (m *matcher) matchOnce(p *particle, pos int) (int, bool) {
+	switch p.kind {
+	case pElement:
+		if pos < len(m.kids) && m.kids[pos].name == p.elem.name {
+			m.assigned[pos] = p.elem
+			return pos + 1, true
+		}
+		m.fail(pos, "<"+p.elem.name.Local+">")
+		return pos, false
+	case pAny:
+		if pos < len(m.kids) && anyAllows(p.anyNS, m.kids[pos].name.Space) {
+			m.assigned[pos] = nil
+			return pos + 1, true
+		}
+		m.fail(pos, "any element")
+		return pos, false
+	case pSequence:
+		for _, ch := range p.children {
+			next, ok := m.match(ch, pos)
+			if !ok {
+				return pos, false
+			}
+			pos = next
+		}
+		return pos, true
+	case pChoice:
+		empty := false
+		for _, ch := range p.children {
+			next, ok := m.match(ch, pos)
+			if ok && next > pos {
+				return next, true
+			}
+			empty = empty || ok
+		}
+		if !empty {
+			names := make([]string, 0, len(p.children))
+			for _, ch := range p.children {
+				if ch.kind == pElement {
+					names = append(names, "<"+ch.elem.name.Local+">")
+				}
+			}
+			if len(names) == len(p.children) {
+				m.fail(pos, "one of "+strings.Join(names, ", "))
+			}
+		}
+		return pos, empty
+	default: // pAll
+		used := make([]bool, len(p.children))
+		for pos < len(m.kids) {
+			found := false
+			for i, ch := range p.children {
+				if !used[i] && m.kids[pos].name == ch.elem.name {
+					used[i], found = true, true
+					m.assigned[pos] = ch.elem
+					pos++
+					break
+				}
+			}
+			if !found {
+				break
+			}
+		}
+		for i, ch := range p.children {
+			if !used[i] && ch.min > 0 {
+				m.fail(pos, "<"+ch.elem.name.Local+">")
+				return pos, false
+			}
+		}
+		return pos, true
+	}
+}
+
+// anyAllows applies an xs:any namespace constraint; "##any" (the default),
+// "##other", "##local", "##targetNamespace" and URI lists are recognized
+// against the element's namespace only, not the schema's.
+func anyAllows(constraint, space string) bool {
+	switch constraint {
+	case "", "##any", "##other", "##targetNamespace":
+		return true
+	case "##local":
+		return space == ""
+	}
+	for _, ns := range strings.Fields(constraint) {
+		if ns == space || (ns == "##local" && space == "") {
+			return true
+		}
+	}
+	return false
+}
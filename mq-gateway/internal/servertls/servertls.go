@@ -0,0 +1,233 @@
+// Package servertls serves TLS on the gateway's own REST and gRPC listeners,
+// reloading the certificate and client CA bundle when their files change.
+package servertls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// recheckInterval limits how often the certificate files are stat'ed for changes.
+const recheckInterval = 10 * time.Second
+
+// Config describes the listener certificate and client verification.
+type Config struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile enables client-certificate verification against this PEM bundle.
+	ClientCAFile string
+	// RequireClientCert rejects handshakes without a verified client certificate.
+	// When false, a certificate is verified only if the client sends one.
+	RequireClientCert bool
+	MinVersion        uint16
+}
+
+func getenv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func getbool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "1", "true", "yes", "y", "on":
+		return true
+	case "0", "false", "no", "n", "off":
+		return false
+	default:
+		return def
+	}
+}
+
+// ParseVersion maps "1.2" or "1.3" to the crypto/tls constant. The
+// deprecated TLS 1.0 and 1.1 are rejected rather than enabled.
+func ParseVersion(v string) (uint16, error) {
+	switch strings.TrimSpace(v) {
+	case "1.0", "1.1":
+		return 0, fmt.Errorf("TLS %s is deprecated; use 1.2 or 1.3", strings.TrimSpace(v))
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version %q", v)
+	}
+}
+
+// FromEnv builds a Reloader from environment variables:
+//
+//	TLS_CERT_FILE            PEM certificate chain for the listeners
+//	TLS_KEY_FILE             PEM private key
+//	TLS_CLIENT_CA_FILE       CA bundle used to verify client certificates (optional)
+//	TLS_REQUIRE_CLIENT_CERT  reject clients without a verified certificate
+//	TLS_MIN_VERSION          1.2 (default) or 1.3
+//
+// It returns nil when TLS_CERT_FILE is unset, which keeps the listeners plaintext.
+func FromEnv() (*Reloader, error) {
+	cfg := Config{
+		CertFile:          getenv("TLS_CERT_FILE", ""),
+		KeyFile:           getenv("TLS_KEY_FILE", ""),
+		ClientCAFile:      getenv("TLS_CLIENT_CA_FILE", ""),
+		RequireClientCert: getbool("TLS_REQUIRE_CLIENT_CERT", false),
+	}
+	if cfg.CertFile == "" {
+		return nil, nil
+	}
+	if cfg.KeyFile == "" {
+		return nil, fmt.Errorf("TLS_CERT_FILE set but TLS_KEY_FILE is missing")
+	}
+	if cfg.RequireClientCert && cfg.ClientCAFile == "" {
+		return nil, fmt.Errorf("TLS_REQUIRE_CLIENT_CERT needs TLS_CLIENT_CA_FILE")
+	}
+	v, err := ParseVersion(getenv("TLS_MIN_VERSION", "1.2"))
+	if err != nil {
+		return nil, fmt.Errorf("TLS_MIN_VERSION: %w", err)
+	}
+	cfg.MinVersion = v
+	return NewReloader(cfg)
+}
+
+// Reloader holds the current certificate and client CA pool and swaps them
+// when the files on disk change. A failed reload keeps the previous material.
+type Reloader struct {
+	cfg     Config
+	recheck time.Duration
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	// modTimes records the mtime of each watched file at the last successful load.
+	modTimes  map[string]time.Time
+	checkedAt time.Time
+}
+
+// NewReloader loads the certificate (and CA bundle) once and fails if they are invalid.
+func NewReloader(cfg Config) (*Reloader, error) {
+	if cfg.MinVersion == 0 {
+		cfg.MinVersion = tls.VersionTLS12
+	}
+	r := &Reloader{cfg: cfg, recheck: recheckInterval}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reloader) files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	return files
+}
+
+func (r *Reloader) load() error {
+	modTimes := make(map[string]time.Time)
+	for _, f := range r.files() {
+		st, err := os.Stat(f)
+		if err != nil {
+			return err
+		}
+		modTimes[f] = st.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load key pair: %w", err)
+	}
+	var pool *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return err
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("%s: no certificates found", r.cfg.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCAs = pool
+	r.modTimes = modTimes
+	r.checkedAt = time.Now()
+	r.mu.Unlock()
+	return nil
+}
+
+// maybeReload reloads when any watched file's mtime changed since the last load.
+func (r *Reloader) maybeReload() {
+	r.mu.Lock()
+	if time.Since(r.checkedAt) < r.recheck {
+		r.mu.Unlock()
+		return
+	}
+	r.checkedAt = time.Now()
+	changed := false
+	for f, mt := range r.modTimes {
+		if st, err := os.Stat(f); err == nil && !st.ModTime().Equal(mt) {
+			changed = true
+			break
+		}
+	}
+	r.mu.Unlock()
+	if !changed {
+		return
+	}
+
+	// Cert and key may be replaced one after the other; a mismatched pair fails
+	// to load here and is retried on the next check.
+	if err := r.load(); err != nil {
+		slog.Error("[tls] certificate reload failed, keeping previous certificate",
+			"error", err,
+			"id", "5007db84-e585-4a32-aa20-da029b118fde")
+		return
+	}
+	slog.Info("[tls] certificate reloaded",
+		"cert_file", r.cfg.CertFile,
+		"id", "9b97dd01-612f-498e-90c9-ba1311ad6777")
+}
+
+// TLSConfig returns a server config that picks up reloaded material on each
+// handshake. nextProtos sets ALPN, e.g. "h2" for gRPC.
+func (r *Reloader) TLSConfig(nextProtos ...string) *tls.Config {
+	clientAuth := tls.NoClientCert
+	switch {
+	case r.cfg.RequireClientCert:
+		clientAuth = tls.RequireAndVerifyClientCert
+	case r.cfg.ClientCAFile != "":
+		clientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return &tls.Config{
+		MinVersion: r.cfg.MinVersion,
+		NextProtos: nextProtos,
+		// The per-connection config carries the current certificate and CA pool,
+		// so both rotate without restarting the listener.
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.maybeReload()
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return &tls.Config{
+				MinVersion:   r.cfg.MinVersion,
+				NextProtos:   nextProtos,
+				Certificates: []tls.Certificate{*r.cert},
+				ClientAuth:   clientAuth,
+				ClientCAs:    r.clientCAs,
+			}, nil
+		},
+	}
+}
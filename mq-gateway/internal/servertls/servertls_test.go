@@ -0,0 +1,136 @@
+package servertls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCert writes a self-signed certificate and key with the given serial.
+func writeCert(t *testing.T, certFile, keyFile string, serial int64) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "mq-gateway"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Make the change visible even on filesystems with coarse mtimes.
+	mt := time.Now().Add(time.Duration(serial) * time.Second)
+	_ = os.Chtimes(certFile, mt, mt)
+	_ = os.Chtimes(keyFile, mt, mt)
+}
+
+func serialOf(t *testing.T, cfg *tls.Config) int64 {
+	t.Helper()
+	conn, err := cfg.GetConfigForClient(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(conn.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.SerialNumber.Int64()
+}
+
+func TestReloaderPicksUpNewCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeCert(t, certFile, keyFile, 1)
+
+	r, err := NewReloader(Config{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.recheck = 0
+	cfg := r.TLSConfig("h2")
+	if got := serialOf(t, cfg); got != 1 {
+		t.Fatalf("serial = %d, want 1", got)
+	}
+
+	writeCert(t, certFile, keyFile, 2)
+	if got := serialOf(t, cfg); got != 2 {
+		t.Fatalf("after rotation serial = %d, want 2", got)
+	}
+
+	// A half-written rotation (garbage key) keeps serving the previous certificate.
+	if err := os.WriteFile(keyFile, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	mt := time.Now().Add(time.Minute)
+	_ = os.Chtimes(keyFile, mt, mt)
+	if got := serialOf(t, cfg); got != 2 {
+		t.Fatalf("after bad reload serial = %d, want 2", got)
+	}
+}
+
+func TestTLSConfigClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeCert(t, certFile, keyFile, 1)
+
+	tests := []struct {
+		cfg  Config
+		want tls.ClientAuthType
+	}{
+		{Config{CertFile: certFile, KeyFile: keyFile}, tls.NoClientCert},
+		{Config{CertFile: certFile, KeyFile: keyFile, ClientCAFile: certFile}, tls.VerifyClientCertIfGiven},
+		{Config{CertFile: certFile, KeyFile: keyFile, ClientCAFile: certFile, RequireClientCert: true}, tls.RequireAndVerifyClientCert},
+	}
+	for _, tt := range tests {
+		r, err := NewReloader(tt.cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn, err := r.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if conn.ClientAuth != tt.want {
+			t.Errorf("%+v: ClientAuth = %v, want %v", tt.cfg, conn.ClientAuth, tt.want)
+		}
+		if conn.MinVersion != tls.VersionTLS12 {
+			t.Errorf("MinVersion = %x, want TLS 1.2", conn.MinVersion)
+		}
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	if v, err := ParseVersion("1.3"); err != nil || v != tls.VersionTLS13 {
+		t.Fatalf("1.3: got %x, %v", v, err)
+	}
+	for _, v := range []string{"1.0", "1.1", "1.4"} {
+		if _, err := ParseVersion(v); err == nil {
+			t.Fatalf("expected error for %s", v)
+		}
+	}
+}
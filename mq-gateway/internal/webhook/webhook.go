@@ -0,0 +1,333 @@
+// Package webhook pushes messages from queues to HTTP endpoints. Each route
+// consumes one queue under syncpoint and POSTs every message to its URL,
+// committing on a 2xx answer and backing out otherwise, so delivery is
+// at-least-once; endpoints can deduplicate on the X-MQ-Msg-Id header.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/audit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/worker"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+// Route pushes the messages of Queue to URL.
+type Route struct {
+	// Name identifies the route in logs and audit records.
+	Name  string `json:"name"`
+	Queue string `json:"queue"`
+	URL   string `json:"url"`
+	// Headers are added to every request, e.g. Authorization.
+	Headers map[string]string `json:"headers,omitempty"`
+	// ContentType defaults to application/octet-stream.
+	ContentType string `json:"content_type,omitempty"`
+	// TimeoutMs caps each request (default 10000).
+	TimeoutMs int `json:"timeout_ms,omitempty"`
+	// Parallelism is the number of workers, each with its own MQ connection
+	// (default 1). Above 1, messages may reach the endpoint out of order.
+	Parallelism int `json:"parallelism,omitempty"`
+	// MaxAttempts is how many failed deliveries a message gets before it is
+	// moved to BackoutQueue (default 5).
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// BackoutQueue defaults to the queue's BOQNAME, or else the dead-letter queue.
+	BackoutQueue string `json:"backout_queue,omitempty"`
+	// After a failure the worker waits BackoffMs, doubled per attempt up to
+	// MaxBackoffMs (defaults 1000 and 60000).
+	BackoffMs    int `json:"backoff_ms,omitempty"`
+	MaxBackoffMs int `json:"max_backoff_ms,omitempty"`
+	// MaxMsgBytes is the largest message accepted (default 4 MiB).
+	MaxMsgBytes int `json:"max_msg_bytes,omitempty"`
+}
+
+// Config is the webhook file format.
+type Config struct {
+	Routes []Route `json:"routes"`
+}
+
+// Consumer is a syncpoint consumer on a connection of its own, normally an
+// *mqcore.TxConsumer.
+type Consumer interface {
+	worker.Consumer
+	Commit() error
+	Backout() error
+	MoveToBackout(queueName string) (string, error)
+}
+
+// OpenFunc opens a Consumer on queue for one worker.
+type OpenFunc func(queue string, maxBytes int) (Consumer, error)
+
+// Dispatcher runs the workers of every route. A nil *Dispatcher runs nothing.
+type Dispatcher struct {
+	routes []Route
+	open   OpenFunc
+	client *http.Client
+	audit  *audit.Logger
+}
+
+// New validates cfg, fills in defaults and returns a Dispatcher whose workers
+// open their consumers with open. Deliveries are audited to auditLog when it
+// is not nil.
+func New(cfg Config, open OpenFunc, auditLog *audit.Logger) (*Dispatcher, error) {
+	names := make(map[string]bool)
+	for i := range cfg.Routes {
+		r := &cfg.Routes[i]
+		if r.Queue == "" || r.URL == "" {
+			return nil, fmt.Errorf("route %d needs queue and url", i)
+		}
+		if u, err := url.Parse(r.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("route %d: url must be an absolute http or https URL", i)
+		}
+		if r.Name == "" {
+			r.Name = r.Queue
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("route %d: duplicate name %q", i, r.Name)
+		}
+		names[r.Name] = true
+		if r.TimeoutMs < 0 || r.Parallelism < 0 || r.MaxAttempts < 0 || r.BackoffMs < 0 || r.MaxBackoffMs < 0 || r.MaxMsgBytes < 0 {
+			return nil, fmt.Errorf("route %q: negative setting", r.Name)
+		}
+		if r.ContentType == "" {
+			r.ContentType = "application/octet-stream"
+		}
+		if r.TimeoutMs == 0 {
+			r.TimeoutMs = 10000
+		}
+		if r.Parallelism == 0 {
+			r.Parallelism = 1
+		}
+		if r.MaxAttempts == 0 {
+			r.MaxAttempts = 5
+		}
+		if r.BackoffMs == 0 {
+			r.BackoffMs = 1000
+		}
+		if r.MaxBackoffMs == 0 {
+			r.MaxBackoffMs = 60000
+		}
+		r.MaxBackoffMs = max(r.MaxBackoffMs, r.BackoffMs)
+	}
+	return &Dispatcher{routes: cfg.Routes, open: open, client: &http.Client{}, audit: auditLog}, nil
+}
+
+// Load reads a JSON Config from path.
+func Load(path string, open OpenFunc, auditLog *audit.Logger) (*Dispatcher, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	d, err := New(cfg, open, auditLog)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return d, nil
+}
+
+// FromEnv loads the file named by WEBHOOK_FILE, or returns nil when unset.
+func FromEnv(open OpenFunc, auditLog *audit.Logger) (*Dispatcher, error) {
+	path := os.Getenv("WEBHOOK_FILE")
+	if path == "" {
+		return nil, nil
+	}
+	return Load(path, open, auditLog)
+}
+
+// Run starts Parallelism workers per route and blocks until ctx is done and
+// every worker has backed out or finished its message and disconnected.
+func (d *Dispatcher) Run(ctx context.Context) {
+	if d == nil {
+		return
+	}
+	var wg sync.WaitGroup
+	for i := range d.routes {
+		r := &d.routes[i]
+		slog.Info("[webhook] route started",
+			"route", r.Name,
+			"queue", r.Queue,
+			"parallelism", r.Parallelism,
+			"id", "2c8b46c5-45a4-4e27-bd0d-dff8ad62b715")
+		for w := 0; w < r.Parallelism; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				d.worker(ctx, r)
+			}()
+		}
+	}
+	wg.Wait()
+}
+
+// worker delivers messages of r until ctx is done, reconnecting with backoff
+// when MQ fails.
+func (d *Dispatcher) worker(ctx context.Context, r *Route) {
+	l := worker.Loop[Consumer]{
+		Open: func() (Consumer, error) { return d.open(r.Queue, r.MaxMsgBytes) },
+		Handle: func(ctx context.Context, c Consumer, m *mqcore.ArchivedMessage) time.Duration {
+			return d.deliver(ctx, c, r, m)
+		},
+		Backoff: func(attempt int) time.Duration {
+			return worker.Backoff(r.BackoffMs, r.MaxBackoffMs, attempt)
+		},
+		OpenFailed: func(err error) {
+			slog.Error("[webhook] cannot open queue",
+				"route", r.Name,
+				"queue", r.Queue,
+				"error", err,
+				"id", "1ebbca6a-174e-44e5-b1b8-4e1ded7bf609")
+		},
+		GetFailed: func(err error) {
+			slog.Error("[webhook] get failed",
+				"route", r.Name,
+				"queue", r.Queue,
+				"error", err,
+				"id", "dd483936-1c39-4c7b-a861-5fb30bd9fd85")
+		},
+	}
+	l.Run(ctx)
+}
+
+// deliver handles one message got by c and returns how long the worker
+// should wait before the next one.
+func (d *Dispatcher) deliver(ctx context.Context, c Consumer, r *Route, m *mqcore.ArchivedMessage) time.Duration {
+	rec := audit.Record{
+		Operation: audit.OpWebhook,
+		Transport: "webhook",
+		Queue:     r.Queue,
+		Target:    r.Name,
+		MsgID:     m.MsgID,
+		CorrelID:  m.CorrelID,
+	}.WithPayload(string(m.Payload))
+
+	if int(m.BackoutCount) >= r.MaxAttempts {
+		target, err := c.MoveToBackout(r.BackoutQueue)
+		if err != nil {
+			slog.Error("[webhook] cannot move message to backout queue",
+				"route", r.Name,
+				"queue", r.Queue,
+				"target", target,
+				"msg_id", m.MsgID,
+				"error", err,
+				"id", "185f6104-d573-4094-a927-22de21506f81")
+			d.record(ctx, rec, err)
+			return worker.Backoff(r.BackoffMs, r.MaxBackoffMs, int(m.BackoutCount))
+		}
+		slog.Warn("[webhook] message moved to backout queue",
+			"route", r.Name,
+			"queue", r.Queue,
+			"target", target,
+			"msg_id", m.MsgID,
+			"attempts", m.BackoutCount,
+			"id", "09656871-3433-48c1-b53b-e434a890a818")
+		rec.Outcome = audit.OutcomeBackedOut
+		d.record(ctx, rec, nil)
+		return 0
+	}
+
+	if err := d.post(ctx, r, m); err != nil {
+		slog.Warn("[webhook] delivery failed",
+			"route", r.Name,
+			"queue", r.Queue,
+			"msg_id", m.MsgID,
+			"attempt", m.BackoutCount+1,
+			"error", err,
+			"id", "a3a2c1c1-070c-4ab0-a8c7-640bf8342c14")
+		d.record(ctx, rec, err)
+		if berr := c.Backout(); berr != nil {
+			slog.Error("[webhook] backout failed",
+				"route", r.Name,
+				"error", berr,
+				"id", "8cfc45fb-2cce-4030-958e-ef046471ca53")
+		}
+		return worker.Backoff(r.BackoffMs, r.MaxBackoffMs, int(m.BackoutCount)+1)
+	}
+
+	// A failed commit leaves the message on the queue, so it is delivered again.
+	err := c.Commit()
+	if err != nil {
+		slog.Error("[webhook] commit failed after delivery",
+			"route", r.Name,
+			"msg_id", m.MsgID,
+			"error", err,
+			"id", "9fa9f13d-1562-43e1-8a96-8fbfb4d1c549")
+	}
+	d.record(ctx, rec, err)
+	return 0
+}
+
+// post sends m to r.URL and fails unless the answer is 2xx.
+func (d *Dispatcher) post(ctx context.Context, r *Route, m *mqcore.ArchivedMessage) error {
+	// A request in flight at shutdown may finish within its timeout.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Duration(r.TimeoutMs)*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(m.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", r.ContentType)
+	setMQHeaders(req.Header, r.Queue, m)
+	for k, v := range r.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s answered %s", r.URL, resp.Status)
+	}
+	return nil
+}
+
+// setMQHeaders describes the message's MQMD as X-MQ-* headers; empty fields
+// are left out.
+func setMQHeaders(h http.Header, queue string, m *mqcore.ArchivedMessage) {
+	set := func(name, v string) {
+		if v != "" {
+			h.Set(name, v)
+		}
+	}
+	itoa := func(n int32) string { return strconv.Itoa(int(n)) }
+	set("X-MQ-Queue", queue)
+	set("X-MQ-Msg-Id", m.MsgID)
+	set("X-MQ-Correl-Id", m.CorrelID)
+	set("X-MQ-Group-Id", m.GroupID)
+	set("X-MQ-Format", m.Format)
+	set("X-MQ-CCSID", itoa(m.CodedCharSetID))
+	set("X-MQ-Encoding", itoa(m.Encoding))
+	set("X-MQ-Msg-Type", itoa(m.MsgType))
+	set("X-MQ-Persistence", itoa(m.Persistence))
+	set("X-MQ-Priority", itoa(m.Priority))
+	set("X-MQ-Expiry", itoa(m.Expiry))
+	set("X-MQ-Reply-To-Q", m.ReplyToQ)
+	set("X-MQ-Reply-To-QMgr", m.ReplyToQMgr)
+	set("X-MQ-User-Id", m.UserIdentifier)
+	set("X-MQ-Put-Appl-Name", m.PutApplName)
+	if !m.PutDateTime.IsZero() {
+		set("X-MQ-Put-Time", m.PutDateTime.UTC().Format(time.RFC3339Nano))
+	}
+	set("X-MQ-Backout-Count", itoa(m.BackoutCount))
+}
+
+// record writes an audit record; a non-nil err marks it failed.
+func (d *Dispatcher) record(ctx context.Context, rec audit.Record, err error) {
+	worker.Record(ctx, d.audit, rec, err)
+}
@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+// fakeConsumer records what the dispatcher did with the current message.
+type fakeConsumer struct {
+	committed, backedOut int
+	movedTo              []string
+}
+
+func (c *fakeConsumer) Next(int) (*mqcore.ArchivedMessage, error) { return nil, nil }
+func (c *fakeConsumer) Commit() error                             { c.committed++; return nil }
+func (c *fakeConsumer) Backout() error                            { c.backedOut++; return nil }
+func (c *fakeConsumer) Close() error                              { return nil }
+func (c *fakeConsumer) MoveToBackout(q string) (string, error) {
+	c.movedTo = append(c.movedTo, q)
+	return q, nil
+}
+
+func newDispatcher(t *testing.T, r Route) *Dispatcher {
+	t.Helper()
+	d, err := New(Config{Routes: []Route{r}}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestNewDefaults(t *testing.T) {
+	d := newDispatcher(t, Route{Queue: "ORDERS.OUT", URL: "http://svc/orders"})
+	r := d.routes[0]
+	if r.Name != "ORDERS.OUT" || r.Parallelism != 1 || r.MaxAttempts != 5 || r.TimeoutMs != 10000 ||
+		r.ContentType != "application/octet-stream" {
+		t.Fatalf("defaults not applied: %+v", r)
+	}
+
+	for _, bad := range []Config{
+		{Routes: []Route{{Queue: "Q"}}},
+		{Routes: []Route{{Queue: "Q", URL: "svc/orders"}}},
+		{Routes: []Route{{Queue: "Q", URL: "http://svc", Parallelism: -1}}},
+		{Routes: []Route{{Queue: "Q", URL: "http://a"}, {Queue: "Q", URL: "http://b"}}},
+	} {
+		if _, err := New(bad, nil, nil); err == nil {
+			t.Errorf("New(%+v) succeeded", bad)
+		}
+	}
+}
+
+func TestDeliverCommitsOn2xx(t *testing.T) {
+	var got http.Header
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	d := newDispatcher(t, Route{Queue: "ORDERS.OUT", URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer t"}})
+	c := &fakeConsumer{}
+	m := &mqcore.ArchivedMessage{MsgID: "414d51", Format: "MQSTR", Priority: 4, Payload: []byte(`{"id":1}`)}
+	if wait := d.deliver(context.Background(), c, &d.routes[0], m); wait != 0 {
+		t.Fatalf("wait = %v after success", wait)
+	}
+	if c.committed != 1 || c.backedOut != 0 {
+		t.Fatalf("committed %d, backed out %d", c.committed, c.backedOut)
+	}
+	if body != `{"id":1}` {
+		t.Fatalf("body = %q", body)
+	}
+	for name, want := range map[string]string{
+		"X-MQ-Queue":    "ORDERS.OUT",
+		"X-MQ-Msg-Id":   "414d51",
+		"X-MQ-Format":   "MQSTR",
+		"X-MQ-Priority": "4",
+		"Authorization": "Bearer t",
+		"Content-Type":  "application/octet-stream",
+	} {
+		if got.Get(name) != want {
+			t.Errorf("%s = %q, want %q", name, got.Get(name), want)
+		}
+	}
+	if got.Get("X-MQ-Correl-Id") != "" {
+		t.Errorf("empty CorrelId sent as %q", got.Get("X-MQ-Correl-Id"))
+	}
+}
+
+func TestDeliverBacksOutOnFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	d := newDispatcher(t, Route{Queue: "Q", URL: srv.URL, BackoffMs: 100, MaxAttempts: 3})
+	c := &fakeConsumer{}
+	wait := d.deliver(context.Background(), c, &d.routes[0], &mqcore.ArchivedMessage{BackoutCount: 1})
+	if c.backedOut != 1 || c.committed != 0 {
+		t.Fatalf("committed %d, backed out %d", c.committed, c.backedOut)
+	}
+	if wait != 200*time.Millisecond {
+		t.Fatalf("wait = %v, want the second attempt's backoff", wait)
+	}
+}
+
+func TestDeliverMovesAfterMaxAttempts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("message past max_attempts was posted")
+	}))
+	defer srv.Close()
+
+	d := newDispatcher(t, Route{Queue: "Q", URL: srv.URL, MaxAttempts: 3, BackoutQueue: "Q.BACKOUT"})
+	c := &fakeConsumer{}
+	d.deliver(context.Background(), c, &d.routes[0], &mqcore.ArchivedMessage{BackoutCount: 3})
+	if len(c.movedTo) != 1 || c.movedTo[0] != "Q.BACKOUT" {
+		t.Fatalf("moved to %v", c.movedTo)
+	}
+}
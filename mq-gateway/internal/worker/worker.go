@@ -0,0 +1,113 @@
+// Package worker runs the consume loops behind the webhook dispatcher and the
+// router: each loop holds a syncpoint consumer on a connection of its own,
+// hands every message to a handler and reconnects with backoff when MQ fails.
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/audit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+// PollMs bounds how long a loop waits for a message before it checks for
+// shutdown again.
+const PollMs = 1000
+
+// Consumer is the part of a syncpoint consumer the loop itself uses; the
+// handler commits, backs out or moves the message.
+type Consumer interface {
+	Next(waitMs int) (*mqcore.ArchivedMessage, error)
+	Close() error
+}
+
+// Loop consumes one queue until its context is done.
+type Loop[C Consumer] struct {
+	// Open connects and opens the queue. It is called again after every
+	// failed open or get.
+	Open func() (C, error)
+	// Handle processes one message got by c and returns how long the loop
+	// should wait before the next one.
+	Handle func(ctx context.Context, c C, m *mqcore.ArchivedMessage) time.Duration
+	// Backoff is the wait after the attempt-th consecutive MQ failure.
+	Backoff func(attempt int) time.Duration
+	// OpenFailed and GetFailed report MQ failures, typically by logging them.
+	OpenFailed func(err error)
+	GetFailed  func(err error)
+}
+
+// Run consumes until ctx is done and closes the consumer it holds.
+func (l *Loop[C]) Run(ctx context.Context) {
+	var c C
+	open := false
+	defer func() {
+		if open {
+			_ = c.Close()
+		}
+	}()
+	failures := 0
+	for ctx.Err() == nil {
+		if !open {
+			var err error
+			if c, err = l.Open(); err != nil {
+				failures++
+				l.OpenFailed(err)
+				Sleep(ctx, l.Backoff(failures))
+				continue
+			}
+			open = true
+			failures = 0
+		}
+
+		m, err := c.Next(PollMs)
+		if err != nil {
+			l.GetFailed(err)
+			_ = c.Close()
+			open = false
+			failures++
+			Sleep(ctx, l.Backoff(failures))
+			continue
+		}
+		if m == nil {
+			continue
+		}
+		Sleep(ctx, l.Handle(ctx, c, m))
+	}
+}
+
+// Backoff is the wait after the attempt-th failure: baseMs, doubled per
+// attempt up to maxMs.
+func Backoff(baseMs, maxMs, attempt int) time.Duration {
+	d := time.Duration(baseMs) * time.Millisecond
+	limit := time.Duration(maxMs) * time.Millisecond
+	for i := 1; i < attempt && d < limit; i++ {
+		d *= 2
+	}
+	return min(d, limit)
+}
+
+// Sleep waits for d or until ctx is done.
+func Sleep(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
+
+// Record writes rec to log, which may be nil; a non-nil err marks it failed.
+func Record(ctx context.Context, log *audit.Logger, rec audit.Record, err error) {
+	if err != nil {
+		rec.Outcome = audit.OutcomeError
+		rec.Error = err.Error()
+		rec.ReasonCode = mqcore.ReasonCode(err)
+	} else if rec.Outcome == "" {
+		rec.Outcome = audit.OutcomeOK
+	}
+	log.Log(ctx, rec)
+}
@@ -0,0 +1,87 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
+)
+
+// fakeConsumer hands out msgs in order and fails the get after them.
+type fakeConsumer struct {
+	msgs   []*mqcore.ArchivedMessage
+	closed bool
+}
+
+func (c *fakeConsumer) Next(int) (*mqcore.ArchivedMessage, error) {
+	if len(c.msgs) == 0 {
+		return nil, errors.New("connection broken")
+	}
+	m := c.msgs[0]
+	c.msgs = c.msgs[1:]
+	return m, nil
+}
+
+func (c *fakeConsumer) Close() error { c.closed = true; return nil }
+
+func TestBackoff(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 350 * time.Millisecond,
+		9: 350 * time.Millisecond,
+	} {
+		if got := Backoff(100, 350, attempt); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestLoopReconnects(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var opened []*fakeConsumer
+	var handled []string
+	var failures []string
+	var waits []int
+	l := Loop[*fakeConsumer]{
+		Open: func() (*fakeConsumer, error) {
+			if len(opened) == 1 {
+				// The second open fails once before the third succeeds.
+				opened = append(opened, nil)
+				return nil, errors.New("queue manager unavailable")
+			}
+			c := &fakeConsumer{msgs: []*mqcore.ArchivedMessage{{MsgID: "0a"}, nil, {MsgID: "0b"}}}
+			opened = append(opened, c)
+			return c, nil
+		},
+		Handle: func(_ context.Context, _ *fakeConsumer, m *mqcore.ArchivedMessage) time.Duration {
+			handled = append(handled, m.MsgID)
+			if len(handled) == 4 {
+				cancel()
+			}
+			return 0
+		},
+		Backoff:    func(attempt int) time.Duration { waits = append(waits, attempt); return 0 },
+		OpenFailed: func(error) { failures = append(failures, "open") },
+		GetFailed:  func(error) { failures = append(failures, "get") },
+	}
+	l.Run(ctx)
+
+	if got := len(handled); got != 4 || handled[2] != "0a" || handled[3] != "0b" {
+		t.Fatalf("handled %v, want 0a 0b 0a 0b", handled)
+	}
+	if len(failures) != 2 || failures[0] != "get" || failures[1] != "open" {
+		t.Fatalf("failures %v, want get then open", failures)
+	}
+	// The failed open follows the failed get, so its backoff is the second.
+	if len(waits) != 2 || waits[0] != 1 || waits[1] != 2 {
+		t.Fatalf("backoff attempts %v, want 1 2", waits)
+	}
+	if len(opened) != 3 || !opened[0].closed || !opened[2].closed {
+		t.Fatal("consumers not closed")
+	}
+}
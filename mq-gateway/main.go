@@ -3,7 +3,14 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/audit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/auth"
 	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/logging"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/ratelimit"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/router"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/schema"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/servertls"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/internal/webhook"
 	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqcore"
 
 	"log/slog"
@@ -15,6 +22,8 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
 
 	"github.com/jlambert68/MQDockerContainer2/mq-gateway/api/gprcsrv"
 	"github.com/jlambert68/MQDockerContainer2/mq-gateway/api/proto/mq_grpc_api"
@@ -59,25 +68,147 @@ func main() {
 	slog.Info("[main] connected to MQ",
 		"id", "d0a80fb4-71f5-4214-9b31-605a38ea5c97")
 
+	// Authentication is shared by REST and gRPC; nil leaves both open.
+	authn, err := auth.FromEnv()
+	if err != nil {
+		slog.Error("[main] invalid authentication config",
+			"error", err,
+			"id", "754eaaa0-7b34-4e5b-be3c-b7933c6e628c")
+		os.Exit(1)
+	}
+	authz, err := auth.PolicyFromEnv()
+	if err != nil {
+		slog.Error("[main] invalid authorization policy",
+			"error", err,
+			"id", "26aaa035-8acf-4dce-9ad7-d6959d4e22c4")
+		os.Exit(1)
+	}
+
+	limits, err := ratelimit.FromEnv()
+	if err != nil {
+		slog.Error("[main] invalid rate limit config",
+			"error", err,
+			"id", "7521d6a8-ff9a-4ed2-aaf0-df6f80c3a86e")
+		os.Exit(1)
+	}
+
+	// Payload schemas are checked on every put path; nil validates nothing.
+	schemas, err := schema.FromEnv()
+	if err != nil {
+		slog.Error("[main] invalid schema config",
+			"error", err,
+			"id", "59083c13-c707-46c1-afb0-f5226a996bd6")
+		os.Exit(1)
+	}
+	gateway.SetValidator(schemas)
+
+	// The audit trail is written to its own sink, not the service log.
+	auditLog, err := audit.FromEnv(gateway)
+	if err != nil {
+		slog.Error("[main] invalid audit config",
+			"error", err,
+			"id", "8a06f7a3-4116-4db2-8687-bf6529d5f145")
+		os.Exit(1)
+	}
+	defer auditLog.Close()
+
+	// Listener TLS is shared by REST and gRPC; nil serves plaintext.
+	serverTLS, err := servertls.FromEnv()
+	if err != nil {
+		slog.Error("[main] invalid listener TLS config",
+			"error", err,
+			"id", "008e4644-53fb-41c8-9d87-5549d335f949")
+		os.Exit(1)
+	}
+
+	// Webhook routes push queues to HTTP endpoints; each worker has its own
+	// MQ connection so its unit of work can span the HTTP call.
+	webhooks, err := webhook.FromEnv(func(queue string, maxBytes int) (webhook.Consumer, error) {
+		return gateway.OpenTxConsumer(queue, maxBytes)
+	}, auditLog)
+	if err != nil {
+		slog.Error("[main] invalid webhook config",
+			"error", err,
+			"id", "3d0f6f1e-52a9-4c1b-9f7e-8b2c6a4d1e90")
+		os.Exit(1)
+	}
+
+	// Routing rules forward messages between queues; like webhooks, each
+	// worker gets a unit of work spanning the get and its puts.
+	routes, err := router.FromEnv(func(queue string, maxBytes int) (router.Consumer, error) {
+		return gateway.OpenTxConsumer(queue, maxBytes)
+	}, auditLog)
+	if err != nil {
+		slog.Error("[main] invalid router config",
+			"error", err,
+			"id", "1e8268e5-0ac7-4369-bbce-dc6138c71756")
+		os.Exit(1)
+	}
+
+	// Scheduled delivery, webhooks and routing run until shutdown; each is a
+	// no-op when not configured.
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go gateway.RunScheduler(workersCtx)
+	webhooksDone := make(chan struct{})
+	go func() {
+		webhooks.Run(workersCtx)
+		close(webhooksDone)
+	}()
+	routesDone := make(chan struct{})
+	go func() {
+		routes.Run(workersCtx)
+		close(routesDone)
+	}()
+
 	// ------------------------------------------------------------------
 	// 2. REST server
 	// ------------------------------------------------------------------
 	restPort := getenv("REST_PORT", ":8080")
 
+	// One service instance backs gRPC and its /v2 REST transcoding.
+	grpcService := &grpcsrv.Server{
+		GW:     gateway,
+		Authz:  authz,
+		Audit:  auditLog,
+		Limits: limits,
+	}
+	v2, err := grpcService.Gateway()
+	if err != nil {
+		slog.Error("[REST] failed to build v2 gateway",
+			"error", err,
+			"id", "5e0b7c2a-9d41-4f6e-8a3b-1c7f92d4e605")
+		os.Exit(1)
+	}
+
 	restHandler := &rest.Handler{
-		GW: gateway,
+		GW:     gateway,
+		Authz:  authz,
+		Audit:  auditLog,
+		Limits: limits,
+		Router: routes,
+		V2:     v2,
 	}
 
 	restServer := &http.Server{
 		Addr:         restPort,
-		Handler:      restHandler.Routes(),
+		Handler:      auth.Middleware(authn, restHandler.Routes()),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 	}
 
 	go func() {
-		slog.Info(fmt.Sprintf("[REST] listening on %s", restPort))
-		if err := restServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		slog.Info(fmt.Sprintf("[REST] listening on %s", restPort),
+			"tls", serverTLS != nil)
+		var err error
+		if serverTLS != nil {
+			// Certificates come from TLSConfig, so no files are passed here.
+			restServer.TLSConfig = serverTLS.TLSConfig("h2", "http/1.1")
+			err = restServer.ListenAndServeTLS("", "")
+		} else {
+			err = restServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			slog.Error("[REST] server error",
 				"error", err,
 				"id", "954b394f-07c4-47bd-afc6-790b60e66a8a")
@@ -104,10 +235,36 @@ func main() {
 		"id", "e9089512-a789-41fe-a4c6-fcc239f94347",
 	)
 
-	grpcServer := grpc.NewServer()
-	mq_grpc_api.RegisterMqGrpcServicesServer(grpcServer, &grpcsrv.Server{
-		GW: gateway,
-	})
+	grpcOptions, err := grpcsrv.OptionsFromEnv()
+	if err != nil {
+		slog.Error("[gRPC] invalid server config",
+			"error", err,
+			"id", "10a4791f-dff8-4463-8045-d4abf7b0bb17")
+		os.Exit(1)
+	}
+
+	// Recovery runs first so it also covers logging and authentication.
+	grpcOpts := append(grpcOptions.ServerOptions(),
+		grpc.ChainUnaryInterceptor(
+			grpcsrv.RecoveryUnaryInterceptor(),
+			grpcsrv.LoggingUnaryInterceptor(),
+			auth.UnaryServerInterceptor(authn),
+		),
+		grpc.ChainStreamInterceptor(
+			grpcsrv.RecoveryStreamInterceptor(),
+			grpcsrv.LoggingStreamInterceptor(),
+			auth.StreamServerInterceptor(authn),
+		),
+	)
+	if serverTLS != nil {
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(serverTLS.TLSConfig("h2"))))
+	}
+	grpcServer := grpc.NewServer(grpcOpts...)
+	mq_grpc_api.RegisterMqGrpcServicesServer(grpcServer, grpcService)
+	if grpcOptions.Reflection {
+		// Lets grpcurl and similar tools list and describe the services.
+		reflection.Register(grpcServer)
+	}
 
 	go func() {
 		slog.Info("[gRPC] listening",
@@ -131,6 +288,11 @@ func main() {
 	sig := <-sigCh
 	slog.Info(fmt.Sprintf("[main] received signal '%s', shutting down", sig))
 
+	// Stop scheduled delivery and let webhook and router workers finish their message
+	stopWorkers()
+	<-webhooksDone
+	<-routesDone
+
 	// Stop gRPC
 	grpcServer.GracefulStop()
 
@@ -0,0 +1,104 @@
+// Package mqclient defines the transport-neutral client interface implemented
+// by the REST (pkg/mqrest) and gRPC (pkg/mqgrpc) gateway clients, so callers
+// can switch transports without code changes:
+//
+//	var c mqclient.Client = mqrest.NewClient(restCfg).AsClient()
+//	// or
+//	g, err := mqgrpc.NewClient(grpcCfg)
+//	c = g.AsClient()
+package mqclient
+
+import (
+	"context"
+	"fmt"
+)
+
+// Client is the operation set both transports support.
+type Client interface {
+	// Put puts message to queue and returns the hex MsgId.
+	Put(ctx context.Context, queue, message string, opts PutOptions) (msgID string, err error)
+	// Get destructively reads one message; it returns nil, nil when the queue is empty.
+	Get(ctx context.Context, queue string, opts GetOptions) (*Message, error)
+	// BrowseFirst opens a browse session and returns the first message (nil when
+	// the queue is empty) and the session id for BrowseNext.
+	BrowseFirst(ctx context.Context, queue string, opts GetOptions) (msg *Message, browseID string, err error)
+	// BrowseNext returns the next message in a browse session, or nil at the end.
+	BrowseNext(ctx context.Context, browseID string, opts GetOptions) (*Message, error)
+	// InquireQueue returns the queue's attributes.
+	InquireQueue(ctx context.Context, queue string) (*QueueInfo, error)
+	// Close releases the underlying connections.
+	Close() error
+}
+
+// PutOptions are optional message properties for Put.
+type PutOptions struct {
+	// ReplyToQueue marks the message as a request whose reply goes to this queue.
+	ReplyToQueue string
+	// IdempotencyKey deduplicates retried puts: the gateway returns the first
+	// put's MsgId for a repeated key instead of putting again.
+	IdempotencyKey string
+}
+
+// GetOptions applies to gets and browses. Zero values use the gateway defaults.
+type GetOptions struct {
+	// WaitMs is how long to wait for a message, in milliseconds.
+	WaitMs int
+	// MaxMsgBytes is the largest message accepted.
+	MaxMsgBytes int
+	// CorrelID selects the message with this hex CorrelId; gets only.
+	CorrelID string
+}
+
+// Message is a message read from a queue.
+type Message struct {
+	Payload string
+	// MsgID and CorrelID are hex-encoded; browses leave them empty.
+	MsgID    string
+	CorrelID string
+}
+
+// QueueInfo holds the attributes returned by InquireQueue.
+type QueueInfo struct {
+	Queue           string
+	QueueDesc       string
+	QueueType       int32
+	QueueUsage      int32
+	DefPersistence  int32
+	InhibitGet      int32
+	InhibitPut      int32
+	CurrentQDepth   int32
+	MaxQDepth       int32
+	OpenInputCount  int32
+	OpenOutputCount int32
+}
+
+// GatewayError is an operation the gateway accepted but could not complete,
+// reported as status "error" in the response body.
+type GatewayError struct {
+	Op      string
+	Message string
+}
+
+func (e *GatewayError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Op, e.Message)
+}
+
+// Credentials are sent with every call. At most one should be set.
+type Credentials struct {
+	// APIKey is sent as the X-API-Key header / metadata.
+	APIKey string
+	// BearerToken is sent as "Authorization: Bearer <token>".
+	BearerToken string
+}
+
+// Headers returns the header (or gRPC metadata) pairs for c.
+func (c Credentials) Headers() map[string]string {
+	h := make(map[string]string, 1)
+	if c.APIKey != "" {
+		h["x-api-key"] = c.APIKey
+	}
+	if c.BearerToken != "" {
+		h["authorization"] = "Bearer " + c.BearerToken
+	}
+	return h
+}
@@ -0,0 +1,81 @@
+package mqclient
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy controls retries with exponential backoff and full jitter.
+// The zero value uses DefaultRetryPolicy; set MaxAttempts to 1 to disable retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// InitialBackoff is the delay ceiling before the first retry; it doubles per attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is used for zero-valued policies.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = DefaultRetryPolicy.InitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultRetryPolicy.MaxBackoff
+	}
+	return p
+}
+
+// RetryableError marks a failure that is safe to retry. After is the delay the
+// server asked for (Retry-After / RetryInfo); zero leaves it to the backoff.
+type RetryableError struct {
+	Err   error
+	After time.Duration
+}
+
+func (e *RetryableError) Error() string { return e.Err.Error() }
+func (e *RetryableError) Unwrap() error { return e.Err }
+
+// Do calls fn until it succeeds, returns an error that is not a
+// *RetryableError, the attempts run out or ctx is done. The error returned is
+// the last one from fn with any RetryableError wrapper removed.
+func (p RetryPolicy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	p = p.withDefaults()
+	backoff := p.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		var re *RetryableError
+		if err == nil || !errors.As(err, &re) {
+			return err
+		}
+		if attempt >= p.MaxAttempts {
+			return re.Err
+		}
+
+		delay := time.Duration(rand.Int64N(int64(backoff)) + 1)
+		if re.After > delay {
+			delay = re.After
+		}
+		backoff = min(2*backoff, p.MaxBackoff)
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return re.Err
+		case <-t.C:
+		}
+	}
+}
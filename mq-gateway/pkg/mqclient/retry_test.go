@@ -0,0 +1,53 @@
+package mqclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDo(t *testing.T) {
+	errBoom := errors.New("boom")
+	fast := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	tests := []struct {
+		name      string
+		policy    RetryPolicy
+		errs      []error
+		wantErr   error
+		wantCalls int
+	}{
+		{"success", fast, []error{nil}, nil, 1},
+		{"permanent error", fast, []error{errBoom}, errBoom, 1},
+		{"retry then success", fast, []error{&RetryableError{Err: errBoom}, nil}, nil, 2},
+		{"attempts exhausted", fast, []error{&RetryableError{Err: errBoom}, &RetryableError{Err: errBoom}, &RetryableError{Err: errBoom}}, errBoom, 3},
+		{"retries disabled", RetryPolicy{MaxAttempts: 1}, []error{&RetryableError{Err: errBoom}}, errBoom, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := tt.policy.Do(context.Background(), func(context.Context) error {
+				calls++
+				return tt.errs[calls-1]
+			})
+			if err != tt.wantErr || calls != tt.wantCalls {
+				t.Fatalf("err = %v after %d calls, want %v after %d", err, calls, tt.wantErr, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDoStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour, MaxBackoff: time.Hour}
+	calls := 0
+	err := p.Do(ctx, func(context.Context) error {
+		calls++
+		cancel()
+		return &RetryableError{Err: context.Canceled}
+	})
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Fatalf("err = %v after %d calls", err, calls)
+	}
+}
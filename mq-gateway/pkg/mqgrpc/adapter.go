@@ -0,0 +1,92 @@
+package mqgrpc
+
+import (
+	"context"
+
+	mq_grpc_api "github.com/jlambert68/MQDockerContainer2/mq-gateway/api/proto/mq_grpc_api"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqclient"
+)
+
+// AsClient returns c as a transport-neutral mqclient.Client. Responses with
+// status "error" are returned as *mqclient.GatewayError.
+func (c *Client) AsClient() mqclient.Client {
+	return neutral{c}
+}
+
+type neutral struct{ c *Client }
+
+func gatewayErr(op, status, msg string) error {
+	if status == "error" {
+		return &mqclient.GatewayError{Op: op, Message: msg}
+	}
+	return nil
+}
+
+func (n neutral) Put(ctx context.Context, queue, message string, opts mqclient.PutOptions) (string, error) {
+	resp, err := n.c.Put(ctx, &mq_grpc_api.PutRequest{Queue: queue, Message: message, ReplyToQueue: opts.ReplyToQueue, IdempotencyKey: opts.IdempotencyKey})
+	if err != nil {
+		return "", err
+	}
+	return resp.GetMsgId(), gatewayErr("put", resp.GetStatus(), resp.GetError())
+}
+
+func (n neutral) Get(ctx context.Context, queue string, opts mqclient.GetOptions) (*mqclient.Message, error) {
+	resp, err := n.c.Get(ctx, &mq_grpc_api.GetRequest{Queue: queue, WaitMs: int32(opts.WaitMs), MaxMsgBytes: int32(opts.MaxMsgBytes), CorrelId: opts.CorrelID})
+	if err != nil {
+		return nil, err
+	}
+	if err := gatewayErr("get", resp.GetStatus(), resp.GetError()); err != nil || resp.GetEmpty() {
+		return nil, err
+	}
+	return &mqclient.Message{Payload: resp.GetMessage(), MsgID: resp.GetMsgId(), CorrelID: resp.GetCorrelId()}, nil
+}
+
+func (n neutral) BrowseFirst(ctx context.Context, queue string, opts mqclient.GetOptions) (*mqclient.Message, string, error) {
+	resp, err := n.c.BrowseFirst(ctx, &mq_grpc_api.BrowseFirstRequest{Queue: queue, WaitMs: int32(opts.WaitMs), MaxMsgBytes: int32(opts.MaxMsgBytes)})
+	if err != nil {
+		return nil, "", err
+	}
+	if err := gatewayErr("browse first", resp.GetStatus(), resp.GetError()); err != nil {
+		return nil, "", err
+	}
+	if resp.GetEmpty() {
+		return nil, resp.GetBrowseId(), nil
+	}
+	return &mqclient.Message{Payload: resp.GetMessage()}, resp.GetBrowseId(), nil
+}
+
+func (n neutral) BrowseNext(ctx context.Context, browseID string, opts mqclient.GetOptions) (*mqclient.Message, error) {
+	resp, err := n.c.BrowseNext(ctx, &mq_grpc_api.BrowseNextRequest{BrowseId: browseID, WaitMs: int32(opts.WaitMs), MaxMsgBytes: int32(opts.MaxMsgBytes)})
+	if err != nil {
+		return nil, err
+	}
+	if err := gatewayErr("browse next", resp.GetStatus(), resp.GetError()); err != nil || resp.GetEmpty() {
+		return nil, err
+	}
+	return &mqclient.Message{Payload: resp.GetMessage()}, nil
+}
+
+func (n neutral) InquireQueue(ctx context.Context, queue string) (*mqclient.QueueInfo, error) {
+	resp, err := n.c.InquireQueue(ctx, &mq_grpc_api.InquireQueueRequest{Queue: queue})
+	if err != nil {
+		return nil, err
+	}
+	if err := gatewayErr("inquire queue", resp.GetStatus(), resp.GetError()); err != nil {
+		return nil, err
+	}
+	return &mqclient.QueueInfo{
+		Queue:           resp.GetQueue(),
+		QueueDesc:       resp.GetQueueDesc(),
+		QueueType:       resp.GetQueueType(),
+		QueueUsage:      resp.GetQueueUsage(),
+		DefPersistence:  resp.GetDefPersistence(),
+		InhibitGet:      resp.GetInhibitGet(),
+		InhibitPut:      resp.GetInhibitPut(),
+		CurrentQDepth:   resp.GetCurrentQDepth(),
+		MaxQDepth:       resp.GetMaxQDepth(),
+		OpenInputCount:  resp.GetOpenInputCount(),
+		OpenOutputCount: resp.GetOpenOutputCount(),
+	}, nil
+}
+
+func (n neutral) Close() error { return n.c.Close() }
@@ -0,0 +1,180 @@
+// Package mqgrpc is a Go client for the gateway's gRPC API.
+package mqgrpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	mq_grpc_api "github.com/jlambert68/MQDockerContainer2/mq-gateway/api/proto/mq_grpc_api"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqclient"
+)
+
+// Config configures a Client.
+type Config struct {
+	// Address is the gateway's gRPC host:port.
+	Address string
+	// TLSConfig enables TLS; nil connects in plaintext.
+	TLSConfig *tls.Config
+	// Credentials are sent as metadata with every call.
+	Credentials mqclient.Credentials
+	// Retry controls retries of throttled and unavailable calls.
+	Retry mqclient.RetryPolicy
+	// DialOptions are appended to the options the client builds.
+	DialOptions []grpc.DialOption
+}
+
+// Client calls the gRPC API. It is safe for concurrent use.
+type Client struct {
+	conn  *grpc.ClientConn
+	api   mq_grpc_api.MqGrpcServicesClient
+	retry mqclient.RetryPolicy
+}
+
+// NewClient creates a client for cfg. The connection is established lazily
+// on the first call.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.Address == "" {
+		return nil, errors.New("mqgrpc: address required")
+	}
+
+	creds := insecure.NewCredentials()
+	if cfg.TLSConfig != nil {
+		creds = credentials.NewTLS(cfg.TLSConfig)
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if md := cfg.Credentials.Headers(); len(md) > 0 {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(metadataInterceptor(md)))
+	}
+	opts = append(opts, cfg.DialOptions...)
+
+	conn, err := grpc.NewClient(cfg.Address, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, api: mq_grpc_api.NewMqGrpcServicesClient(conn), retry: cfg.Retry}, nil
+}
+
+func metadataInterceptor(md map[string]string) grpc.UnaryClientInterceptor {
+	pairs := make([]string, 0, 2*len(md))
+	for k, v := range md {
+		pairs = append(pairs, k, v)
+	}
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(metadata.AppendToOutgoingContext(ctx, pairs...), method, req, reply, cc, opts...)
+	}
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// API returns the generated stub for calls this client does not wrap.
+func (c *Client) API() mq_grpc_api.MqGrpcServicesClient {
+	return c.api
+}
+
+// call runs fn under the retry policy. ResourceExhausted (throttling) is
+// always retried; Unavailable only for idempotent calls, since the gateway may
+// have acted on the request before the connection dropped.
+func call[T any](ctx context.Context, c *Client, idempotent bool, fn func(ctx context.Context) (T, error)) (T, error) {
+	var out T
+	err := c.retry.Do(ctx, func(ctx context.Context) error {
+		var err error
+		out, err = fn(ctx)
+		if err == nil {
+			return nil
+		}
+		st := status.Convert(err)
+		switch {
+		case st.Code() == codes.ResourceExhausted,
+			st.Code() == codes.Unavailable && idempotent:
+			return &mqclient.RetryableError{Err: err, After: retryDelay(st)}
+		}
+		return err
+	})
+	return out, err
+}
+
+func retryDelay(st *status.Status) time.Duration {
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok && ri.GetRetryDelay() != nil {
+			return ri.GetRetryDelay().AsDuration()
+		}
+	}
+	return 0
+}
+
+// Put puts one message. With an idempotency_key the put is retried like a
+// read, since the gateway does not put the message twice.
+func (c *Client) Put(ctx context.Context, req *mq_grpc_api.PutRequest, opts ...grpc.CallOption) (*mq_grpc_api.PutResponse, error) {
+	return call(ctx, c, req.GetIdempotencyKey() != "", func(ctx context.Context) (*mq_grpc_api.PutResponse, error) {
+		return c.api.Put(ctx, req, opts...)
+	})
+}
+
+// Get destructively reads one message.
+func (c *Client) Get(ctx context.Context, req *mq_grpc_api.GetRequest, opts ...grpc.CallOption) (*mq_grpc_api.GetResponse, error) {
+	return call(ctx, c, false, func(ctx context.Context) (*mq_grpc_api.GetResponse, error) {
+		return c.api.Get(ctx, req, opts...)
+	})
+}
+
+// PutGroup puts messages as one MQ message group.
+func (c *Client) PutGroup(ctx context.Context, req *mq_grpc_api.PutGroupRequest, opts ...grpc.CallOption) (*mq_grpc_api.PutGroupResponse, error) {
+	return call(ctx, c, false, func(ctx context.Context) (*mq_grpc_api.PutGroupResponse, error) {
+		return c.api.PutGroup(ctx, req, opts...)
+	})
+}
+
+// GetGroup reads a complete message group.
+func (c *Client) GetGroup(ctx context.Context, req *mq_grpc_api.GetGroupRequest, opts ...grpc.CallOption) (*mq_grpc_api.GetGroupResponse, error) {
+	return call(ctx, c, false, func(ctx context.Context) (*mq_grpc_api.GetGroupResponse, error) {
+		return c.api.GetGroup(ctx, req, opts...)
+	})
+}
+
+// PutBatch puts several messages in one call.
+func (c *Client) PutBatch(ctx context.Context, req *mq_grpc_api.PutBatchRequest, opts ...grpc.CallOption) (*mq_grpc_api.PutBatchResponse, error) {
+	return call(ctx, c, false, func(ctx context.Context) (*mq_grpc_api.PutBatchResponse, error) {
+		return c.api.PutBatch(ctx, req, opts...)
+	})
+}
+
+// GetBatch reads several messages in one call.
+func (c *Client) GetBatch(ctx context.Context, req *mq_grpc_api.GetBatchRequest, opts ...grpc.CallOption) (*mq_grpc_api.GetBatchResponse, error) {
+	return call(ctx, c, false, func(ctx context.Context) (*mq_grpc_api.GetBatchResponse, error) {
+		return c.api.GetBatch(ctx, req, opts...)
+	})
+}
+
+// BrowseFirst opens a browse session.
+func (c *Client) BrowseFirst(ctx context.Context, req *mq_grpc_api.BrowseFirstRequest, opts ...grpc.CallOption) (*mq_grpc_api.BrowseResponse, error) {
+	return call(ctx, c, true, func(ctx context.Context) (*mq_grpc_api.BrowseResponse, error) {
+		return c.api.BrowseFirst(ctx, req, opts...)
+	})
+}
+
+// BrowseNext continues a browse session.
+func (c *Client) BrowseNext(ctx context.Context, req *mq_grpc_api.BrowseNextRequest, opts ...grpc.CallOption) (*mq_grpc_api.BrowseResponse, error) {
+	return call(ctx, c, false, func(ctx context.Context) (*mq_grpc_api.BrowseResponse, error) {
+		return c.api.BrowseNext(ctx, req, opts...)
+	})
+}
+
+// InquireQueue returns queue attributes.
+func (c *Client) InquireQueue(ctx context.Context, req *mq_grpc_api.InquireQueueRequest, opts ...grpc.CallOption) (*mq_grpc_api.InquireQueueResponse, error) {
+	return call(ctx, c, true, func(ctx context.Context) (*mq_grpc_api.InquireQueueResponse, error) {
+		return c.api.InquireQueue(ctx, req, opts...)
+	})
+}
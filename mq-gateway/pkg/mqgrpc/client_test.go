@@ -0,0 +1,96 @@
+package mqgrpc
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	mq_grpc_api "github.com/jlambert68/MQDockerContainer2/mq-gateway/api/proto/mq_grpc_api"
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqclient"
+)
+
+type fakeServer struct {
+	mq_grpc_api.UnimplementedMqGrpcServicesServer
+	calls  atomic.Int32
+	apiKey atomic.Value
+}
+
+func (s *fakeServer) Put(ctx context.Context, req *mq_grpc_api.PutRequest) (*mq_grpc_api.PutResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get("x-api-key"); len(v) > 0 {
+		s.apiKey.Store(v[0])
+	}
+	if s.calls.Add(1) == 1 {
+		if req.GetIdempotencyKey() != "" {
+			return nil, status.Error(codes.Unavailable, "connection reset")
+		}
+		return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	return &mq_grpc_api.PutResponse{Status: "ok", MsgId: "414d51"}, nil
+}
+
+func (s *fakeServer) Get(ctx context.Context, req *mq_grpc_api.GetRequest) (*mq_grpc_api.GetResponse, error) {
+	s.calls.Add(1)
+	return nil, status.Error(codes.Unavailable, "connection reset")
+}
+
+func newTestClient(t *testing.T) (*Client, *fakeServer) {
+	lis := bufconn.Listen(1 << 20)
+	fake := &fakeServer{}
+	srv := grpc.NewServer()
+	mq_grpc_api.RegisterMqGrpcServicesServer(srv, fake)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	c, err := NewClient(Config{
+		Address:     "passthrough:///bufnet",
+		Credentials: mqclient.Credentials{APIKey: "secret"},
+		Retry:       mqclient.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+		DialOptions: []grpc.DialOption{grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		})},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c, fake
+}
+
+func TestPutRetriesResourceExhausted(t *testing.T) {
+	c, fake := newTestClient(t)
+	msgID, err := c.AsClient().Put(context.Background(), "DEV.QUEUE.1", "hi", mqclient.PutOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msgID != "414d51" || fake.calls.Load() != 2 {
+		t.Fatalf("msgID = %q after %d calls", msgID, fake.calls.Load())
+	}
+	if got, _ := fake.apiKey.Load().(string); got != "secret" {
+		t.Fatalf("x-api-key = %q", got)
+	}
+}
+
+func TestGetDoesNotRetryUnavailable(t *testing.T) {
+	c, fake := newTestClient(t)
+	_, err := c.Get(context.Background(), &mq_grpc_api.GetRequest{Queue: "DEV.QUEUE.1"})
+	if status.Code(err) != codes.Unavailable || fake.calls.Load() != 1 {
+		t.Fatalf("err = %v after %d calls", err, fake.calls.Load())
+	}
+}
+
+func TestIdempotentPutRetriesUnavailable(t *testing.T) {
+	c, fake := newTestClient(t)
+	msgID, err := c.AsClient().Put(context.Background(), "DEV.QUEUE.1", "hi", mqclient.PutOptions{IdempotencyKey: "order-42"})
+	if err != nil || msgID != "414d51" || fake.calls.Load() != 2 {
+		t.Fatalf("msgID = %q, err = %v after %d calls", msgID, err, fake.calls.Load())
+	}
+}
@@ -0,0 +1,91 @@
+package mqrest
+
+import (
+	"context"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqclient"
+)
+
+// AsClient returns c as a transport-neutral mqclient.Client. Responses with
+// status "error" are returned as *mqclient.GatewayError.
+func (c *Client) AsClient() mqclient.Client {
+	return neutral{c}
+}
+
+type neutral struct{ c *Client }
+
+func gatewayErr(op, status, msg string) error {
+	if status == "error" {
+		return &mqclient.GatewayError{Op: op, Message: msg}
+	}
+	return nil
+}
+
+func (n neutral) Put(ctx context.Context, queue, message string, opts mqclient.PutOptions) (string, error) {
+	resp, err := n.c.Put(ctx, PutRequest{Queue: queue, Message: message, ReplyToQueue: opts.ReplyToQueue, IdempotencyKey: opts.IdempotencyKey})
+	if err != nil {
+		return "", err
+	}
+	return resp.MsgID, gatewayErr("put", resp.Status, resp.Error)
+}
+
+func (n neutral) Get(ctx context.Context, queue string, opts mqclient.GetOptions) (*mqclient.Message, error) {
+	resp, err := n.c.Get(ctx, GetRequest{Queue: queue, WaitMs: opts.WaitMs, MaxMsgBytes: opts.MaxMsgBytes, CorrelID: opts.CorrelID})
+	if err != nil {
+		return nil, err
+	}
+	if err := gatewayErr("get", resp.Status, resp.Error); err != nil || resp.Empty {
+		return nil, err
+	}
+	return &mqclient.Message{Payload: resp.Message, MsgID: resp.MsgID, CorrelID: resp.CorrelID}, nil
+}
+
+func (n neutral) BrowseFirst(ctx context.Context, queue string, opts mqclient.GetOptions) (*mqclient.Message, string, error) {
+	resp, err := n.c.BrowseFirst(ctx, BrowseFirstRequest{Queue: queue, WaitMs: opts.WaitMs, MaxMsgBytes: opts.MaxMsgBytes})
+	if err != nil {
+		return nil, "", err
+	}
+	if err := gatewayErr("browse first", resp.Status, resp.Error); err != nil {
+		return nil, "", err
+	}
+	if resp.Empty {
+		return nil, resp.BrowseID, nil
+	}
+	return &mqclient.Message{Payload: resp.Message}, resp.BrowseID, nil
+}
+
+func (n neutral) BrowseNext(ctx context.Context, browseID string, opts mqclient.GetOptions) (*mqclient.Message, error) {
+	resp, err := n.c.BrowseNext(ctx, BrowseNextRequest{BrowseID: browseID, WaitMs: opts.WaitMs, MaxMsgBytes: opts.MaxMsgBytes})
+	if err != nil {
+		return nil, err
+	}
+	if err := gatewayErr("browse next", resp.Status, resp.Error); err != nil || resp.Empty {
+		return nil, err
+	}
+	return &mqclient.Message{Payload: resp.Message}, nil
+}
+
+func (n neutral) InquireQueue(ctx context.Context, queue string) (*mqclient.QueueInfo, error) {
+	resp, err := n.c.InquireQueue(ctx, InquireQueueRequest{Queue: queue})
+	if err != nil {
+		return nil, err
+	}
+	if err := gatewayErr("inquire queue", resp.Status, resp.Error); err != nil {
+		return nil, err
+	}
+	return &mqclient.QueueInfo{
+		Queue:           resp.Queue,
+		QueueDesc:       resp.QueueDesc,
+		QueueType:       resp.QueueType,
+		QueueUsage:      resp.QueueUsage,
+		DefPersistence:  resp.DefPersistence,
+		InhibitGet:      resp.InhibitGet,
+		InhibitPut:      resp.InhibitPut,
+		CurrentQDepth:   resp.CurrentQDepth,
+		MaxQDepth:       resp.MaxQDepth,
+		OpenInputCount:  resp.OpenInputCount,
+		OpenOutputCount: resp.OpenOutputCount,
+	}, nil
+}
+
+func (n neutral) Close() error { return n.c.Close() }
@@ -0,0 +1,198 @@
+// Package mqrest is a Go client for the gateway's REST API.
+package mqrest
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqclient"
+)
+
+// Config configures a Client.
+type Config struct {
+	// BaseURL is the gateway address, e.g. "https://mq-gateway:8080".
+	BaseURL string
+	// Timeout bounds each HTTP attempt; zero means no timeout beyond ctx.
+	Timeout time.Duration
+	// TLSConfig is used for https base URLs (client certificates, private CAs).
+	TLSConfig *tls.Config
+	// Credentials are sent with every request.
+	Credentials mqclient.Credentials
+	// Retry controls retries of throttled and unavailable requests.
+	Retry mqclient.RetryPolicy
+	// HTTPClient overrides the client built from Timeout and TLSConfig.
+	HTTPClient *http.Client
+}
+
+// Client calls the REST API. It is safe for concurrent use.
+type Client struct {
+	base  string
+	http  *http.Client
+	creds mqclient.Credentials
+	retry mqclient.RetryPolicy
+}
+
+// NewClient returns a client for cfg.
+func NewClient(cfg Config) *Client {
+	hc := cfg.HTTPClient
+	if hc == nil {
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		if cfg.TLSConfig != nil {
+			tr.TLSClientConfig = cfg.TLSConfig
+		}
+		hc = &http.Client{Timeout: cfg.Timeout, Transport: tr}
+	}
+	return &Client{
+		base:  strings.TrimRight(cfg.BaseURL, "/"),
+		http:  hc,
+		creds: cfg.Credentials,
+		retry: cfg.Retry,
+	}
+}
+
+// Close releases idle connections.
+func (c *Client) Close() error {
+	c.http.CloseIdleConnections()
+	return nil
+}
+
+// HTTPError is a non-2xx response that carried no gateway result.
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, strings.TrimSpace(e.Body))
+}
+
+// post sends in as JSON to path and decodes the response into out. Requests
+// are retried when the gateway throttles (429), is unavailable (503) or the
+// connection fails; non-idempotent operations only retry failures where the
+// request cannot have reached the gateway. That rules out 503, which a proxy
+// in front of the gateway may answer after the gateway processed the request.
+func (c *Client) post(ctx context.Context, path string, idempotent bool, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return c.retry.Do(ctx, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.base+path, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range c.creds.Headers() {
+			req.Header.Set(k, v)
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			if ctx.Err() == nil && (idempotent || isDialError(err)) {
+				return &mqclient.RetryableError{Err: err}
+			}
+			return err
+		}
+		defer resp.Body.Close()
+		raw, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			return json.Unmarshal(raw, out)
+		case resp.StatusCode == http.StatusBadGateway && json.Unmarshal(raw, out) == nil:
+			// MQ failures come back as 502 with status "error" in the body.
+			return nil
+		}
+		herr := &HTTPError{StatusCode: resp.StatusCode, Body: string(raw)}
+		if resp.StatusCode == http.StatusTooManyRequests || (idempotent && resp.StatusCode == http.StatusServiceUnavailable) {
+			return &mqclient.RetryableError{Err: herr, After: retryAfter(resp.Header.Get("Retry-After"))}
+		}
+		return herr
+	})
+}
+
+func isDialError(err error) bool {
+	var op *net.OpError
+	return errors.As(err, &op) && op.Op == "dial"
+}
+
+func retryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// Put puts one message. With an IdempotencyKey the put is retried like a
+// read, since the gateway does not put the message twice.
+func (c *Client) Put(ctx context.Context, req PutRequest) (*PutResponse, error) {
+	var out PutResponse
+	return &out, c.post(ctx, "/put", req.IdempotencyKey != "", req, &out)
+}
+
+// Get destructively reads one message.
+func (c *Client) Get(ctx context.Context, req GetRequest) (*GetResponse, error) {
+	var out GetResponse
+	return &out, c.post(ctx, "/get", false, req, &out)
+}
+
+// PutGroup puts messages as one MQ message group.
+func (c *Client) PutGroup(ctx context.Context, req PutGroupRequest) (*PutGroupResponse, error) {
+	var out PutGroupResponse
+	return &out, c.post(ctx, "/put/group", false, req, &out)
+}
+
+// GetGroup reads a complete message group.
+func (c *Client) GetGroup(ctx context.Context, req GetGroupRequest) (*GetGroupResponse, error) {
+	var out GetGroupResponse
+	return &out, c.post(ctx, "/get/group", false, req, &out)
+}
+
+// PutBatch puts several messages in one call.
+func (c *Client) PutBatch(ctx context.Context, req PutBatchRequest) (*PutBatchResponse, error) {
+	var out PutBatchResponse
+	return &out, c.post(ctx, "/put/batch", false, req, &out)
+}
+
+// GetBatch reads up to req.MaxMessages messages in one call.
+func (c *Client) GetBatch(ctx context.Context, req GetBatchRequest) (*GetBatchResponse, error) {
+	var out GetBatchResponse
+	return &out, c.post(ctx, "/get/batch", false, req, &out)
+}
+
+// BrowseFirst opens a browse session.
+func (c *Client) BrowseFirst(ctx context.Context, req BrowseFirstRequest) (*BrowseResponse, error) {
+	var out BrowseResponse
+	return &out, c.post(ctx, "/browse/first", true, req, &out)
+}
+
+// BrowseNext continues a browse session.
+func (c *Client) BrowseNext(ctx context.Context, req BrowseNextRequest) (*BrowseResponse, error) {
+	var out BrowseResponse
+	return &out, c.post(ctx, "/browse/next", false, req, &out)
+}
+
+// InquireQueue returns queue attributes.
+func (c *Client) InquireQueue(ctx context.Context, req InquireQueueRequest) (*InquireQueueResponse, error) {
+	var out InquireQueueResponse
+	return &out, c.post(ctx, "/inquire/queue", true, req, &out)
+}
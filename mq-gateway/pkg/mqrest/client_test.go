@@ -0,0 +1,110 @@
+package mqrest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jlambert68/MQDockerContainer2/mq-gateway/pkg/mqclient"
+)
+
+var fastRetry = mqclient.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+func TestClientRetriesThrottledRequests(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-API-Key"); got != "secret" {
+			t.Errorf("X-API-Key = %q", got)
+		}
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(PutResponse{Status: "ok", MsgID: "414d51"})
+	}))
+	defer srv.Close()
+
+	c := NewClient(Config{BaseURL: srv.URL + "/", Credentials: mqclient.Credentials{APIKey: "secret"}, Retry: fastRetry})
+	resp, err := c.Put(context.Background(), PutRequest{Queue: "DEV.QUEUE.1", Message: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.MsgID != "414d51" || calls.Load() != 2 {
+		t.Fatalf("resp = %+v after %d calls", resp, calls.Load())
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		wantCalls int32
+		wantHTTP  int
+		wantGWErr bool
+	}{
+		{"mq failure is a gateway error", http.StatusBadGateway, `{"status":"error","error":"MQRC_UNKNOWN_OBJECT_NAME"}`, 1, 0, true},
+		{"bad request is not retried", http.StatusBadRequest, "queue required", 1, http.StatusBadRequest, false},
+		{"unavailable is retried", http.StatusServiceUnavailable, "draining", 3, http.StatusServiceUnavailable, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			c := NewClient(Config{BaseURL: srv.URL, Retry: fastRetry}).AsClient()
+			_, err := c.InquireQueue(context.Background(), "DEV.QUEUE.1")
+
+			var herr *HTTPError
+			var gwErr *mqclient.GatewayError
+			switch {
+			case calls.Load() != tt.wantCalls:
+				t.Errorf("calls = %d, want %d", calls.Load(), tt.wantCalls)
+			case tt.wantGWErr && !errors.As(err, &gwErr):
+				t.Errorf("err = %v, want GatewayError", err)
+			case tt.wantHTTP != 0 && (!errors.As(err, &herr) || herr.StatusCode != tt.wantHTTP):
+				t.Errorf("err = %v, want HTTP %d", err, tt.wantHTTP)
+			}
+		})
+	}
+}
+
+func TestClientDoesNotRetryUnavailableGet(t *testing.T) {
+	// A 503 may come from a proxy after the gateway got the message, so a
+	// destructive get is not sent again.
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "upstream timed out", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(Config{BaseURL: srv.URL, Retry: fastRetry}).Get(context.Background(), GetRequest{Queue: "DEV.QUEUE.1"})
+	var herr *HTTPError
+	if !errors.As(err, &herr) || herr.StatusCode != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Fatalf("err = %v after %d calls, want one HTTP 503", err, calls.Load())
+	}
+}
+
+func TestAsClientEmptyQueue(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(GetResponse{Status: "ok", Empty: true})
+	}))
+	defer srv.Close()
+
+	msg, err := NewClient(Config{BaseURL: srv.URL}).AsClient().Get(context.Background(), "DEV.QUEUE.1", mqclient.GetOptions{})
+	if msg != nil || err != nil {
+		t.Fatalf("Get = %v, %v; want nil, nil", msg, err)
+	}
+}
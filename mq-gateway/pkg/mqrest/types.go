@@ -0,0 +1,190 @@
+package mqrest
+
+// Request and response bodies of the REST API.
+
+type PutRequest struct {
+	// Target queue name.
+	Queue string `json:"queue"`
+	// Payload to put.
+	Message string `json:"message"`
+	// Optional MQMD grouping/segmentation fields; group_id is hex.
+	GroupID      string `json:"group_id,omitempty"`
+	MsgSeqNumber int32  `json:"msg_seq_number,omitempty"`
+	Offset       int32  `json:"offset,omitempty"`
+	MsgFlags     int32  `json:"msg_flags,omitempty"`
+	// Optional reply queue; marks the message as a request.
+	ReplyToQueue string `json:"reply_to_queue,omitempty"`
+	// Optional deduplication key; a put with one is retried on network errors.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+type PutResponse struct {
+	Status string `json:"status"`
+	MsgID  string `json:"msg_id,omitempty"`
+	// Replayed is true when the gateway answered from an earlier put with
+	// the same idempotency key.
+	Replayed bool   `json:"replayed,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type GetRequest struct {
+	// Target queue name.
+	Queue string `json:"queue"`
+	// Wait interval in milliseconds.
+	WaitMs int `json:"wait_ms"`
+	// Max message size in bytes.
+	MaxMsgBytes int `json:"max_msg_bytes"`
+	// Reassemble segmented messages into one logical message.
+	CompleteMsg bool `json:"complete_msg,omitempty"`
+	// Only get the message with this CorrelId (hex), e.g. a reply.
+	CorrelID string `json:"correl_id,omitempty"`
+}
+
+type GetResponse struct {
+	Status       string `json:"status"`
+	Message      string `json:"message,omitempty"`
+	Empty        bool   `json:"empty"`
+	MsgID        string `json:"msg_id,omitempty"`
+	CorrelID     string `json:"correl_id,omitempty"`
+	GroupID      string `json:"group_id,omitempty"`
+	MsgSeqNumber int32  `json:"msg_seq_number,omitempty"`
+	Offset       int32  `json:"offset,omitempty"`
+	MsgFlags     int32  `json:"msg_flags,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+type PutGroupRequest struct {
+	// Target queue name.
+	Queue string `json:"queue"`
+	// Payloads put in order; the last one is flagged last-in-group.
+	Messages []string `json:"messages"`
+}
+
+type PutGroupResponse struct {
+	Status  string `json:"status"`
+	GroupID string `json:"group_id,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type GetGroupRequest struct {
+	// Target queue name.
+	Queue string `json:"queue"`
+	// Wait interval in milliseconds.
+	WaitMs int `json:"wait_ms"`
+	// Max message size in bytes (per message).
+	MaxMsgBytes int `json:"max_msg_bytes"`
+}
+
+type GroupMessage struct {
+	Message      string `json:"message"`
+	MsgID        string `json:"msg_id,omitempty"`
+	MsgSeqNumber int32  `json:"msg_seq_number"`
+	Offset       int32  `json:"offset,omitempty"`
+	MsgFlags     int32  `json:"msg_flags,omitempty"`
+}
+
+type GetGroupResponse struct {
+	Status   string         `json:"status"`
+	GroupID  string         `json:"group_id,omitempty"`
+	Messages []GroupMessage `json:"messages,omitempty"`
+	Empty    bool           `json:"empty"`
+	Error    string         `json:"error,omitempty"`
+}
+
+type PutBatchRequest struct {
+	// Target queue name.
+	Queue string `json:"queue"`
+	// Payloads to put, in order.
+	Messages []string `json:"messages"`
+	// Put all messages in one unit of work (all-or-nothing).
+	Syncpoint bool `json:"syncpoint"`
+}
+
+type PutBatchResult struct {
+	Status string `json:"status"`
+	MsgID  string `json:"msg_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type PutBatchResponse struct {
+	Status  string           `json:"status"`
+	Results []PutBatchResult `json:"results,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+type GetBatchRequest struct {
+	// Target queue name.
+	Queue string `json:"queue"`
+	// Maximum number of messages to receive.
+	MaxMessages int `json:"max_messages"`
+	// Wait interval in milliseconds for the first message.
+	WaitMs int `json:"wait_ms"`
+	// Max message size in bytes (per message).
+	MaxMsgBytes int `json:"max_msg_bytes"`
+	// Get all messages in one unit of work (all-or-nothing).
+	Syncpoint bool `json:"syncpoint"`
+}
+
+type GetBatchResult struct {
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+	MsgID    string `json:"msg_id,omitempty"`
+	CorrelID string `json:"correl_id,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type GetBatchResponse struct {
+	Status  string           `json:"status"`
+	Results []GetBatchResult `json:"results,omitempty"`
+	Empty   bool             `json:"empty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+type BrowseFirstRequest struct {
+	// Target queue name.
+	Queue string `json:"queue"`
+	// Wait interval in milliseconds.
+	WaitMs int `json:"wait_ms"`
+	// Max message size in bytes.
+	MaxMsgBytes int `json:"max_msg_bytes"`
+}
+
+type BrowseNextRequest struct {
+	// Browse session token returned from /browse/first.
+	BrowseID string `json:"browse_id"`
+	// Wait interval in milliseconds.
+	WaitMs int `json:"wait_ms"`
+	// Max message size in bytes.
+	MaxMsgBytes int `json:"max_msg_bytes"`
+}
+
+type BrowseResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	Empty   bool   `json:"empty"`
+	// BrowseID is only set for BrowseFirst responses.
+	BrowseID string `json:"browse_id,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type InquireQueueRequest struct {
+	// Target queue name.
+	Queue string `json:"queue"`
+}
+
+type InquireQueueResponse struct {
+	Status string `json:"status"`
+	// Queue is the resolved queue name (may be normalized by MQ).
+	Queue           string `json:"queue"`
+	QueueDesc       string `json:"queue_desc"`
+	QueueType       int32  `json:"queue_type"`
+	QueueUsage      int32  `json:"queue_usage"`
+	DefPersistence  int32  `json:"def_persistence"`
+	InhibitGet      int32  `json:"inhibit_get"`
+	InhibitPut      int32  `json:"inhibit_put"`
+	CurrentQDepth   int32  `json:"current_q_depth"`
+	MaxQDepth       int32  `json:"max_q_depth"`
+	OpenInputCount  int32  `json:"open_input_count"`
+	OpenOutputCount int32  `json:"open_output_count"`
+	Error           string `json:"error,omitempty"`
+}